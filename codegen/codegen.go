@@ -0,0 +1,289 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+// Package codegen writes a complete, importable Go package from a genome,
+// so that an evolved model can be refreshed in a service with go generate
+// instead of copying the output of genome.Write by hand.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/gmlewis/gep/v2/genome"
+	"github.com/gmlewis/gep/v2/grammars"
+)
+
+// modelFunc is the name of the function written by the Go grammars.
+const modelFunc = "gepModel"
+
+type config struct {
+	pkg        string
+	function   string
+	inputNames []string
+}
+
+// Option represents an option that can modify the generated package.
+type Option func(c *config)
+
+// WithPackage sets the name of the generated package (default "model").
+func WithPackage(name string) Option {
+	return func(c *config) {
+		c.pkg = name
+	}
+}
+
+// WithFunction sets the name of the exported model function (default "Predict").
+func WithFunction(name string) Option {
+	return func(c *config) {
+		c.function = name
+	}
+}
+
+// WithInputNames generates an input struct type named after the model
+// function (such as PredictInput) with one field for each input d[i]
+// named after names[i], such as the column names of dataset.InputNames.
+// The exported function then takes the struct instead of a slice.
+func WithInputNames(names []string) Option {
+	return func(c *config) {
+		c.inputNames = names
+	}
+}
+
+// Write writes the source code of a Go package that evaluates the genome
+// using the provided Go grammar. The exported model function is documented
+// with the Karva expression and the score of the genome.
+func Write(w io.Writer, g *genome.Genome, grammar *grammars.Grammar, opts ...Option) error {
+	c := &config{pkg: "model", function: "Predict"}
+	for _, f := range opts {
+		f(c)
+	}
+	if !token.IsIdentifier(c.pkg) {
+		return fmt.Errorf("codegen: invalid package name %q", c.pkg)
+	}
+	if !token.IsIdentifier(c.function) || !token.IsExported(c.function) {
+		return fmt.Errorf("codegen: function name %q is not exported", c.function)
+	}
+	if grammar.Ext != "go" {
+		return fmt.Errorf("codegen: grammar %q does not generate Go code", grammar.Name)
+	}
+	if len(c.inputNames) > 0 {
+		if inputs := g.Complexity().Inputs; len(inputs) > 0 && inputs[len(inputs)-1] >= len(c.inputNames) {
+			return fmt.Errorf("codegen: the genome uses d[%v], but there are only %v input names", inputs[len(inputs)-1], len(c.inputNames))
+		}
+	}
+
+	src, err := g.Generate(grammar)
+	if err != nil {
+		return fmt.Errorf("codegen: %v", err)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return fmt.Errorf("codegen: unable to parse generated code: %v", err)
+	}
+	removeUnusedImports(f)
+	model := findFunc(f, modelFunc)
+	if model == nil || len(model.Type.Params.List) != 1 || len(model.Type.Params.List[0].Names) != 1 {
+		return fmt.Errorf("codegen: unable to find func %v(d []T) in the generated code", modelFunc)
+	}
+	param := model.Type.Params.List[0]
+	results := types.ExprString(model.Type.Results.List[0].Type)
+
+	name := c.function
+	if len(c.inputNames) > 0 {
+		name = unexported(c.function)
+		if token.IsKeyword(name) {
+			name = "gep" + c.function
+		}
+		if findFunc(f, name) != nil {
+			return fmt.Errorf("codegen: function name %q conflicts with the generated code", c.function)
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == modelFunc {
+			id.Name = name
+		}
+		return true
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by github.com/gmlewis/gep/v2/codegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "// Package %v evaluates a model evolved by gene expression programming.\n", c.pkg)
+	fmt.Fprintf(&buf, "package %v\n", c.pkg)
+
+	doc := docComment(c.function, g)
+	for _, decl := range f.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			buf.WriteString("\n")
+			if err := printer.Fprint(&buf, fset, decl); err != nil {
+				return err
+			}
+			buf.WriteString("\n")
+		}
+	}
+	if len(c.inputNames) > 0 {
+		arrayType, ok := param.Type.(*ast.ArrayType)
+		if !ok {
+			return fmt.Errorf("codegen: %v takes %v, want a slice", modelFunc, types.ExprString(param.Type))
+		}
+		writeInput(&buf, c, types.ExprString(arrayType.Elt), types.ExprString(param.Type), results, name, doc)
+	}
+	for _, decl := range f.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			continue
+		}
+		buf.WriteString("\n")
+		if decl == ast.Decl(model) && len(c.inputNames) == 0 {
+			buf.WriteString(doc)
+		}
+		if err := printer.Fprint(&buf, fset, decl); err != nil {
+			return err
+		}
+		buf.WriteString("\n")
+	}
+
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("codegen: %v", err)
+	}
+	_, err = w.Write(code)
+	return err
+}
+
+// WriteFile writes the Go package source for the genome to the named
+// file, as from a go:generate directive.
+func WriteFile(path string, g *genome.Genome, grammar *grammars.Grammar, opts ...Option) error {
+	var buf bytes.Buffer
+	if err := Write(&buf, g, grammar, opts...); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+func findFunc(f *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range f.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == name {
+			return fd
+		}
+	}
+	return nil
+}
+
+// removeUnusedImports removes the imports that the code does not use,
+// such as "math" from the header of the Go Math grammar when the model
+// calls no math function.
+func removeUnusedImports(f *ast.File) {
+	used := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	var decls []ast.Decl
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			decls = append(decls, decl)
+			continue
+		}
+		var specs []ast.Spec
+		for _, spec := range gd.Specs {
+			is := spec.(*ast.ImportSpec)
+			name := path.Base(strings.Trim(is.Path.Value, "\"`"))
+			if is.Name != nil {
+				name = is.Name.Name
+			}
+			if name == "_" || name == "." || used[name] {
+				specs = append(specs, spec)
+			}
+		}
+		if len(specs) > 0 {
+			gd.Specs = specs
+			decls = append(decls, gd)
+		}
+	}
+	f.Decls = decls
+}
+
+// docComment returns the doc comment of the exported model function.
+func docComment(function string, g *genome.Genome) string {
+	karva := make([]string, len(g.Genes))
+	for i, gene := range g.Genes {
+		karva[i] = gene.String()
+	}
+	return fmt.Sprintf("// %v evaluates the model.\n//\n// Karva: %v\n// Score: %v\n",
+		function, strings.Join(karva, "|"+g.LinkFunc+"|"), g.Score)
+}
+
+// writeInput writes the input struct type and the exported function
+// that passes its fields to the model function.
+func writeInput(buf *bytes.Buffer, c *config, elem, sliceType, results, model, doc string) {
+	inputType := c.function + "Input"
+	fields := fieldNames(c.inputNames)
+
+	fmt.Fprintf(buf, "\n// %v holds the inputs of %v.\ntype %v struct {\n", inputType, c.function, inputType)
+	for i, field := range fields {
+		fmt.Fprintf(buf, "\t%v %v // d[%v]: %v\n", field, elem, i, c.inputNames[i])
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString(doc)
+	fmt.Fprintf(buf, "func %v(in %v) %v {\n", c.function, inputType, results)
+	args := make([]string, len(fields))
+	for i, field := range fields {
+		args[i] = "in." + field
+	}
+	fmt.Fprintf(buf, "\treturn %v(%v{%v})\n}\n", model, sliceType, strings.Join(args, ", "))
+}
+
+// fieldNames converts the input names to unique exported Go identifiers,
+// such as "sepal length (cm)" to "SepalLengthCm".
+func fieldNames(names []string) []string {
+	seen := map[string]bool{}
+	result := make([]string, len(names))
+	for i, name := range names {
+		var b strings.Builder
+		for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			rs := []rune(word)
+			b.WriteString(string(unicode.ToUpper(rs[0])) + string(rs[1:]))
+		}
+		field := b.String()
+		if field == "" {
+			field = fmt.Sprintf("D%v", i)
+		} else if !unicode.IsLetter([]rune(field)[0]) || !token.IsExported(field) {
+			field = "X" + field
+		}
+		for base, n := field, 2; seen[field]; n++ {
+			field = fmt.Sprintf("%v%v", base, n)
+		}
+		seen[field] = true
+		result[i] = field
+	}
+	return result
+}
+
+// unexported returns the name with its first letter in lower case.
+func unexported(name string) string {
+	rs := []rune(name)
+	rs[0] = unicode.ToLower(rs[0])
+	return string(rs)
+}
@@ -0,0 +1,243 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package codegen
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+	"github.com/gmlewis/gep/v2/gene"
+	"github.com/gmlewis/gep/v2/genome"
+	"github.com/gmlewis/gep/v2/grammars"
+)
+
+func mathGenome() *genome.Genome {
+	g1 := gene.New("+.*.Sqrt.d0.d1.d2.d0", functions.Float64)
+	g2 := gene.New("Max2.d1.c0.d0.d1", functions.Float64)
+	g2.Constants = []float64{1.5}
+	gn := genome.New([]*gene.Gene{g1, g2}, "*")
+	gn.Score = 987.5
+	return gn
+}
+
+func TestWriteWithInputNames(t *testing.T) {
+	want := `// Code generated by github.com/gmlewis/gep/v2/codegen. DO NOT EDIT.
+
+// Package iris evaluates a model evolved by gene expression programming.
+package iris
+
+import (
+	"math"
+)
+
+// PetalInput holds the inputs of Petal.
+type PetalInput struct {
+	SepalLengthCm float64 // d[0]: sepal length (cm)
+	SepalWidth    float64 // d[1]: sepal_width
+	X2nd          float64 // d[2]: 2nd
+}
+
+// Petal evaluates the model.
+//
+// Karva: +.*.Sqrt.d0.d1.d2.d0|*|Max2.d1.c0(1.5).d0.d1
+// Score: 987.5
+func Petal(in PetalInput) float64 {
+	return petal([]float64{in.SepalLengthCm, in.SepalWidth, in.X2nd})
+}
+
+func petal(d []float64) float64 {
+	var y float64
+
+	y = ((d[0] * d[1]) + math.Sqrt(d[2]))
+	y *= math.Max(d[1], 1.5)
+
+	return y
+}
+`
+
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+	var buf bytes.Buffer
+	names := []string{"sepal length (cm)", "sepal_width", "2nd"}
+	if err := Write(&buf, mathGenome(), grammar, WithPackage("iris"), WithFunction("Petal"), WithInputNames(names)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("Write got:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestWriteBool(t *testing.T) {
+	want := `// Code generated by github.com/gmlewis/gep/v2/codegen. DO NOT EDIT.
+
+// Package model evaluates a model evolved by gene expression programming.
+package model
+
+// Predict evaluates the model.
+//
+// Karva: Nand.Nand.d0.d1.d1.d0.d1
+// Score: 0
+func Predict(d []bool) bool {
+	var y bool
+
+	y = gepNand(gepNand(d[1], d[1]), d[0])
+
+	return y
+}
+
+func gepNand(x, y bool) bool {
+	return (!(x && y))
+}
+`
+
+	g1 := gene.New("Nand.Nand.d0.d1.d1.d0.d1", functions.Bool)
+	gn := genome.New([]*gene.Gene{g1}, "Nand")
+	grammar, err := grammars.LoadGoBooleanNandGatesGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoBooleanNandGatesGrammar(): %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, gn, grammar); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("Write got:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "model.go")
+	if err := WriteFile(path, mathGenome(), grammar); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	if err := Write(&want, mathGenome(), grammar); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if string(got) != want.String() {
+		t.Errorf("WriteFile got:\n%s\nwant:\n%v", got, want.String())
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	goMath, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+	pythonMath, err := grammars.LoadPythonMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadPythonMathGrammar(): %v", err)
+	}
+
+	unknownLink := mathGenome()
+	unknownLink.LinkFunc = "Bogus"
+
+	tests := []struct {
+		name    string
+		genome  *genome.Genome
+		grammar *grammars.Grammar
+		opts    []Option
+	}{
+		{"invalid package", mathGenome(), goMath, []Option{WithPackage("my-model")}},
+		{"unexported function", mathGenome(), goMath, []Option{WithFunction("predict")}},
+		{"invalid function", mathGenome(), goMath, []Option{WithFunction("Pre dict")}},
+		{"python grammar", mathGenome(), pythonMath, nil},
+		{"missing input names", mathGenome(), goMath, []Option{WithInputNames([]string{"a", "b"})}},
+		{"unknown linking function", unknownLink, goMath, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.genome, tt.grammar, tt.opts...); err == nil {
+				t.Errorf("Write: expected error, got:\n%v", buf.String())
+			}
+		})
+	}
+}
+
+func TestWriteTypeChecks(t *testing.T) {
+	goMath, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+	goBool, err := grammars.LoadGoBooleanAllGatesGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoBooleanAllGatesGrammar(): %v", err)
+	}
+	// A model that calls no math function must not import "math".
+	noMath := genome.New([]*gene.Gene{gene.New("+.d0.d1", functions.Float64)}, "+")
+	boolModel := genome.New([]*gene.Gene{gene.New("Xor.d0.d1", functions.Bool), gene.New("Not.d1", functions.Bool)}, "And")
+
+	tests := []struct {
+		name    string
+		genome  *genome.Genome
+		grammar *grammars.Grammar
+		opts    []Option
+	}{
+		{"math", mathGenome(), goMath, nil},
+		{"math with input names", mathGenome(), goMath, []Option{WithInputNames([]string{"a", "b", "c"})}},
+		{"no math calls", noMath, goMath, nil},
+		{"no math calls with input names", noMath, goMath, []Option{WithInputNames([]string{"a", "b"})}},
+		{"bool", boolModel, goBool, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.genome, tt.grammar, tt.opts...); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, "model.go", buf.Bytes(), 0)
+			if err != nil {
+				t.Fatalf("ParseFile: %v\n%v", err, buf.String())
+			}
+			conf := types.Config{Importer: importer.Default()}
+			if _, err := conf.Check("model", fset, []*ast.File{f}, nil); err != nil {
+				t.Errorf("type-checking the generated package: %v\n%v", err, buf.String())
+			}
+		})
+	}
+}
+
+func TestFieldNames(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  []string
+	}{
+		{[]string{"age", "Height (cm)", "num_children"}, []string{"Age", "HeightCm", "NumChildren"}},
+		{[]string{"x", "X", "x"}, []string{"X", "X2", "X3"}},
+		{[]string{"1st", "", "été"}, []string{"X1st", "D1", "Été"}},
+	}
+
+	for _, tt := range tests {
+		got := fieldNames(tt.names)
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("fieldNames(%q) = %q, want %q", tt.names, got, tt.want)
+				break
+			}
+		}
+	}
+}
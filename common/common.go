@@ -31,3 +31,74 @@ type Space struct {
 	// Subspaces for Tuple spaces.
 	Subspaces []*Space `json:"subspaces"`
 }
+
+// ResetOption represents an option that can modify the Reset of an environment.
+type ResetOption func(o *ResetOptions)
+
+// ResetOptions holds the options passed to the Reset of an environment.
+type ResetOptions struct {
+	// Seed, if not nil, re-seeds the random number generator of the
+	// environment so that the episodes that follow can be replayed.
+	Seed *int64
+
+	// Options holds additional environment-specific options,
+	// such as "low" and "high" for the classic control environments.
+	Options map[string]any
+}
+
+// WithSeed re-seeds the random number generator of the environment.
+// Identical seeds result in identical episodes for identical actions.
+func WithSeed(seed int64) ResetOption {
+	return func(o *ResetOptions) {
+		o.Seed = &seed
+	}
+}
+
+// WithOptions passes additional environment-specific options to Reset.
+func WithOptions(options map[string]any) ResetOption {
+	return func(o *ResetOptions) {
+		o.Options = options
+	}
+}
+
+// NewResetOptions returns the ResetOptions resulting from opts.
+func NewResetOptions(opts ...ResetOption) *ResetOptions {
+	o := &ResetOptions{}
+	for _, f := range opts {
+		f(o)
+	}
+	return o
+}
+
+// Float64 returns the numeric option called name as a float64, so that
+// both 0 and 0.0 may be passed, or defaultValue if it was not provided
+// or is not a number.
+func (o *ResetOptions) Float64(name string, defaultValue float64) float64 {
+	switch v := o.Options[name].(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int8:
+		return float64(v)
+	case int16:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint8:
+		return float64(v)
+	case uint16:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	}
+	return defaultValue
+}
@@ -0,0 +1,37 @@
+package common
+
+import "testing"
+
+func TestResetOptionsFloat64(t *testing.T) {
+	o := NewResetOptions(WithOptions(map[string]any{
+		"float64": -0.5,
+		"float32": float32(0.25),
+		"int":     0,
+		"int64":   int64(-3),
+		"uint8":   uint8(7),
+		"string":  "1.5",
+	}))
+
+	tests := []struct {
+		name string
+		want float64
+	}{
+		{"float64", -0.5},
+		{"float32", 0.25},
+		{"int", 0},
+		{"int64", -3},
+		{"uint8", 7},
+		{"string", 42},
+		{"missing", 42},
+	}
+
+	for _, tt := range tests {
+		if got := o.Float64(tt.name, 42); got != tt.want {
+			t.Errorf("Float64(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if got := NewResetOptions().Float64("low", -0.1); got != -0.1 {
+		t.Errorf("Float64 without options = %v, want -0.1", got)
+	}
+}
@@ -0,0 +1,571 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+// Package dataset loads fitness cases from CSV or TSV files into named,
+// typed columns that can be fed directly to the genome evaluators.
+//
+// Every column except the target becomes an input to the genome, so
+// the first input column is d0, the second is d1, and so on.
+package dataset
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gmlewis/gep/v2/transform"
+)
+
+// ColumnType is the type of the values in a column.
+type ColumnType int
+
+const (
+	// Auto means that the column type is inferred from its values.
+	Auto ColumnType = iota
+	// Bool columns contain values accepted by strconv.ParseBool.
+	Bool
+	// Int columns contain integers.
+	Int
+	// Float64 columns contain floating-point numbers.
+	Float64
+	// Categorical columns contain arbitrary strings (nominal values).
+	Categorical
+)
+
+// String returns the name of the column type.
+func (t ColumnType) String() string {
+	switch t {
+	case Auto:
+		return "Auto"
+	case Bool:
+		return "Bool"
+	case Int:
+		return "Int"
+	case Float64:
+		return "Float64"
+	case Categorical:
+		return "Categorical"
+	default:
+		return fmt.Sprintf("ColumnType(%d)", int(t))
+	}
+}
+
+// MissingStrategy determines how missing values are handled.
+type MissingStrategy int
+
+const (
+	// MissingMean replaces missing values in numeric columns by the mean
+	// of the column (rounded for Int columns) and in Bool and Categorical
+	// columns by the most frequent value.
+	MissingMean MissingStrategy = iota
+	// MissingZero replaces missing values by the zero value of the column
+	// (0, false, or the first category).
+	MissingZero
+	// MissingDrop drops every row that contains a missing value.
+	MissingDrop
+)
+
+// ErrType is returned when the columns can not be converted to the requested type.
+var ErrType = errors.New("incompatible column type")
+
+// Column is a single named column of a dataset.
+// Only the slice matching Type is populated.
+type Column struct {
+	Name string
+	Type ColumnType
+
+	Bools   []bool
+	Ints    []int
+	Floats  []float64
+	Strings []string
+
+	// Categories lists the distinct values of a Categorical column in
+	// order of first appearance. The ordinal encoding of a value is its
+	// index in Categories.
+	Categories []string
+	// Encoding, if set, maps the values of a Categorical column to the
+	// numbers returned by Float64 instead of their ordinal encoding.
+	// See EncodeCategorical.
+	Encoding *transform.Categories
+	// Missing records which values were missing in the original data
+	// and have been replaced according to the MissingStrategy.
+	Missing []bool
+}
+
+// Len returns the number of values in the column.
+func (c *Column) Len() int {
+	return len(c.Missing)
+}
+
+// Float64 returns the i-th value of the column as a float64.
+// Bools are 0 or 1 and categories use their Encoding (ordinal by default).
+func (c *Column) Float64(i int) float64 {
+	switch c.Type {
+	case Bool:
+		if c.Bools[i] {
+			return 1
+		}
+		return 0
+	case Int:
+		return float64(c.Ints[i])
+	case Categorical:
+		if c.Encoding != nil {
+			return c.Encoding.Encode(c.Strings[i])
+		}
+		return float64(c.categoryIndex(c.Strings[i]))
+	default:
+		return c.Floats[i]
+	}
+}
+
+// Int returns the i-th value of the column as an int.
+// It returns ErrType for Float64 columns.
+func (c *Column) Int(i int) (int, error) {
+	switch c.Type {
+	case Bool:
+		if c.Bools[i] {
+			return 1, nil
+		}
+		return 0, nil
+	case Int:
+		return c.Ints[i], nil
+	case Categorical:
+		return c.categoryIndex(c.Strings[i]), nil
+	default:
+		return 0, fmt.Errorf("column %q of type %v: %w", c.Name, c.Type, ErrType)
+	}
+}
+
+func (c *Column) categoryIndex(s string) int {
+	for i, v := range c.Categories {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// Dataset is a collection of input columns and a single target column.
+type Dataset struct {
+	// Inputs are the input columns in order; Inputs[i] is terminal "d<i>".
+	Inputs []*Column
+	// Target is the column that the genome should predict.
+	Target *Column
+}
+
+// Len returns the number of rows (fitness cases) in the dataset.
+func (d *Dataset) Len() int {
+	return d.Target.Len()
+}
+
+// InputNames returns the names of the input columns, indexed by
+// their terminal number, so that InputNames()[i] is the name of "d<i>".
+func (d *Dataset) InputNames() []string {
+	result := make([]string, 0, len(d.Inputs))
+	for _, c := range d.Inputs {
+		result = append(result, c.Name)
+	}
+	return result
+}
+
+// Column returns the input or target column with the given name, or nil.
+func (d *Dataset) Column(name string) *Column {
+	if d.Target.Name == name {
+		return d.Target
+	}
+	for _, c := range d.Inputs {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// MathCases returns the fitness cases as float64 inputs and targets,
+// ready for use with genome.EvalMath.
+func (d *Dataset) MathCases() (in [][]float64, target []float64) {
+	n := d.Len()
+	in = make([][]float64, n)
+	target = make([]float64, n)
+	for i := 0; i < n; i++ {
+		in[i] = make([]float64, len(d.Inputs))
+		for j, c := range d.Inputs {
+			in[i][j] = c.Float64(i)
+		}
+		target[i] = d.Target.Float64(i)
+	}
+	return in, target
+}
+
+// BoolCases returns the fitness cases as bool inputs and targets,
+// ready for use with genome.EvalBool. All columns must be of type Bool.
+func (d *Dataset) BoolCases() (in [][]bool, target []bool, err error) {
+	for _, c := range append([]*Column{d.Target}, d.Inputs...) {
+		if c.Type != Bool {
+			return nil, nil, fmt.Errorf("column %q of type %v: %w", c.Name, c.Type, ErrType)
+		}
+	}
+	n := d.Len()
+	in = make([][]bool, n)
+	for i := 0; i < n; i++ {
+		in[i] = make([]bool, len(d.Inputs))
+		for j, c := range d.Inputs {
+			in[i][j] = c.Bools[i]
+		}
+	}
+	return in, d.Target.Bools, nil
+}
+
+// IntCases returns the fitness cases as int inputs and targets,
+// ready for use with genome.EvalInt. No column may be of type Float64.
+func (d *Dataset) IntCases() (in [][]int, target []int, err error) {
+	n := d.Len()
+	in = make([][]int, n)
+	target = make([]int, n)
+	for i := 0; i < n; i++ {
+		in[i] = make([]int, len(d.Inputs))
+		for j, c := range d.Inputs {
+			if in[i][j], err = c.Int(i); err != nil {
+				return nil, nil, err
+			}
+		}
+		if target[i], err = d.Target.Int(i); err != nil {
+			return nil, nil, err
+		}
+	}
+	return in, target, nil
+}
+
+// options are the settings used when loading a dataset.
+type options struct {
+	comma         rune
+	noHeader      bool
+	target        string
+	types         map[string]ColumnType
+	missingValues []string
+	missing       MissingStrategy
+}
+
+// Option represents an option that can modify how a dataset is loaded.
+type Option func(o *options)
+
+// WithComma sets the field delimiter, such as '\t' for TSV files.
+func WithComma(comma rune) Option {
+	return func(o *options) {
+		o.comma = comma
+	}
+}
+
+// WithNoHeader indicates that the first row contains data instead of
+// column names. The columns are then named "d0", "d1", and so on.
+func WithNoHeader() Option {
+	return func(o *options) {
+		o.noHeader = true
+	}
+}
+
+// WithTarget designates the named column as the target.
+// By default, the last column is the target.
+func WithTarget(name string) Option {
+	return func(o *options) {
+		o.target = name
+	}
+}
+
+// WithColumnType forces the named column to the given type
+// instead of inferring it from its values.
+func WithColumnType(name string, columnType ColumnType) Option {
+	return func(o *options) {
+		o.types[name] = columnType
+	}
+}
+
+// WithMissingValues sets the strings that represent a missing value.
+// By default these are "", "?", "NA", "N/A", "NaN", and "null".
+func WithMissingValues(values ...string) Option {
+	return func(o *options) {
+		o.missingValues = values
+	}
+}
+
+// WithMissingStrategy sets how missing values are handled.
+// Rows with a missing target value are always dropped.
+func WithMissingStrategy(strategy MissingStrategy) Option {
+	return func(o *options) {
+		o.missing = strategy
+	}
+}
+
+// LoadFile loads a dataset from a CSV file, or from a TSV file
+// if the file name has a ".tsv" extension.
+func LoadFile(path string, opts ...Option) (*Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		opts = append([]Option{WithComma('\t')}, opts...)
+	}
+	return Load(f, opts...)
+}
+
+// Load loads a dataset from CSV (or otherwise delimited) data.
+func Load(r io.Reader, opts ...Option) (*Dataset, error) {
+	o := &options{
+		comma:         ',',
+		types:         map[string]ColumnType{},
+		missingValues: []string{"", "?", "NA", "N/A", "NaN", "null"},
+	}
+	for _, f := range opts {
+		f(o)
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = o.comma
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("dataset is empty")
+	}
+
+	var names []string
+	if o.noHeader {
+		for i := range records[0] {
+			names = append(names, fmt.Sprintf("d%v", i))
+		}
+	} else {
+		for _, name := range records[0] {
+			names = append(names, strings.TrimSpace(name))
+		}
+		records = records[1:]
+	}
+	if len(names) < 2 {
+		return nil, fmt.Errorf("dataset has %v columns, want at least 2", len(names))
+	}
+
+	targetIdx := len(names) - 1
+	if o.target != "" {
+		targetIdx = -1
+		for i, name := range names {
+			if name == o.target {
+				targetIdx = i
+			}
+		}
+		if targetIdx < 0 {
+			return nil, fmt.Errorf("target column %q not found", o.target)
+		}
+	}
+
+	isMissing := func(s string) bool {
+		s = strings.TrimSpace(s)
+		for _, v := range o.missingValues {
+			if s == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Drop rows with a missing target (or any missing value for MissingDrop).
+	var rows [][]string
+	for _, rec := range records {
+		if isMissing(rec[targetIdx]) {
+			continue
+		}
+		if o.missing == MissingDrop && containsFunc(rec, isMissing) {
+			continue
+		}
+		rows = append(rows, rec)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("dataset has no complete rows")
+	}
+
+	d := &Dataset{}
+	for j, name := range names {
+		values := make([]string, len(rows))
+		missing := make([]bool, len(rows))
+		for i, row := range rows {
+			values[i] = strings.TrimSpace(row[j])
+			missing[i] = isMissing(values[i])
+		}
+		c, err := newColumn(name, o.types[name], values, missing, o.missing)
+		if err != nil {
+			return nil, err
+		}
+		if j == targetIdx {
+			d.Target = c
+		} else {
+			d.Inputs = append(d.Inputs, c)
+		}
+	}
+	return d, nil
+}
+
+func containsFunc(values []string, f func(string) bool) bool {
+	for _, v := range values {
+		if f(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// inferType returns the narrowest type that can represent all non-missing values.
+func inferType(values []string, missing []bool) ColumnType {
+	isBool, isInt, isFloat := true, true, true
+	for i, v := range values {
+		if missing[i] {
+			continue
+		}
+		if _, err := strconv.ParseBool(v); err != nil {
+			isBool = false
+		}
+		if _, err := strconv.Atoi(v); err != nil {
+			isInt = false
+		}
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			isFloat = false
+		}
+	}
+	switch {
+	case isBool:
+		return Bool
+	case isInt:
+		return Int
+	case isFloat:
+		return Float64
+	default:
+		return Categorical
+	}
+}
+
+func newColumn(name string, columnType ColumnType, values []string, missing []bool, strategy MissingStrategy) (*Column, error) {
+	if columnType == Auto {
+		columnType = inferType(values, missing)
+	}
+	c := &Column{Name: name, Type: columnType, Missing: missing}
+	n := len(values)
+	var numMissing int
+	for _, m := range missing {
+		if m {
+			numMissing++
+		}
+	}
+	useMean := strategy == MissingMean && numMissing < n
+
+	switch columnType {
+	case Bool:
+		c.Bools = make([]bool, n)
+		var numTrue int
+		for i, v := range values {
+			if missing[i] {
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("column %q row %v: %w", name, i, err)
+			}
+			c.Bools[i] = b
+			if b {
+				numTrue++
+			}
+		}
+		mode := useMean && 2*numTrue > n-numMissing
+		for i := range values {
+			if missing[i] {
+				c.Bools[i] = mode
+			}
+		}
+	case Int:
+		c.Ints = make([]int, n)
+		var sum float64
+		for i, v := range values {
+			if missing[i] {
+				continue
+			}
+			x, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("column %q row %v: %w", name, i, err)
+			}
+			c.Ints[i] = x
+			sum += float64(x)
+		}
+		var mean int
+		if useMean {
+			mean = int(math.Round(sum / float64(n-numMissing)))
+		}
+		for i := range values {
+			if missing[i] {
+				c.Ints[i] = mean
+			}
+		}
+	case Float64:
+		c.Floats = make([]float64, n)
+		var sum float64
+		for i, v := range values {
+			if missing[i] {
+				continue
+			}
+			x, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("column %q row %v: %w", name, i, err)
+			}
+			c.Floats[i] = x
+			sum += x
+		}
+		var mean float64
+		if useMean {
+			mean = sum / float64(n-numMissing)
+		}
+		for i := range values {
+			if missing[i] {
+				c.Floats[i] = mean
+			}
+		}
+	case Categorical:
+		c.Strings = make([]string, n)
+		counts := map[string]int{}
+		for i, v := range values {
+			if missing[i] {
+				continue
+			}
+			c.Strings[i] = v
+			if counts[v] == 0 {
+				c.Categories = append(c.Categories, v)
+			}
+			counts[v]++
+		}
+		if len(c.Categories) == 0 {
+			c.Categories = []string{""}
+		}
+		mode := c.Categories[0]
+		if useMean {
+			for _, v := range c.Categories {
+				if counts[v] > counts[mode] {
+					mode = v
+				}
+			}
+		}
+		for i := range values {
+			if missing[i] {
+				c.Strings[i] = mode
+			}
+		}
+	default:
+		return nil, fmt.Errorf("column %q: unknown column type %v", name, columnType)
+	}
+	return c, nil
+}
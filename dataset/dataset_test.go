@@ -0,0 +1,154 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+	"github.com/gmlewis/gep/v2/gene"
+	"github.com/gmlewis/gep/v2/genome"
+	"github.com/google/go-cmp/cmp"
+)
+
+const carsCSV = `weight, cylinders, origin, turbo, mpg
+1.5, 4, usa, false, 30.5
+2.0, 6, japan, true, 25.0
+NA, 4, usa, false, 31.0
+2.5, ?, europe, , 20.5
+3.0, 8, japan, true, NA
+`
+
+func TestLoad(t *testing.T) {
+	d, err := Load(strings.NewReader(carsCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := d.Len(), 4; got != want {
+		t.Errorf("Len = %v, want %v", got, want)
+	}
+	if diff := cmp.Diff([]string{"weight", "cylinders", "origin", "turbo"}, d.InputNames()); diff != "" {
+		t.Errorf("InputNames mismatch (-want +got):\n%v", diff)
+	}
+
+	want := []*Column{
+		{
+			Name:    "weight",
+			Type:    Float64,
+			Floats:  []float64{1.5, 2.0, 2.0, 2.5},
+			Missing: []bool{false, false, true, false},
+		},
+		{
+			Name:    "cylinders",
+			Type:    Int,
+			Ints:    []int{4, 6, 4, 5},
+			Missing: []bool{false, false, false, true},
+		},
+		{
+			Name:       "origin",
+			Type:       Categorical,
+			Strings:    []string{"usa", "japan", "usa", "europe"},
+			Categories: []string{"usa", "japan", "europe"},
+			Missing:    []bool{false, false, false, false},
+		},
+		{
+			Name:    "turbo",
+			Type:    Bool,
+			Bools:   []bool{false, true, false, false},
+			Missing: []bool{false, false, false, true},
+		},
+	}
+	if diff := cmp.Diff(want, d.Inputs); diff != "" {
+		t.Errorf("Inputs mismatch (-want +got):\n%v", diff)
+	}
+
+	in, target := d.MathCases()
+	wantIn := [][]float64{
+		{1.5, 4, 0, 0},
+		{2.0, 6, 1, 1},
+		{2.0, 4, 0, 0},
+		{2.5, 5, 2, 0},
+	}
+	if diff := cmp.Diff(wantIn, in); diff != "" {
+		t.Errorf("MathCases inputs mismatch (-want +got):\n%v", diff)
+	}
+	if diff := cmp.Diff([]float64{30.5, 25.0, 31.0, 20.5}, target); diff != "" {
+		t.Errorf("MathCases targets mismatch (-want +got):\n%v", diff)
+	}
+
+	if _, _, err := d.BoolCases(); !errors.Is(err, ErrType) {
+		t.Errorf("BoolCases error = %v, want ErrType", err)
+	}
+	if _, _, err := d.IntCases(); !errors.Is(err, ErrType) {
+		t.Errorf("IntCases error = %v, want ErrType", err)
+	}
+}
+
+func TestLoadOptions(t *testing.T) {
+	d, err := Load(strings.NewReader(carsCSV),
+		WithTarget("cylinders"),
+		WithColumnType("mpg", Int),
+		WithMissingStrategy(MissingDrop))
+	if err == nil {
+		t.Fatalf("Load: got nil error for non-integer mpg column")
+	}
+
+	d, err = Load(strings.NewReader(carsCSV),
+		WithTarget("cylinders"),
+		WithMissingStrategy(MissingDrop))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.Target.Name, "cylinders"; got != want {
+		t.Errorf("Target.Name = %v, want %v", got, want)
+	}
+	if diff := cmp.Diff([]int{4, 6}, d.Target.Ints); diff != "" {
+		t.Errorf("Target.Ints mismatch (-want +got):\n%v", diff)
+	}
+	if diff := cmp.Diff([]string{"weight", "origin", "turbo", "mpg"}, d.InputNames()); diff != "" {
+		t.Errorf("InputNames mismatch (-want +got):\n%v", diff)
+	}
+
+	d, err = Load(strings.NewReader("1;2;3\n4;5;6\n"), WithComma(';'), WithNoHeader(), WithMissingStrategy(MissingZero))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, target, err := d.IntCases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([][]int{{1, 2}, {4, 5}}, in); diff != "" {
+		t.Errorf("IntCases inputs mismatch (-want +got):\n%v", diff)
+	}
+	if diff := cmp.Diff([]int{3, 6}, target); diff != "" {
+		t.Errorf("IntCases targets mismatch (-want +got):\n%v", diff)
+	}
+	if diff := cmp.Diff([]string{"d0", "d1"}, d.InputNames()); diff != "" {
+		t.Errorf("InputNames mismatch (-want +got):\n%v", diff)
+	}
+}
+
+func TestLoadFileEvalBool(t *testing.T) {
+	d, err := LoadFile("testdata/parity.tsv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, target, err := d.BoolCases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// odd-3-parity: (a xor b) xor c
+	g := gene.New("Xor.Xor.d2.d0.d1", functions.Bool)
+	gn := genome.New([]*gene.Gene{g}, "And")
+	for i, v := range in {
+		if got := gn.EvalBool(v); got != target[i] {
+			t.Errorf("EvalBool(%v) = %v, want %v", v, got, target[i])
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"fmt"
+
+	"github.com/gmlewis/gep/v2/transform"
+)
+
+// EncodeCategorical learns the encoding of every Categorical input column
+// from the dataset (typically the training dataset), which is then used by
+// MathCases. The TargetMean encoding requires a numerical target.
+// Use SetCategorical to apply the same encoding to another dataset.
+func (d *Dataset) EncodeCategorical(e transform.Encoding) error {
+	var target []float64
+	if e == transform.TargetMean {
+		if d.Target.Type == Categorical {
+			return fmt.Errorf("target column %q of type %v: %w", d.Target.Name, d.Target.Type, ErrType)
+		}
+		target = make([]float64, d.Len())
+		for i := range target {
+			target[i] = d.Target.Float64(i)
+		}
+	}
+
+	for _, c := range d.Inputs {
+		if c.Type != Categorical {
+			continue
+		}
+		switch e {
+		case transform.Ordinal:
+			c.Encoding = transform.NewOrdinal(c.Categories)
+		case transform.TargetMean:
+			c.Encoding = transform.NewTargetMean(c.Categories, c.Strings, target)
+		default:
+			return fmt.Errorf("unknown categorical encoding %v", e)
+		}
+	}
+	return nil
+}
+
+// Categorical returns the encoding of every input column, indexed by
+// terminal number, with nil for the non-Categorical columns.
+// Columns that have not been encoded use the ordinal encoding.
+// The result can be passed to transform.WithCategorical so that the
+// generated code encodes the raw string inputs in the same way.
+func (d *Dataset) Categorical() []*transform.Categories {
+	result := make([]*transform.Categories, len(d.Inputs))
+	for i, c := range d.Inputs {
+		switch {
+		case c.Type != Categorical:
+		case c.Encoding != nil:
+			result[i] = c.Encoding
+		default:
+			result[i] = transform.NewOrdinal(c.Categories)
+		}
+	}
+	return result
+}
+
+// SetCategorical sets the encoding of the Categorical input columns,
+// such as the encodings learned from a training dataset.
+func (d *Dataset) SetCategorical(encodings []*transform.Categories) error {
+	if len(encodings) != len(d.Inputs) {
+		return fmt.Errorf("got %v encodings for %v inputs", len(encodings), len(d.Inputs))
+	}
+	for i, c := range d.Inputs {
+		if c.Type == Categorical {
+			c.Encoding = encodings[i]
+		}
+	}
+	return nil
+}
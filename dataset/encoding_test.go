@@ -0,0 +1,71 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/transform"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEncodeCategorical(t *testing.T) {
+	d, err := Load(strings.NewReader(carsCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Before encoding, categories use their ordinal encoding.
+	want := []*transform.Categories{nil, nil, {Names: []string{"usa", "japan", "europe"}, Values: []float64{0, 1, 2}}, nil}
+	if diff := cmp.Diff(want, d.Categorical()); diff != "" {
+		t.Errorf("Categorical mismatch (-want +got):\n%v", diff)
+	}
+
+	if err := d.EncodeCategorical(transform.TargetMean); err != nil {
+		t.Fatalf("EncodeCategorical: %v", err)
+	}
+	want[2].Values = []float64{30.75, 25.0, 20.5}
+	if diff := cmp.Diff(want, d.Categorical()); diff != "" {
+		t.Errorf("Categorical mismatch (-want +got):\n%v", diff)
+	}
+	in, _ := d.MathCases()
+	var got []float64
+	for _, row := range in {
+		got = append(got, row[2])
+	}
+	if diff := cmp.Diff([]float64{30.75, 25.0, 30.75, 20.5}, got); diff != "" {
+		t.Errorf("MathCases origin mismatch (-want +got):\n%v", diff)
+	}
+
+	// The encoding learned from the training rows applies to the validation rows.
+	train, valid := d.Subset([]int{0, 1}), d.Subset([]int{2, 3})
+	if err := train.EncodeCategorical(transform.TargetMean); err != nil {
+		t.Fatalf("EncodeCategorical: %v", err)
+	}
+	if err := valid.SetCategorical(train.Categorical()); err != nil {
+		t.Fatalf("SetCategorical: %v", err)
+	}
+	in, _ = valid.MathCases()
+	if got, want := []float64{in[0][2], in[1][2]}, []float64{30.5, 0}; !cmp.Equal(got, want) {
+		t.Errorf("validation origin = %v, want %v (europe is unseen in training)", got, want)
+	}
+	if err := valid.SetCategorical(nil); err == nil {
+		t.Error("SetCategorical(nil): expected error")
+	}
+}
+
+func TestEncodeCategoricalTarget(t *testing.T) {
+	d, err := Load(strings.NewReader(carsCSV), WithTarget("origin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.EncodeCategorical(transform.TargetMean); err == nil {
+		t.Error("EncodeCategorical: expected error for a categorical target")
+	}
+	if err := d.EncodeCategorical(transform.Ordinal); err != nil {
+		t.Errorf("EncodeCategorical: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// SplitMethod determines how rows are assigned when splitting a dataset.
+type SplitMethod int
+
+const (
+	// Random shuffles the rows before splitting.
+	Random SplitMethod = iota
+	// Stratified shuffles the rows while preserving the distribution of
+	// the target values in every split. Bool, Int, and Categorical targets
+	// are stratified by value and Float64 targets by decile.
+	Stratified
+	// Ordered keeps the original (for example, chronological) row order so
+	// that the training rows always precede the validation and test rows.
+	Ordered
+)
+
+// SplitOption represents an option that can modify how a dataset is split.
+type SplitOption func(o *splitOptions)
+
+type splitOptions struct {
+	rng *rand.Rand
+}
+
+// WithRand shuffles the rows with rng instead of the global random number
+// generator, so that the same seed always gives the same split.
+func WithRand(rng *rand.Rand) SplitOption {
+	return func(o *splitOptions) {
+		o.rng = rng
+	}
+}
+
+// perm returns a random permutation of the n row indices.
+func (o *splitOptions) perm(n int) []int {
+	if o.rng == nil {
+		return rand.Perm(n)
+	}
+	return o.rng.Perm(n)
+}
+
+func newSplitOptions(opts []SplitOption) *splitOptions {
+	o := &splitOptions{}
+	for _, f := range opts {
+		f(o)
+	}
+	return o
+}
+
+// Fold is a single fold of a k-fold cross-validation.
+type Fold struct {
+	Train      *Dataset
+	Validation *Dataset
+}
+
+// Subset returns a new dataset containing only the given rows, in order.
+func (d *Dataset) Subset(rows []int) *Dataset {
+	result := &Dataset{Target: d.Target.subset(rows)}
+	for _, c := range d.Inputs {
+		result.Inputs = append(result.Inputs, c.subset(rows))
+	}
+	return result
+}
+
+func (c *Column) subset(rows []int) *Column {
+	result := &Column{
+		Name:       c.Name,
+		Type:       c.Type,
+		Categories: c.Categories,
+		Encoding:   c.Encoding,
+		Missing:    make([]bool, 0, len(rows)),
+	}
+	for _, i := range rows {
+		result.Missing = append(result.Missing, c.Missing[i])
+		switch c.Type {
+		case Bool:
+			result.Bools = append(result.Bools, c.Bools[i])
+		case Int:
+			result.Ints = append(result.Ints, c.Ints[i])
+		case Float64:
+			result.Floats = append(result.Floats, c.Floats[i])
+		case Categorical:
+			result.Strings = append(result.Strings, c.Strings[i])
+		}
+	}
+	return result
+}
+
+// Split divides the dataset into training, validation, and test datasets
+// holding trainFrac, validFrac, and the remaining fraction of the rows.
+// The test dataset is empty (but non-nil) when trainFrac+validFrac is 1.
+func (d *Dataset) Split(method SplitMethod, trainFrac, validFrac float64, opts ...SplitOption) (train, valid, test *Dataset, err error) {
+	if trainFrac <= 0 || validFrac < 0 || trainFrac+validFrac > 1 {
+		return nil, nil, nil, fmt.Errorf("invalid split fractions %v and %v", trainFrac, validFrac)
+	}
+
+	var trainRows, validRows, testRows []int
+	for _, group := range d.groups(method, newSplitOptions(opts)) {
+		n := float64(len(group))
+		t := int(math.Round(n * trainFrac))
+		v := int(math.Round(n * (trainFrac + validFrac)))
+		trainRows = append(trainRows, group[:t]...)
+		validRows = append(validRows, group[t:v]...)
+		testRows = append(testRows, group[v:]...)
+	}
+	if method != Ordered {
+		sort.Ints(trainRows)
+		sort.Ints(validRows)
+		sort.Ints(testRows)
+	}
+	return d.Subset(trainRows), d.Subset(validRows), d.Subset(testRows), nil
+}
+
+// KFold divides the dataset into k folds for cross-validation.
+// Each row appears in the validation dataset of exactly one fold.
+// With the Ordered method, each fold validates on a contiguous block of rows.
+func (d *Dataset) KFold(k int, method SplitMethod, opts ...SplitOption) ([]Fold, error) {
+	n := d.Len()
+	if k < 2 || k > n {
+		return nil, fmt.Errorf("invalid number of folds %v for %v rows", k, n)
+	}
+
+	foldOf := make([]int, n)
+	var pos int
+	for _, group := range d.groups(method, newSplitOptions(opts)) {
+		for _, row := range group {
+			if method == Ordered {
+				foldOf[row] = pos * k / n
+			} else {
+				foldOf[row] = pos % k
+			}
+			pos++
+		}
+	}
+
+	result := make([]Fold, k)
+	for i := range result {
+		var trainRows, validRows []int
+		for row, f := range foldOf {
+			if f == i {
+				validRows = append(validRows, row)
+			} else {
+				trainRows = append(trainRows, row)
+			}
+		}
+		result[i] = Fold{Train: d.Subset(trainRows), Validation: d.Subset(validRows)}
+	}
+	return result, nil
+}
+
+// groups returns the row indices grouped into strata (a single group
+// unless the method is Stratified) and ordered according to the method.
+func (d *Dataset) groups(method SplitMethod, o *splitOptions) [][]int {
+	n := d.Len()
+	switch method {
+	case Ordered:
+		rows := make([]int, n)
+		for i := range rows {
+			rows[i] = i
+		}
+		return [][]int{rows}
+	case Stratified:
+		strataKeys := d.strata()
+		var keys []string
+		strata := map[string][]int{}
+		for _, row := range o.perm(n) {
+			key := strataKeys[row]
+			if _, ok := strata[key]; !ok {
+				keys = append(keys, key)
+			}
+			strata[key] = append(strata[key], row)
+		}
+		sort.Strings(keys)
+		result := make([][]int, 0, len(keys))
+		for _, key := range keys {
+			result = append(result, strata[key])
+		}
+		return result
+	default:
+		return [][]int{o.perm(n)}
+	}
+}
+
+// strata returns the stratification key of the target value of every row.
+func (d *Dataset) strata() []string {
+	t := d.Target
+	n := t.Len()
+	result := make([]string, n)
+	switch t.Type {
+	case Bool:
+		for i, v := range t.Bools {
+			result[i] = fmt.Sprint(v)
+		}
+	case Int:
+		for i, v := range t.Ints {
+			result[i] = fmt.Sprint(v)
+		}
+	case Categorical:
+		copy(result, t.Strings)
+	default:
+		rows := make([]int, n)
+		for i := range rows {
+			rows[i] = i
+		}
+		sort.SliceStable(rows, func(a, b int) bool { return t.Floats[rows[a]] < t.Floats[rows[b]] })
+		for rank, row := range rows {
+			result[row] = fmt.Sprint(10 * rank / n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newSplitDataset(t *testing.T, n int) *Dataset {
+	t.Helper()
+	lines := []string{"x,label"}
+	for i := 0; i < n; i++ {
+		lines = append(lines, fmt.Sprintf("%v,%v", i, []string{"a", "a", "a", "b"}[i%4]))
+	}
+	d, err := Load(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestSplit(t *testing.T) {
+	d := newSplitDataset(t, 48)
+
+	for _, method := range []SplitMethod{Random, Stratified, Ordered} {
+		train, valid, test, err := d.Split(method, 0.5, 0.25)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := []int{train.Len(), valid.Len(), test.Len()}, []int{24, 12, 12}; !cmp.Equal(got, want) {
+			t.Errorf("Split(%v) lengths = %v, want %v", method, got, want)
+		}
+
+		seen := map[int]bool{}
+		for _, s := range []*Dataset{train, valid, test} {
+			for _, x := range s.Inputs[0].Ints {
+				if seen[x] {
+					t.Errorf("Split(%v): row %v appears more than once", method, x)
+				}
+				seen[x] = true
+			}
+		}
+
+		if method == Stratified {
+			var numB int
+			for _, v := range valid.Target.Strings {
+				if v == "b" {
+					numB++
+				}
+			}
+			if numB != 3 || len(valid.Target.Categories) != 2 {
+				t.Errorf("Split(Stratified) validation has %v of 12 'b' labels, want 3", numB)
+			}
+		}
+
+		if method == Ordered {
+			if got, want := valid.Inputs[0].Ints, []int{24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35}; !cmp.Equal(got, want) {
+				t.Errorf("Split(Ordered) validation = %v, want %v", got, want)
+			}
+		}
+	}
+
+	if _, _, _, err := d.Split(Random, 0.8, 0.3); err == nil {
+		t.Error("Split(Random, 0.8, 0.3): got nil error, want error")
+	}
+}
+
+func TestKFold(t *testing.T) {
+	d := newSplitDataset(t, 20)
+
+	for _, method := range []SplitMethod{Random, Stratified, Ordered} {
+		folds, err := d.KFold(5, method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(folds) != 5 {
+			t.Fatalf("KFold(5, %v) = %v folds, want 5", method, len(folds))
+		}
+		seen := map[int]int{}
+		for i, f := range folds {
+			if f.Train.Len() != 16 || f.Validation.Len() != 4 {
+				t.Errorf("KFold(5, %v) fold %v: train=%v validation=%v, want 16 and 4", method, i, f.Train.Len(), f.Validation.Len())
+			}
+			for _, x := range f.Validation.Inputs[0].Ints {
+				seen[x]++
+			}
+		}
+		if len(seen) != 20 {
+			t.Errorf("KFold(5, %v) validated %v distinct rows, want 20", method, len(seen))
+		}
+	}
+
+	if _, err := d.KFold(1, Random); err == nil {
+		t.Error("KFold(1, Random): got nil error, want error")
+	}
+}
+
+func TestSplitWithRand(t *testing.T) {
+	d := newSplitDataset(t, 48)
+
+	for _, method := range []SplitMethod{Random, Stratified} {
+		train1, valid1, test1, err := d.Split(method, 0.5, 0.25, WithRand(rand.New(rand.NewSource(42))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		train2, valid2, test2, err := d.Split(method, 0.5, 0.25, WithRand(rand.New(rand.NewSource(42))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, pair := range [][2]*Dataset{{train1, train2}, {valid1, valid2}, {test1, test2}} {
+			if diff := cmp.Diff(pair[0].Inputs[0].Ints, pair[1].Inputs[0].Ints); diff != "" {
+				t.Errorf("Split(%v) with the same seed, dataset #%v mismatch (-first +second):\n%v", method, i, diff)
+			}
+		}
+
+		folds1, err := d.KFold(4, method, WithRand(rand.New(rand.NewSource(7))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		folds2, err := d.KFold(4, method, WithRand(rand.New(rand.NewSource(7))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range folds1 {
+			if diff := cmp.Diff(folds1[i].Validation.Inputs[0].Ints, folds2[i].Validation.Inputs[0].Ints); diff != "" {
+				t.Errorf("KFold(%v) with the same seed, fold #%v mismatch (-first +second):\n%v", method, i, diff)
+			}
+		}
+
+		_, valid3, _, err := d.Split(method, 0.5, 0.25, WithRand(rand.New(rand.NewSource(43))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmp.Equal(valid1.Inputs[0].Ints, valid3.Inputs[0].Ints) {
+			t.Errorf("Split(%v) with different seeds gave the same validation rows %v", method, valid1.Inputs[0].Ints)
+		}
+	}
+}
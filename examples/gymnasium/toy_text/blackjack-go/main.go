@@ -8,7 +8,7 @@ import (
 	"flag"
 	"log"
 	"os"
-	"sync"
+	"slices"
 	"time"
 
 	"github.com/gmlewis/gep/v2/common"
@@ -29,6 +29,8 @@ var (
 	numConsts       = flag.Int("nc", 2, "Number of constants in karva expressions")
 	numIndividuals  = flag.Int("ni", 100, "Number of individuals in population")
 	numSteps        = flag.Int("s", defaultSteps, "Number of total steps to run")
+	recordFile      = flag.String("record", "", "If set, record the episodes of the best agent to this JSON Lines file")
+	seed            = flag.Int64("seed", 0, "If non-zero, deal the same hands at every table by re-seeding all tables with seed+step at every step")
 	showHelp        = flag.Bool("h", false, "Show help message")
 	showTime        = flag.Bool("t", false, "Display timestamps")
 )
@@ -98,34 +100,25 @@ func main() {
 		}
 	}
 
-	makeEvaluateAgentFunc := func(agentIdx int) func(episodeSteps int, obs common.Obs, action any) error {
-		return func(episodeSteps int, obs common.Obs, action any) error {
-			return agents.EvaluateAgent(agentIdx, episodeSteps, obs, action)
-		}
-	}
-	makeRewardAgentFunc := func(agentIdx int) func(reward float64) {
-		return func(reward float64) {
-			agents.RewardAgent(agentIdx, reward)
-		}
-	}
-
-	casino := &casinoT{
-		tables: make([]*tableT, 0, *numIndividuals),
-	}
-	for agentIdx := 0; agentIdx < *numIndividuals; agentIdx++ {
-		env, err := gym.Make(environment)
-		check("gym.Make: %v", err)
-		casino.tables = append(casino.tables, &tableT{
-			env:           env,
-			evaluateAgent: makeEvaluateAgentFunc(agentIdx),
-			rewardAgent:   makeRewardAgentFunc(agentIdx),
-		})
+	envs, err := gym.MakeEnvs(environment, *numIndividuals)
+	check("gym.MakeEnvs: %v", err)
+	var casino gym.VectorEnv
+	if *debug { // Run tables serially in debug mode
+		casino = gym.NewSyncVectorEnv(envs)
+	} else {
+		casino = gym.NewAsyncVectorEnv(envs, 0)
 	}
+	defer casino.Close()
 
 	startTime := time.Now()
 
 	for i := 1; i <= *numSteps; i++ {
-		casino.runEpisodes(*episodesPerStep)
+		if *seed != 0 { // Compare the agents fairly on the same hands.
+			for _, env := range envs {
+				env.Reset(common.WithSeed(*seed + int64(i)))
+			}
+		}
+		runEpisodes(casino, agents, *episodesPerStep)
 
 		if *debug || i%(*numSteps/100) == 0 {
 			agents.SortIndividuals()
@@ -158,78 +151,92 @@ func main() {
 		check("Expression: %v", err)
 		log.Printf("Individual #%v: %v\n\tkarva: %v", i+1, expr, individual)
 	}
-	log.Printf("Done.")
-}
-
-type casinoT struct {
-	tables []*tableT
-}
 
-type tableT struct {
-	env           gym.Environment
-	evaluateAgent func(episodeSteps int, obs common.Obs, action any) error
-	rewardAgent   func(reward float64)
+	if *recordFile != "" {
+		recordBestAgent(agents, *recordFile, *episodesPerStep)
+		log.Printf("Recorded %v episodes of the best agent to %v", *episodesPerStep, *recordFile)
+	}
+	log.Printf("Done.")
 }
 
-func (c *casinoT) runEpisodes(numEpisodes int) {
-	if *debug { // Run tables serially in debug mode
-		log.Printf("")
-		for i, table := range c.tables {
-			log.Printf("runEpisodes for table[%v]", i)
-			table.runEpisodes(numEpisodes)
+// recordBestAgent records numEpisodes episodes played by the best agent,
+// with a text frame of the hands at every step.
+func recordBestAgent(agents *model.GymnasiumAgents, filename string, numEpisodes int) {
+	f, err := os.Create(filename)
+	check("os.Create: %v", err)
+	defer f.Close()
+
+	env, err := gym.Make(environment)
+	check("gym.Make: %v", err)
+	recorder := gym.NewRecordEpisodes(env, f)
+	recorder.RecordFrames = true
+
+	for episode := 0; episode < numEpisodes; episode++ {
+		obs, _ := recorder.Reset()
+		for episodeSteps := 0; ; episodeSteps++ {
+			var action int
+			err := agents.EvaluateAgent(0, episodeSteps, obs, &action)
+			check("EvaluateAgent(%v): %v", obs, err)
+			var terminated, truncated bool
+			obs, _, terminated, truncated, _ = recorder.Step(action)
+			if terminated || truncated {
+				break
+			}
 		}
-		return
-	}
-
-	// Run all tables concurrently for numEpisodes
-	var wg sync.WaitGroup
-	wg.Add(len(c.tables))
-	for _, table := range c.tables {
-		go func(table *tableT) {
-			table.runEpisodes(numEpisodes)
-			wg.Done()
-		}(table)
 	}
-	wg.Wait()
+	check("RecordEpisodes: %v", recorder.Err())
 }
 
-func (t *tableT) runEpisodes(numEpisodes int) {
-	lastObs, _ := t.env.Reset()
+// runEpisodes plays numEpisodes episodes at every table of the casino,
+// where table #i is played by agent #i, and rewards each agent with its total reward.
+func runEpisodes(casino gym.VectorEnv, agents *model.GymnasiumAgents, numEpisodes int) {
+	numTables := casino.NumEnvs()
+	obs, _ := casino.Reset()
 	if *debug {
-		log.Printf("env.Reset: lastObs=%v [agent-sum, dealer-card, usable-ace]", lastObs)
-	}
-
-	// Run numEpisodes
-	var totalReward float64
-	var episode, episodeSteps int
-	for episode < numEpisodes {
-		var action int
-		err := t.evaluateAgent(episodeSteps, lastObs, &action)
-		check("Evaluate(%v): %v", lastObs, err)
-
-		obs, reward, terminated, truncated, info := t.env.Step(action)
-		check("Step(%v): %v", action, err)
-
-		totalReward += reward
-		if *debug {
-			log.Printf("Episode #%v, step #%v: obs=%v, action=%v, reward=%v, terminated=%v, totalReward=%v", episode+1, episodeSteps+1, lastObs, action, reward, terminated, totalReward)
-		}
-
-		if truncated {
-			log.Fatalf("ERROR: unexpected truncated in Blackjack-v1 environment: info=%v", info)
-		}
+		log.Printf("")
+		log.Printf("casino.Reset: obs=%v [agent-sum, dealer-card, usable-ace]", obs)
+	}
+
+	totalRewards := make([]float64, numTables)
+	episodes := make([]int, numTables)
+	episodeSteps := make([]int, numTables)
+	autoreset := make([]bool, numTables)
+	for slices.Min(episodes) < numEpisodes {
+		actions, err := agents.EvaluateAgents(episodeSteps, obs)
+		check("EvaluateAgents(%v): %v", obs, err)
+
+		lastObs := obs
+		var rewards []float64
+		var terminated, truncated []bool
+		var infos []any
+		obs, rewards, terminated, truncated, infos = casino.Step(actions)
+
+		for i := range numTables {
+			if truncated[i] {
+				log.Fatalf("ERROR: unexpected truncated in Blackjack-v1 environment: info=%v", infos[i])
+			}
 
-		if terminated {
-			lastObs, _ = t.env.Reset()
-			episode++
-			episodeSteps = 0
-		} else {
-			lastObs = obs
-			episodeSteps++
+			switch {
+			case autoreset[i]: // The table was reset by this step and a new episode begins.
+				episodeSteps[i] = 0
+			case episodes[i] < numEpisodes:
+				totalRewards[i] += rewards[i]
+				if *debug {
+					log.Printf("Table #%v, episode #%v, step #%v: obs=%v, action=%v, reward=%v, terminated=%v, totalReward=%v", i, episodes[i]+1, episodeSteps[i]+1, lastObs[i], actions[i], rewards[i], terminated[i], totalRewards[i])
+				}
+				if terminated[i] {
+					episodes[i]++
+				} else {
+					episodeSteps[i]++
+				}
+			}
+			autoreset[i] = terminated[i]
 		}
 	}
 
-	t.rewardAgent(totalReward)
+	for agentIdx, totalReward := range totalRewards {
+		agents.RewardAgent(agentIdx, totalReward)
+	}
 }
 
 func check(fmt string, args ...any) {
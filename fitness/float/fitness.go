@@ -3,6 +3,12 @@
 // license that can be found in the LICENSE file.
 
 // Package fitness provides common fitness functions.
+//
+// Predicted values that are NaN or infinite are handled the same way by
+// every fitness function in this package: hits-based functions count them
+// as misses, selection-range functions treat them as contributing nothing,
+// and error-based functions return a fitness of 0 since the error of such
+// a model is unbounded. A non-finite target value is reported as ErrTarget.
 package fitness
 
 import (
@@ -13,8 +19,34 @@ import (
 // FloatFunc ...
 type FloatFunc func(predicted, target []float64) (float64, error)
 
-// ErrLength is returned when the predicted and target slices are empty or not the same length.
-var ErrLength = errors.New("length error")
+var (
+	// ErrLength is returned when the predicted and target slices are empty or not the same length.
+	ErrLength = errors.New("length error")
+	// ErrTarget is returned when a target value is NaN or infinite.
+	ErrTarget = errors.New("non-finite target value")
+)
+
+// check validates the predicted and target slices and reports whether
+// all the predicted values are finite.
+func check(predicted, target []float64) (finite bool, err error) {
+	if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
+		return false, ErrLength
+	}
+	finite = true
+	for i, t := range target {
+		if !isFinite(t) {
+			return false, ErrTarget
+		}
+		if !isFinite(predicted[i]) {
+			finite = false
+		}
+	}
+	return finite, nil
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
 
 // NumHitsAbs returns a fitness function that favors models that perform well for all
 // fitness cases within a certain absolute error (that is, the precision that is chosen
@@ -25,8 +57,8 @@ func NumHitsAbs(precision, scaleFactor float64) (FloatFunc, error) {
 		return nil, errors.New("invalid precision, should be 0-1")
 	}
 	return func(predicted, target []float64) (float64, error) {
-		if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
-			return 0, ErrLength
+		if _, err := check(predicted, target); err != nil {
+			return 0, err
 		}
 		result := 0.0
 		for i, t := range target {
@@ -48,8 +80,8 @@ func NumHitsRel(precision, scaleFactor float64) (FloatFunc, error) {
 		return nil, errors.New("invalid precision, should be 0-1")
 	}
 	return func(predicted, target []float64) (float64, error) {
-		if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
-			return 0, ErrLength
+		if _, err := check(predicted, target); err != nil {
+			return 0, err
 		}
 		result := 0.0
 		for i, t := range target {
@@ -68,6 +100,43 @@ func NumHitsRel(precision, scaleFactor float64) (FloatFunc, error) {
 	}, nil
 }
 
+// NumHitsWithPenalty returns a fitness function that counts the number of fitness
+// cases within a certain absolute error (the precision, a number between 0 and 1,
+// inclusive) of the correct value, just like NumHitsAbs. However, a model whose
+// predictions are identical for every fitness case is penalized with a fitness of 0
+// (unless the targets are also all identical) since such a model ignores its inputs.
+// The maximum fitness possible is N*scaleFactor, where N=len(target).
+func NumHitsWithPenalty(precision, scaleFactor float64) (FloatFunc, error) {
+	if precision < 0 || precision > 1 {
+		return nil, errors.New("invalid precision, should be 0-1")
+	}
+	return func(predicted, target []float64) (float64, error) {
+		if _, err := check(predicted, target); err != nil {
+			return 0, err
+		}
+		if isConstant(predicted) && !isConstant(target) {
+			return 0, nil
+		}
+		result := 0.0
+		for i, t := range target {
+			e := math.Abs(predicted[i] - t)
+			if e <= precision {
+				result++
+			}
+		}
+		return result * scaleFactor, nil
+	}, nil
+}
+
+func isConstant(values []float64) bool {
+	for _, v := range values[1:] {
+		if v != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
 // SelectionRangeAbs returns a fitness function that is used as a limit for
 // selection to operate, above which the performance of a program on a particular
 // fitness case contributes nothing to its fitness. The precision is the
@@ -76,11 +145,14 @@ func NumHitsRel(precision, scaleFactor float64) (FloatFunc, error) {
 // The maximum fitness possible is N*selectionRange*scaleFactor, where N=len(target).
 func SelectionRangeAbs(selectionRange, scaleFactor float64) (FloatFunc, error) {
 	return func(predicted, target []float64) (float64, error) {
-		if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
-			return 0, ErrLength
+		if _, err := check(predicted, target); err != nil {
+			return 0, err
 		}
 		result := 0.0
 		for i, t := range target {
+			if !isFinite(predicted[i]) {
+				continue
+			}
 			e := math.Abs(predicted[i] - t)
 			result += (selectionRange - e)
 		}
@@ -96,11 +168,14 @@ func SelectionRangeAbs(selectionRange, scaleFactor float64) (FloatFunc, error) {
 // The maximum fitness possible is N*selectionRange*scaleFactor, where N=len(target).
 func SelectionRangeRel(selectionRange, scaleFactor float64) (FloatFunc, error) {
 	return func(predicted, target []float64) (float64, error) {
-		if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
-			return 0, ErrLength
+		if _, err := check(predicted, target); err != nil {
+			return 0, err
 		}
 		result := 0.0
 		for i, t := range target {
+			if !isFinite(predicted[i]) {
+				continue
+			}
 			if t == 0 {
 				if predicted[i] == 0 {
 					result += selectionRange
@@ -116,10 +191,11 @@ func SelectionRangeRel(selectionRange, scaleFactor float64) (FloatFunc, error) {
 
 // MeanSquaredErrorAbs returns a fitness function that calculates the mean square
 // error and is normalized from 0 to scaleFactor.
+// The maximum fitness possible is scaleFactor.
 func MeanSquaredErrorAbs(scaleFactor float64) (FloatFunc, error) {
 	return func(predicted, target []float64) (float64, error) {
-		if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
-			return 0, ErrLength
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
 		}
 		result := 0.0
 		for i, t := range target {
@@ -133,10 +209,11 @@ func MeanSquaredErrorAbs(scaleFactor float64) (FloatFunc, error) {
 
 // MeanSquaredErrorAbsRoot returns a fitness function that calculates the square root
 // of the mean square error and is normalized from 0 to scaleFactor.
+// The maximum fitness possible is scaleFactor.
 func MeanSquaredErrorAbsRoot(scaleFactor float64) (FloatFunc, error) {
 	return func(predicted, target []float64) (float64, error) {
-		if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
-			return 0, ErrLength
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
 		}
 		result := 0.0
 		for i, t := range target {
@@ -150,10 +227,11 @@ func MeanSquaredErrorAbsRoot(scaleFactor float64) (FloatFunc, error) {
 
 // MeanSquaredErrorRelRoot returns a fitness function that calculates the square root
 // of the mean square relative error and is normalized from 0 to scaleFactor.
+// The maximum fitness possible is scaleFactor.
 func MeanSquaredErrorRelRoot(scaleFactor float64) (FloatFunc, error) {
 	return func(predicted, target []float64) (float64, error) {
-		if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
-			return 0, ErrLength
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
 		}
 		result := 0.0
 		for i, t := range target {
@@ -171,10 +249,11 @@ func MeanSquaredErrorRelRoot(scaleFactor float64) (FloatFunc, error) {
 
 // MeanSquaredErrorRel returns a fitness function that calculates the mean square
 // relative error and is normalized from 0 to scaleFactor.
+// The maximum fitness possible is scaleFactor.
 func MeanSquaredErrorRel(scaleFactor float64) (FloatFunc, error) {
 	return func(predicted, target []float64) (float64, error) {
-		if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
-			return 0, ErrLength
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
 		}
 		result := 0.0
 		for i, t := range target {
@@ -190,13 +269,133 @@ func MeanSquaredErrorRel(scaleFactor float64) (FloatFunc, error) {
 	}, nil
 }
 
+// RootMeanSquaredError returns a fitness function that calculates the root mean
+// square error, sqrt(sum((p-t)^2)/N), and is normalized from 0 to scaleFactor.
+// The maximum fitness possible is scaleFactor.
+func RootMeanSquaredError(scaleFactor float64) (FloatFunc, error) {
+	return func(predicted, target []float64) (float64, error) {
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
+		}
+		result := 0.0
+		for i, t := range target {
+			e := predicted[i] - t
+			result += (e * e)
+		}
+		result = scaleFactor / (1 + math.Sqrt(result/float64(len(predicted))))
+		return result, nil
+	}, nil
+}
+
+// MeanAbsoluteError returns a fitness function that calculates the mean absolute
+// error and is normalized from 0 to scaleFactor.
+// The maximum fitness possible is scaleFactor.
+func MeanAbsoluteError(scaleFactor float64) (FloatFunc, error) {
+	return func(predicted, target []float64) (float64, error) {
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
+		}
+		result := 0.0
+		for i, t := range target {
+			result += math.Abs(predicted[i] - t)
+		}
+		result = scaleFactor / (1 + result/float64(len(predicted)))
+		return result, nil
+	}, nil
+}
+
+// RelativeAbsoluteError returns a fitness function that calculates the relative
+// absolute error, which is the total absolute error divided by the total absolute
+// error of the simple predictor (the average of the targets). It is normalized
+// from 0 to scaleFactor. If all the targets are identical, the total absolute
+// error is used without being divided.
+// The maximum fitness possible is scaleFactor.
+func RelativeAbsoluteError(scaleFactor float64) (FloatFunc, error) {
+	return func(predicted, target []float64) (float64, error) {
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
+		}
+		var avg float64
+		for _, t := range target {
+			avg += t
+		}
+		avg /= float64(len(target))
+		var sumE, sumT float64
+		for i, t := range target {
+			sumE += math.Abs(predicted[i] - t)
+			sumT += math.Abs(t - avg)
+		}
+		if sumT == 0 {
+			sumT = 1
+		}
+		result := scaleFactor / (1 + sumE/sumT)
+		return result, nil
+	}, nil
+}
+
+// HuberLoss returns a fitness function that calculates the mean Huber loss,
+// which is quadratic for errors up to delta and linear beyond it, making it
+// robust to outliers. delta must be greater than 0. The result is normalized
+// from 0 to scaleFactor.
+// The maximum fitness possible is scaleFactor.
+func HuberLoss(delta, scaleFactor float64) (FloatFunc, error) {
+	if delta <= 0 {
+		return nil, errors.New("invalid delta, should be > 0")
+	}
+	return func(predicted, target []float64) (float64, error) {
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
+		}
+		result := 0.0
+		for i, t := range target {
+			e := math.Abs(predicted[i] - t)
+			if e <= delta {
+				result += 0.5 * e * e
+			} else {
+				result += delta * (e - 0.5*delta)
+			}
+		}
+		result = scaleFactor / (1 + result/float64(len(predicted)))
+		return result, nil
+	}, nil
+}
+
+// QuantileLoss returns a fitness function that calculates the mean quantile
+// (or "pinball") loss for the given quantile, which must be between 0 and 1,
+// exclusive. Under-predictions are weighted by quantile and over-predictions
+// by (1-quantile), so a quantile of 0.5 favors the median.
+// The result is normalized from 0 to scaleFactor.
+// The maximum fitness possible is scaleFactor.
+func QuantileLoss(quantile, scaleFactor float64) (FloatFunc, error) {
+	if quantile <= 0 || quantile >= 1 {
+		return nil, errors.New("invalid quantile, should be between 0 and 1, exclusive")
+	}
+	return func(predicted, target []float64) (float64, error) {
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
+		}
+		result := 0.0
+		for i, t := range target {
+			e := t - predicted[i]
+			if e >= 0 {
+				result += quantile * e
+			} else {
+				result += (quantile - 1) * e
+			}
+		}
+		result = scaleFactor / (1 + result/float64(len(predicted)))
+		return result, nil
+	}, nil
+}
+
 // RSquare returns a fitness function that is based on the standard R-square, which returns
 // the square of the Pearson product moment correlation coefficient. The return value
 // is normalized from 0 to scaleFactor.
+// The maximum fitness possible is scaleFactor.
 func RSquare(scaleFactor float64) (FloatFunc, error) {
 	return func(predicted, target []float64) (float64, error) {
-		if len(predicted) == 0 || len(target) == 0 || len(predicted) != len(target) {
-			return 0, ErrLength
+		if finite, err := check(predicted, target); !finite {
+			return 0, err
 		}
 		var sumP, sumT, sumPP, sumTP, sumTT float64
 		for i, t := range target {
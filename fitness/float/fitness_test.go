@@ -176,3 +176,156 @@ func TestRSquare(t *testing.T) {
 		t.Errorf("RSquare: got result %v, want %v", got, want)
 	}
 }
+
+func TestNumHitsWithPenalty(t *testing.T) {
+	tests := []struct {
+		precision, want float64
+		predicted       []float64
+	}{
+		{precision: 0.0, want: 2, predicted: predicted},
+		{precision: 0.5, want: 8, predicted: predicted},
+		{precision: 1.0, want: 12, predicted: predicted},
+		{precision: 1.0, want: 0, predicted: []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5}},
+	}
+	for i, test := range tests {
+		f, err := NumHitsWithPenalty(test.precision, 1)
+		if err != nil {
+			t.Errorf("NumHitsWithPenalty test %v: got NewHits error %v, want nil", i, err)
+		}
+		got, err := f(test.predicted, target)
+		if err != nil {
+			t.Errorf("NumHitsWithPenalty test %v: got error %v, want nil", i, err)
+		}
+		if got != test.want {
+			t.Errorf("NumHitsWithPenalty test %v: got result %v, want %v", i, got, test.want)
+		}
+	}
+}
+
+func TestRootMeanSquaredError(t *testing.T) {
+	f, err := RootMeanSquaredError(1000)
+	if err != nil {
+		t.Errorf("RootMeanSquaredError: got NewHits error %v, want nil", err)
+	}
+	got, err := f(predicted, target)
+	if err != nil {
+		t.Errorf("RootMeanSquaredError: got error %v, want nil", err)
+	}
+	if want := 637.6504; math.Abs(got-want) > 1e-4 {
+		t.Errorf("RootMeanSquaredError: got result %v, want %v", got, want)
+	}
+}
+
+func TestMeanAbsoluteError(t *testing.T) {
+	f, err := MeanAbsoluteError(1000)
+	if err != nil {
+		t.Errorf("MeanAbsoluteError: got NewHits error %v, want nil", err)
+	}
+	got, err := f(predicted, target)
+	if err != nil {
+		t.Errorf("MeanAbsoluteError: got error %v, want nil", err)
+	}
+	if want := 685.7143; math.Abs(got-want) > 1e-4 {
+		t.Errorf("MeanAbsoluteError: got result %v, want %v", got, want)
+	}
+}
+
+func TestRelativeAbsoluteError(t *testing.T) {
+	f, err := RelativeAbsoluteError(1000)
+	if err != nil {
+		t.Errorf("RelativeAbsoluteError: got NewHits error %v, want nil", err)
+	}
+	got, err := f(predicted, target)
+	if err != nil {
+		t.Errorf("RelativeAbsoluteError: got error %v, want nil", err)
+	}
+	if want := 421.0526; math.Abs(got-want) > 1e-4 {
+		t.Errorf("RelativeAbsoluteError: got result %v, want %v", got, want)
+	}
+}
+
+func TestHuberLoss(t *testing.T) {
+	tests := []struct{ delta, want float64 }{
+		{delta: 0.5, want: 880.7339},
+		{delta: 1.0, want: 860.9865},
+	}
+	for i, test := range tests {
+		f, err := HuberLoss(test.delta, 1000)
+		if err != nil {
+			t.Errorf("HuberLoss test %v: got NewHits error %v, want nil", i, err)
+		}
+		got, err := f(predicted, target)
+		if err != nil {
+			t.Errorf("HuberLoss test %v: got error %v, want nil", i, err)
+		}
+		if math.Abs(got-test.want) > 1e-4 {
+			t.Errorf("HuberLoss test %v: got result %v, want %v", i, got, test.want)
+		}
+	}
+	if _, err := HuberLoss(0, 1000); err == nil {
+		t.Error("HuberLoss(0, 1000): got nil error, want error")
+	}
+}
+
+func TestQuantileLoss(t *testing.T) {
+	target2 := []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0, 1.1, 1.2}
+	tests := []struct{ quantile, want float64 }{
+		{quantile: 0.1, want: 886.9180},
+		{quantile: 0.5, want: 842.1053},
+		{quantile: 0.9, want: 801.6032},
+	}
+	for i, test := range tests {
+		f, err := QuantileLoss(test.quantile, 1000)
+		if err != nil {
+			t.Errorf("QuantileLoss test %v: got NewHits error %v, want nil", i, err)
+		}
+		got, err := f(predicted, target2)
+		if err != nil {
+			t.Errorf("QuantileLoss test %v: got error %v, want nil", i, err)
+		}
+		if math.Abs(got-test.want) > 1e-4 {
+			t.Errorf("QuantileLoss test %v: got result %v, want %v", i, got, test.want)
+		}
+	}
+	if _, err := QuantileLoss(1, 1000); err == nil {
+		t.Error("QuantileLoss(1, 1000): got nil error, want error")
+	}
+}
+
+func TestNonFinite(t *testing.T) {
+	predicted2 := append([]float64{math.NaN()}, predicted[1:]...)
+	predicted3 := append([]float64{math.Inf(1)}, predicted[1:]...)
+	numHitsAbs, _ := NumHitsAbs(0.5, 1)
+	selectionRangeAbs, _ := SelectionRangeAbs(1, 1)
+	mse, _ := MeanSquaredErrorAbs(1000)
+	mae, _ := MeanAbsoluteError(1000)
+	huber, _ := HuberLoss(1, 1000)
+	rSquare, _ := RSquare(1000)
+	tests := []struct {
+		name string
+		f    FloatFunc
+		want float64
+	}{
+		{name: "NumHitsAbs", f: numHitsAbs, want: 7},
+		{name: "SelectionRangeAbs", f: selectionRangeAbs, want: 5.5},
+		{name: "MeanSquaredErrorAbs", f: mse, want: 0},
+		{name: "MeanAbsoluteError", f: mae, want: 0},
+		{name: "HuberLoss", f: huber, want: 0},
+		{name: "RSquare", f: rSquare, want: 0},
+	}
+	for _, test := range tests {
+		for _, p := range [][]float64{predicted2, predicted3} {
+			got, err := test.f(p, target)
+			if err != nil {
+				t.Errorf("%v(%v): got error %v, want nil", test.name, p[0], err)
+			}
+			if got != test.want {
+				t.Errorf("%v(%v): got result %v, want %v", test.name, p[0], got, test.want)
+			}
+		}
+		target2 := append([]float64{math.NaN()}, target[1:]...)
+		if _, err := test.f(predicted, target2); err != ErrTarget {
+			t.Errorf("%v: got error %v, want ErrTarget", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package gene
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Complexity describes the size of the expression tree that is actually
+// expressed by a gene (or genome), ignoring any non-coding symbols.
+type Complexity struct {
+	// Size is the number of expressed nodes (functions and terminals).
+	Size int
+	// Depth is the depth of the expression tree. A lone terminal has depth 1.
+	Depth int
+	// Inputs lists the distinct input indices (d0=0, d1=1, ...) that are
+	// used by the expression tree, in ascending order.
+	Inputs []int
+}
+
+// NumInputs returns the number of distinct inputs used.
+func (c Complexity) NumInputs() int {
+	return len(c.Inputs)
+}
+
+// Complexity returns the complexity of the expressed portion of the gene.
+func (g *Gene) Complexity() Complexity {
+	argOrder := g.getArgOrder()
+	inputs := map[int]bool{}
+	var walk func(symbolIndex int) (size, depth int)
+	walk = func(symbolIndex int) (size, depth int) {
+		if symbolIndex >= len(g.Symbols) {
+			return 0, 0
+		}
+		sym := g.Symbols[symbolIndex]
+		if sym[0:1] == "d" {
+			if index, err := strconv.Atoi(sym[1:]); err == nil {
+				inputs[index] = true
+			}
+		}
+		size = 1
+		for _, arg := range argOrder[symbolIndex] {
+			s, d := walk(arg)
+			size += s
+			if d > depth {
+				depth = d
+			}
+		}
+		return size, depth + 1
+	}
+
+	var c Complexity
+	c.Size, c.Depth = walk(0)
+	for k := range inputs {
+		c.Inputs = append(c.Inputs, k)
+	}
+	sort.Ints(c.Inputs)
+	return c
+}
@@ -0,0 +1,51 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package gene
+
+import (
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		gene     string
+		funcType functions.FuncType
+		want     Complexity
+	}{
+		{
+			gene:     "+.d0.d1.+.+.+.+.d0.d1.d1.d1.d0.d1.d1.d0",
+			funcType: functions.Float64,
+			want:     Complexity{Size: 3, Depth: 2, Inputs: []int{0, 1}},
+		},
+		{
+			gene:     "Or.And.Not.Not.Or.And.And.d0.d1.d1.d1.d0.d1.d1.d0",
+			funcType: functions.Bool,
+			want:     Complexity{Size: 13, Depth: 5, Inputs: []int{0, 1}},
+		},
+		{
+			gene:     "d2.*.d0.*.*.d0.d0.*.d0.d0.d0.d0.d0.d0.d0.d0.d0",
+			funcType: functions.Float64,
+			want:     Complexity{Size: 1, Depth: 1, Inputs: []int{2}},
+		},
+		{
+			gene:     "*.c0.+.d3.c1.c0.c0",
+			funcType: functions.Float64,
+			want:     Complexity{Size: 5, Depth: 3, Inputs: []int{3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.gene, func(t *testing.T) {
+			g := New(tt.gene, tt.funcType)
+			got := g.Complexity()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Complexity mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,557 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package gene
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gmlewis/gep/v2/functions"
+	mn "github.com/gmlewis/gep/v2/functions/math_nodes"
+)
+
+// Notation is a mathematical notation in which math genes can be rendered.
+type Notation int
+
+const (
+	// LaTeX renders the expression as a LaTeX math formula (without delimiters).
+	LaTeX Notation = iota
+	// MathML renders the expression as a presentation MathML <math> element.
+	MathML
+	// SymPy renders the expression as a string that sympy.parse_expr can parse.
+	SymPy
+)
+
+// Render renders the math gene in the notation n.
+// The inputs are named d_0, d_1, ... (d0, d1, ... for SymPy).
+func (g *Gene) Render(n Notation) (string, error) {
+	return Render(n, "", g)
+}
+
+// Render renders the math genes linked by the linking function linkFunc
+// (in the same order as genome.EvalMath links them) in the notation n.
+//
+// Functions with a closed form (such as Logi, Gau or Avg2) are expanded,
+// and the guards that the GEP functions use for degenerate inputs (such
+// as overflow in Logi or a base of zero in Log2) are not rendered.
+// The remaining functions (such as Mod or LT2A) are rendered as named
+// functions, which SymPy treats as undefined functions named gepMod, gepLT2A, etc.
+func Render(n Notation, linkFunc string, genes ...*Gene) (string, error) {
+	if len(genes) == 0 {
+		return "", errors.New("no genes to render")
+	}
+	var result *expr
+	for i, g := range genes {
+		if g.funcType != functions.Float64 {
+			return "", fmt.Errorf("unable to render gene %v: only math genes can be rendered", i)
+		}
+		e, err := g.tree(0, g.getArgOrder())
+		if err != nil {
+			return "", err
+		}
+		if i == 0 {
+			result = e
+			continue
+		}
+		if f, ok := mn.Math[linkFunc]; !ok || f.Terminals() != 2 {
+			return "", fmt.Errorf("unable to render linking function %q", linkFunc)
+		}
+		result = lower(linkFunc, []*expr{result, e})
+	}
+
+	p := printer{notation: n}
+	switch n {
+	case LaTeX, SymPy:
+		return p.print(result), nil
+	case MathML:
+		return `<math xmlns="http://www.w3.org/1998/Math/MathML">` + p.print(result) + "</math>", nil
+	}
+	return "", fmt.Errorf("unknown notation: %v", n)
+}
+
+// tree builds the expression tree of the gene starting at symbolIndex.
+func (g *Gene) tree(symbolIndex int, argOrder [][]int) (*expr, error) {
+	if symbolIndex >= len(g.Symbols) {
+		return nil, fmt.Errorf("bad symbolIndex %v for symbols: %v", symbolIndex, g.Symbols)
+	}
+	sym := g.Symbols[symbolIndex]
+	if _, ok := mn.Math[sym]; ok {
+		var args []*expr
+		for _, arg := range argOrder[symbolIndex] {
+			e, err := g.tree(arg, argOrder)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, e)
+		}
+		return lower(sym, args), nil
+	}
+
+	index, err := strconv.Atoi(sym[1:])
+	switch {
+	case err != nil:
+	case sym[0:1] == "d":
+		return &expr{op: opInput, index: index}, nil
+	case sym[0:1] == "c" && index < len(g.Constants):
+		return number(g.Constants[index]), nil
+	}
+	return nil, fmt.Errorf("unable to render symbol %q", sym)
+}
+
+// op is the operation of a node of an expression tree.
+type op int
+
+const (
+	opNumber op = iota
+	opInput
+	opPi
+	opE
+	opAdd
+	opSub
+	opMul
+	opDiv
+	opNeg
+	opPow
+	opRoot
+	opExp
+	opLog
+	opAbs
+	opFloor
+	opCeil
+	opCall
+)
+
+// expr is a node of the expression tree of a math gene.
+type expr struct {
+	op    op
+	value float64 // the value of a number
+	index int     // the index of an input or the degree of a root
+	real  bool    // whether a root of a negative number is negative
+	name  string  // the name of a function
+	args  []*expr // the log of args[0] is in base args[1] (if any)
+}
+
+func number(v float64) *expr { return &expr{op: opNumber, value: v} }
+
+func binary(o op, x, y *expr) *expr { return &expr{op: o, args: []*expr{x, y}} }
+
+func unary(o op, x *expr) *expr { return &expr{op: o, args: []*expr{x}} }
+
+// chain left-associates the binary operation over the arguments.
+func chain(o op, args []*expr) *expr {
+	result := args[0]
+	for _, arg := range args[1:] {
+		result = binary(o, result, arg)
+	}
+	return result
+}
+
+// lower translates a math function symbol to an expression.
+func lower(sym string, x []*expr) *expr {
+	switch sym {
+	case "+", "Add3", "Add4":
+		return chain(opAdd, x)
+	case "-", "Sub3", "Sub4":
+		return chain(opSub, x)
+	case "*", "Mul3", "Mul4":
+		return chain(opMul, x)
+	case "/", "Div3", "Div4":
+		return chain(opDiv, x)
+	case "Pow":
+		return binary(opPow, x[0], x[1])
+	case "Sqrt":
+		return &expr{op: opRoot, index: 2, args: x}
+	case "3Rt", "5Rt":
+		return &expr{op: opRoot, index: int(sym[0] - '0'), real: true, args: x}
+	case "4Rt":
+		return &expr{op: opRoot, index: 4, args: x}
+	case "Exp":
+		return unary(opExp, x[0])
+	case "Pow10":
+		return binary(opPow, number(10), x[0])
+	case "X2", "X3", "X4", "X5":
+		return binary(opPow, x[0], number(float64(sym[1]-'0')))
+	case "Ln":
+		return unary(opLog, x[0])
+	case "Log":
+		return binary(opLog, x[0], number(10))
+	case "Log2":
+		return binary(opLog, x[0], x[1])
+	case "Floor":
+		return unary(opFloor, x[0])
+	case "Ceil":
+		return unary(opCeil, x[0])
+	case "Abs":
+		return unary(opAbs, x[0])
+	case "Inv":
+		return binary(opDiv, number(1), x[0])
+	case "Neg":
+		return unary(opNeg, x[0])
+	case "Nop":
+		return x[0]
+	case "NOT":
+		return binary(opSub, number(1), x[0])
+	case "Avg2", "Avg3", "Avg4":
+		return binary(opDiv, chain(opAdd, x), number(float64(len(x))))
+	case "Logi", "Logi2", "Logi3", "Logi4":
+		return binary(opDiv, number(1), binary(opAdd, number(1), unary(opExp, unary(opNeg, chain(opAdd, x)))))
+	case "Gau", "Gau2", "Gau3", "Gau4":
+		return unary(opExp, unary(opNeg, binary(opPow, chain(opAdd, x), number(2))))
+	case "Zero", "Zero2":
+		return number(0)
+	case "One", "One2":
+		return number(1)
+	case "Pi":
+		return &expr{op: opPi}
+	case "E":
+		return &expr{op: opE}
+	case "Min2", "Min3", "Min4":
+		return &expr{op: opCall, name: "min", args: x}
+	case "Max2", "Max3", "Max4":
+		return &expr{op: opCall, name: "max", args: x}
+	}
+	if _, ok := functionNames[strings.ToLower(sym)]; ok {
+		return &expr{op: opCall, name: strings.ToLower(sym), args: x}
+	}
+	return &expr{op: opCall, name: sym, args: x}
+}
+
+// functionNames maps the named functions to their LaTeX, MathML and SymPy names.
+var functionNames = map[string][3]string{
+	"sin":   {`\sin`, "sin", "sin"},
+	"cos":   {`\cos`, "cos", "cos"},
+	"tan":   {`\tan`, "tan", "tan"},
+	"csc":   {`\csc`, "csc", "csc"},
+	"sec":   {`\sec`, "sec", "sec"},
+	"cot":   {`\cot`, "cot", "cot"},
+	"asin":  {`\arcsin`, "arcsin", "asin"},
+	"acos":  {`\arccos`, "arccos", "acos"},
+	"atan":  {`\arctan`, "arctan", "atan"},
+	"acsc":  {`\operatorname{arccsc}`, "arccsc", "acsc"},
+	"asec":  {`\operatorname{arcsec}`, "arcsec", "asec"},
+	"acot":  {`\operatorname{arccot}`, "arccot", "acot"},
+	"sinh":  {`\sinh`, "sinh", "sinh"},
+	"cosh":  {`\cosh`, "cosh", "cosh"},
+	"tanh":  {`\tanh`, "tanh", "tanh"},
+	"csch":  {`\operatorname{csch}`, "csch", "csch"},
+	"sech":  {`\operatorname{sech}`, "sech", "sech"},
+	"coth":  {`\coth`, "coth", "coth"},
+	"asinh": {`\operatorname{arsinh}`, "arsinh", "asinh"},
+	"acosh": {`\operatorname{arcosh}`, "arcosh", "acosh"},
+	"atanh": {`\operatorname{artanh}`, "artanh", "atanh"},
+	"acsch": {`\operatorname{arcsch}`, "arcsch", "acsch"},
+	"asech": {`\operatorname{arsech}`, "arsech", "asech"},
+	"acoth": {`\operatorname{arcoth}`, "arcoth", "acoth"},
+	"min":   {`\min`, "min", "Min"},
+	"max":   {`\max`, "max", "Max"},
+}
+
+// Precedence levels of the expressions, from the loosest to the tightest binding.
+const (
+	precSum = iota + 1
+	precProduct
+	precUnary
+	precPower
+	precAtom
+)
+
+// printer renders expression trees in a notation.
+type printer struct {
+	notation Notation
+}
+
+// prec returns the precedence of the expression as rendered by the printer.
+func (p printer) prec(e *expr) int {
+	fracs := p.notation != SymPy // fractions and roots are typeset
+	switch e.op {
+	case opAdd, opSub:
+		return precSum
+	case opMul:
+		return precProduct
+	case opDiv:
+		if fracs {
+			return precAtom
+		}
+		return precProduct
+	case opNeg:
+		return precUnary
+	case opPow:
+		return precPower
+	case opExp:
+		if fracs {
+			return precPower
+		}
+	case opNumber:
+		if math.Signbit(e.value) {
+			return precUnary
+		}
+		if fracs && strings.Contains(formatFloat(e.value), "e") {
+			return precProduct
+		}
+	}
+	return precAtom
+}
+
+// operand renders the operand of an operation, wrapped in parentheses if needed.
+func (p printer) operand(e *expr, wrap bool) string {
+	s := p.print(e)
+	if !wrap {
+		return s
+	}
+	switch p.notation {
+	case LaTeX:
+		return `\left(` + s + `\right)`
+	case MathML:
+		return "<mrow><mo>(</mo>" + s + "<mo>)</mo></mrow>"
+	}
+	return "(" + s + ")"
+}
+
+// operands renders the left and right operands of a binary operation.
+func (p printer) operands(e *expr) (string, string) {
+	x, y := e.args[0], e.args[1]
+	px, py := p.prec(x), p.prec(y)
+	switch e.op {
+	case opAdd:
+		return p.operand(x, false), p.operand(y, py == precUnary)
+	case opSub:
+		return p.operand(x, false), p.operand(y, py <= precSum || py == precUnary)
+	case opMul:
+		return p.operand(x, px < precProduct), p.operand(y, py < precProduct || py == precUnary)
+	case opDiv:
+		if p.notation != SymPy {
+			return p.print(x), p.print(y)
+		}
+		return p.operand(x, px < precProduct), p.operand(y, py <= precUnary)
+	}
+	// opPow: the exponent is raised, except in SymPy.
+	base := px <= precPower
+	if p.notation != SymPy {
+		switch x.op {
+		case opNumber:
+		case opInput, opPi, opE, opAbs, opFloor, opCeil:
+			base = false
+		default:
+			base = true
+		}
+	}
+	return p.operand(x, base), p.operand(y, p.notation == SymPy && py < precPower)
+}
+
+func (p printer) print(e *expr) string {
+	switch p.notation {
+	case LaTeX:
+		return p.latex(e)
+	case MathML:
+		return p.mathML(e)
+	}
+	return p.sympy(e)
+}
+
+func (p printer) args(e *expr, sep string) string {
+	args := make([]string, len(e.args))
+	for i, arg := range e.args {
+		args[i] = p.print(arg)
+	}
+	return strings.Join(args, sep)
+}
+
+func (p printer) latex(e *expr) string {
+	switch e.op {
+	case opNumber:
+		return latexNumber(e.value)
+	case opInput:
+		return fmt.Sprintf("d_{%v}", e.index)
+	case opPi:
+		return `\pi`
+	case opE:
+		return "e"
+	case opAdd, opSub, opMul:
+		x, y := p.operands(e)
+		return x + map[op]string{opAdd: " + ", opSub: " - ", opMul: ` \cdot `}[e.op] + y
+	case opDiv:
+		x, y := p.operands(e)
+		return `\frac{` + x + "}{" + y + "}"
+	case opNeg:
+		return "-" + p.operand(e.args[0], p.negWrap(e.args[0]))
+	case opPow:
+		x, y := p.operands(e)
+		return x + "^{" + y + "}"
+	case opExp:
+		return "e^{" + p.print(e.args[0]) + "}"
+	case opRoot:
+		if e.index == 2 {
+			return `\sqrt{` + p.print(e.args[0]) + "}"
+		}
+		return fmt.Sprintf(`\sqrt[%v]{%v}`, e.index, p.print(e.args[0]))
+	case opLog:
+		if len(e.args) == 1 {
+			return `\ln\left(` + p.print(e.args[0]) + `\right)`
+		}
+		return `\log_{` + p.print(e.args[1]) + `}\left(` + p.print(e.args[0]) + `\right)`
+	case opAbs:
+		return `\left|` + p.print(e.args[0]) + `\right|`
+	case opFloor:
+		return `\left\lfloor ` + p.print(e.args[0]) + `\right\rfloor`
+	case opCeil:
+		return `\left\lceil ` + p.print(e.args[0]) + `\right\rceil`
+	}
+	name := `\operatorname{` + e.name + "}"
+	if names, ok := functionNames[e.name]; ok {
+		name = names[0]
+	}
+	return name + `\left(` + p.args(e, ", ") + `\right)`
+}
+
+// negWrap reports whether the operand of a negation needs parentheses.
+func (p printer) negWrap(x *expr) bool {
+	px := p.prec(x)
+	return px < precProduct || px == precUnary
+}
+
+func latexNumber(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return `\mathrm{NaN}`
+	case math.IsInf(v, 0):
+		return strings.Replace(formatFloat(v), "Inf", `\infty`, 1)
+	}
+	s := formatFloat(v)
+	if i := strings.Index(s, "e"); i >= 0 {
+		return s[:i] + ` \times 10^{` + exponent(s[i+1:]) + "}"
+	}
+	return s
+}
+
+// exponent removes the sign and leading zeros of a positive exponent.
+func exponent(s string) string {
+	n, _ := strconv.Atoi(s)
+	return strconv.Itoa(n)
+}
+
+func (p printer) mathML(e *expr) string {
+	switch e.op {
+	case opNumber:
+		return mathMLNumber(e.value)
+	case opInput:
+		return fmt.Sprintf("<msub><mi>d</mi><mn>%v</mn></msub>", e.index)
+	case opPi:
+		return "<mi>&#x3C0;</mi>"
+	case opE:
+		return "<mi>e</mi>"
+	case opAdd, opSub, opMul:
+		x, y := p.operands(e)
+		return "<mrow>" + x + map[op]string{opAdd: "<mo>+</mo>", opSub: "<mo>&#x2212;</mo>", opMul: "<mo>&#x22C5;</mo>"}[e.op] + y + "</mrow>"
+	case opDiv:
+		x, y := p.operands(e)
+		return "<mfrac>" + x + y + "</mfrac>"
+	case opNeg:
+		return "<mrow><mo>&#x2212;</mo>" + p.operand(e.args[0], p.negWrap(e.args[0])) + "</mrow>"
+	case opPow:
+		x, y := p.operands(e)
+		return "<msup>" + x + y + "</msup>"
+	case opExp:
+		return "<msup><mi>e</mi>" + p.print(e.args[0]) + "</msup>"
+	case opRoot:
+		if e.index == 2 {
+			return "<msqrt>" + p.print(e.args[0]) + "</msqrt>"
+		}
+		return fmt.Sprintf("<mroot>%v<mn>%v</mn></mroot>", p.print(e.args[0]), e.index)
+	case opLog:
+		name := "<mi>ln</mi>"
+		if len(e.args) == 2 {
+			name = "<msub><mi>log</mi>" + p.print(e.args[1]) + "</msub>"
+		}
+		return "<mrow>" + name + "<mo>&#x2061;</mo><mrow><mo>(</mo>" + p.print(e.args[0]) + "<mo>)</mo></mrow></mrow>"
+	case opAbs:
+		return "<mrow><mo>|</mo>" + p.print(e.args[0]) + "<mo>|</mo></mrow>"
+	case opFloor:
+		return "<mrow><mo>&#x230A;</mo>" + p.print(e.args[0]) + "<mo>&#x230B;</mo></mrow>"
+	case opCeil:
+		return "<mrow><mo>&#x2308;</mo>" + p.print(e.args[0]) + "<mo>&#x2309;</mo></mrow>"
+	}
+	name := e.name
+	if names, ok := functionNames[e.name]; ok {
+		name = names[1]
+	}
+	return "<mrow><mi>" + name + "</mi><mo>&#x2061;</mo><mrow><mo>(</mo>" + p.args(e, "<mo>,</mo>") + "<mo>)</mo></mrow></mrow>"
+}
+
+func mathMLNumber(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "<mi>NaN</mi>"
+	case math.IsInf(v, 1):
+		return "<mi>&#x221E;</mi>"
+	case math.Signbit(v):
+		return "<mrow><mo>&#x2212;</mo>" + mathMLNumber(-v) + "</mrow>"
+	}
+	s := formatFloat(v)
+	if i := strings.Index(s, "e"); i >= 0 {
+		exp := exponent(s[i+1:])
+		if strings.HasPrefix(exp, "-") {
+			exp = "<mrow><mo>&#x2212;</mo><mn>" + exp[1:] + "</mn></mrow>"
+		} else {
+			exp = "<mn>" + exp + "</mn>"
+		}
+		return "<mrow><mn>" + s[:i] + "</mn><mo>&#xD7;</mo><msup><mn>10</mn>" + exp + "</msup></mrow>"
+	}
+	return "<mn>" + s + "</mn>"
+}
+
+func (p printer) sympy(e *expr) string {
+	switch e.op {
+	case opNumber:
+		switch {
+		case math.IsNaN(e.value):
+			return "nan"
+		case math.IsInf(e.value, 0):
+			return strings.Replace(formatFloat(e.value), "Inf", "oo", 1)
+		}
+		return formatFloat(e.value)
+	case opInput:
+		return fmt.Sprintf("d%v", e.index)
+	case opPi:
+		return "pi"
+	case opE:
+		return "E"
+	case opAdd, opSub, opMul, opDiv, opPow:
+		x, y := p.operands(e)
+		return x + map[op]string{opAdd: " + ", opSub: " - ", opMul: "*", opDiv: "/", opPow: "**"}[e.op] + y
+	case opNeg:
+		return "-" + p.operand(e.args[0], p.negWrap(e.args[0]))
+	case opExp:
+		return "exp(" + p.print(e.args[0]) + ")"
+	case opRoot:
+		switch {
+		case e.index == 2:
+			return "sqrt(" + p.print(e.args[0]) + ")"
+		case e.real:
+			return fmt.Sprintf("real_root(%v, %v)", p.print(e.args[0]), e.index)
+		}
+		return fmt.Sprintf("root(%v, %v)", p.print(e.args[0]), e.index)
+	case opLog:
+		return "log(" + p.args(e, ", ") + ")"
+	case opAbs:
+		return "Abs(" + p.print(e.args[0]) + ")"
+	case opFloor:
+		return "floor(" + p.print(e.args[0]) + ")"
+	case opCeil:
+		return "ceiling(" + p.print(e.args[0]) + ")"
+	}
+	name := "gep" + e.name
+	if names, ok := functionNames[e.name]; ok {
+		name = names[2]
+	}
+	return name + "(" + p.args(e, ", ") + ")"
+}
+
+// formatFloat formats v with the minimal number of digits, such as "3" or "0.5".
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
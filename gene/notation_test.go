@@ -0,0 +1,99 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package gene
+
+import (
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		karva  string
+		latex  string
+		sympy  string
+		mathML string
+	}{
+		{
+			karva:  "+.d0.*.d1.-.d0.d1",
+			latex:  `d_{0} + d_{1} \cdot \left(d_{0} - d_{1}\right)`,
+			sympy:  "d0 + d1*(d0 - d1)",
+			mathML: `<mrow><msub><mi>d</mi><mn>0</mn></msub><mo>+</mo><mrow><msub><mi>d</mi><mn>1</mn></msub><mo>&#x22C5;</mo><mrow><mo>(</mo><mrow><msub><mi>d</mi><mn>0</mn></msub><mo>&#x2212;</mo><msub><mi>d</mi><mn>1</mn></msub></mrow><mo>)</mo></mrow></mrow></mrow>`,
+		},
+		{
+			karva:  "/.+.Neg.d0.d1.c0",
+			latex:  `\frac{d_{0} + d_{1}}{-1.5 \times 10^{-7}}`,
+			sympy:  "(d0 + d1)/(-1.5e-07)",
+			mathML: `<mfrac><mrow><msub><mi>d</mi><mn>0</mn></msub><mo>+</mo><msub><mi>d</mi><mn>1</mn></msub></mrow><mrow><mo>&#x2212;</mo><mrow><mn>1.5</mn><mo>&#xD7;</mo><msup><mn>10</mn><mrow><mo>&#x2212;</mo><mn>7</mn></mrow></msup></mrow></mrow></mfrac>`,
+		},
+		{
+			karva:  "X2.Neg.d0",
+			latex:  `\left(-d_{0}\right)^{2}`,
+			sympy:  "(-d0)**2",
+			mathML: `<msup><mrow><mo>(</mo><mrow><mo>&#x2212;</mo><msub><mi>d</mi><mn>0</mn></msub></mrow><mo>)</mo></mrow><mn>2</mn></msup>`,
+		},
+		{
+			karva:  "Logi.*.d0.d1",
+			latex:  `\frac{1}{1 + e^{-d_{0} \cdot d_{1}}}`,
+			sympy:  "1/(1 + exp(-d0*d1))",
+			mathML: `<mfrac><mn>1</mn><mrow><mn>1</mn><mo>+</mo><msup><mi>e</mi><mrow><mo>&#x2212;</mo><mrow><msub><mi>d</mi><mn>0</mn></msub><mo>&#x22C5;</mo><msub><mi>d</mi><mn>1</mn></msub></mrow></mrow></msup></mrow></mfrac>`,
+		},
+		{
+			karva:  "Sub3.d0.Neg.+.d1.c1.d0.d1",
+			latex:  `d_{0} - \left(-d_{1}\right) - \left(-2 + d_{0}\right)`,
+			sympy:  "d0 - (-d1) - (-2 + d0)",
+			mathML: `<mrow><mrow><msub><mi>d</mi><mn>0</mn></msub><mo>&#x2212;</mo><mrow><mo>(</mo><mrow><mo>&#x2212;</mo><msub><mi>d</mi><mn>1</mn></msub></mrow><mo>)</mo></mrow></mrow><mo>&#x2212;</mo><mrow><mo>(</mo><mrow><mrow><mo>&#x2212;</mo><mn>2</mn></mrow><mo>+</mo><msub><mi>d</mi><mn>0</mn></msub></mrow><mo>)</mo></mrow></mrow>`,
+		},
+		{
+			karva:  "Mod.3Rt.Avg2.d0.d0.d1",
+			latex:  `\operatorname{Mod}\left(\sqrt[3]{d_{0}}, \frac{d_{0} + d_{1}}{2}\right)`,
+			sympy:  "gepMod(real_root(d0, 3), (d0 + d1)/2)",
+			mathML: `<mrow><mi>Mod</mi><mo>&#x2061;</mo><mrow><mo>(</mo><mroot><msub><mi>d</mi><mn>0</mn></msub><mn>3</mn></mroot><mo>,</mo><mfrac><mrow><msub><mi>d</mi><mn>0</mn></msub><mo>+</mo><msub><mi>d</mi><mn>1</mn></msub></mrow><mn>2</mn></mfrac><mo>)</mo></mrow></mrow>`,
+		},
+		{
+			karva:  "Log2.Abs.Floor.d0.d1",
+			latex:  `\log_{\left\lfloor d_{1}\right\rfloor}\left(\left|d_{0}\right|\right)`,
+			sympy:  "log(Abs(d0), floor(d1))",
+			mathML: `<mrow><msub><mi>log</mi><mrow><mo>&#x230A;</mo><msub><mi>d</mi><mn>1</mn></msub><mo>&#x230B;</mo></mrow></msub><mo>&#x2061;</mo><mrow><mo>(</mo><mrow><mo>|</mo><msub><mi>d</mi><mn>0</mn></msub><mo>|</mo></mrow><mo>)</mo></mrow></mrow>`,
+		},
+		{
+			karva:  "Pow.Pow.d0.Sin.Pi.d1.d0",
+			latex:  `\left(\left(\sin\left(d_{1}\right)\right)^{\pi}\right)^{d_{0}}`,
+			sympy:  "(sin(d1)**pi)**d0",
+			mathML: `<msup><mrow><mo>(</mo><msup><mrow><mo>(</mo><mrow><mi>sin</mi><mo>&#x2061;</mo><mrow><mo>(</mo><msub><mi>d</mi><mn>1</mn></msub><mo>)</mo></mrow></mrow><mo>)</mo></mrow><mi>&#x3C0;</mi></msup><mo>)</mo></mrow><msub><mi>d</mi><mn>0</mn></msub></msup>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.karva, func(t *testing.T) {
+			g := New(tt.karva, functions.Float64)
+			g.Constants = []float64{1.5e-7, -2}
+			for n, want := range map[Notation]string{
+				LaTeX:  tt.latex,
+				SymPy:  tt.sympy,
+				MathML: `<math xmlns="http://www.w3.org/1998/Math/MathML">` + tt.mathML + "</math>",
+			} {
+				got, err := g.Render(n)
+				if err != nil {
+					t.Fatalf("Render(%v): %v", n, err)
+				}
+				if got != want {
+					t.Errorf("Render(%v) =\n%v\nwant:\n%v", n, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	if _, err := New("And.d0.d1", functions.Bool).Render(LaTeX); err == nil {
+		t.Error("Render(boolean gene) = nil error, want error")
+	}
+	g := New("+.d0.d1", functions.Float64)
+	if _, err := Render(SymPy, "Sin", g, g); err == nil {
+		t.Error("Render(linking function Sin) = nil error, want error")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/gmlewis/gep/v2/functions"
 	"github.com/gmlewis/gep/v2/grammars"
 )
 
@@ -37,15 +38,18 @@ func (g *Gene) buildExp(symbolIndex int, argOrder [][]int, grammar *grammars.Gra
 			log.Fatalf("programming error: symbol %q args length mismatch: len(args)=%v, want %v; check FuncType", sym, len(args), f.Terminals())
 		}
 
+		// Replace all the arguments in a single pass so that an argument
+		// such as "gepMax2(d[0],d[1])" is not mistaken for "x2".
+		var oldnew []string
 		for i := 0; i < f.Terminals(); i++ {
 			e, err := g.buildExp(args[i], argOrder, grammar, helpers)
 			if err != nil {
 				return "", err
 			}
-			exp = strings.Replace(exp, "x"+strconv.Itoa(i), e, -1)
+			oldnew = append(oldnew, "x"+strconv.Itoa(i), e)
 		}
 
-		return exp, nil
+		return strings.NewReplacer(oldnew...).Replace(exp), nil
 	}
 
 	// No named symbol found - look for d0, d1, ... or constants c0, c1, ...
@@ -70,12 +74,43 @@ func (g *Gene) buildExp(symbolIndex int, argOrder [][]int, grammar *grammars.Gra
 		if index > len(g.Constants) {
 			log.Fatalf("programming error: constant symbol name %q exceeds length of constant slice (%v)", sym, len(g.Constants))
 		}
-		return fmt.Sprintf("%v", g.Constants[index]), nil
+		switch g.funcType {
+		case functions.Int:
+			// Integer models truncate their constants when evaluated.
+			return strconv.Itoa(int(g.Constants[index])), nil
+		case functions.VectorInts:
+			return g.vectorConstant(index, grammar, helpers)
+		}
+		// Render the constant as a floating-point literal so that
+		// targets such as C and Java never use integer arithmetic.
+		c := strconv.FormatFloat(g.Constants[index], 'g', -1, 64)
+		if !strings.ContainsAny(c, ".eEnN") {
+			c += ".0"
+		}
+		return c, nil
 	}
 
 	return "", fmt.Errorf("unable to render function: sym=%v for gene %#v", sym, g)
 }
 
+// vectorConstant renders the constant as a vector using the grammar's
+// "vector" random constant, since a vector of integers model broadcasts
+// each constant to the length of its inputs.
+func (g *Gene) vectorConstant(index int, grammar *grammars.Grammar, helpers grammars.HelperMap) (string, error) {
+	for _, r := range grammar.RandomConstants {
+		if r.Type != "vector" {
+			continue
+		}
+		if _, ok := helpers["c"]; !ok {
+			if v, ok := grammar.Helpers.HelperMap["c"]; ok {
+				helpers["c"] = v
+			}
+		}
+		return strings.Replace(r.Chardata, "{value}", strconv.Itoa(int(g.Constants[index])), -1), nil
+	}
+	return "", fmt.Errorf("grammar %q has no vector random constant for constant c%v", grammar.Name, index)
+}
+
 // Expression builds up the expression tree and returns the resulting string.
 // While building, it keeps track of any helper functions that are needed.
 func (g *Gene) Expression(grammar *grammars.Grammar, helpers grammars.HelperMap) (string, error) {
@@ -57,3 +57,41 @@ func TestConstants_Float64(t *testing.T) {
 		t.Errorf("helpers got length %v, want 0", len(helpers))
 	}
 }
+
+func TestConstants_Integral(t *testing.T) {
+	want := "(d[0]/3.0)"
+	g := New("/.d0.c0.d0.d1", functions.Float64)
+	g.Constants = []float64{3}
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+
+	got, err := g.Expression(grammar, make(grammars.HelperMap))
+	if err != nil {
+		t.Fatalf("g.Expression error: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("g.Expression got %q, want %q", got, want)
+	}
+}
+
+func TestConstants_Int(t *testing.T) {
+	want := "(d[0] + 3)"
+	g := New("+.d0.c0.d0.d1", functions.Int)
+	g.Constants = []float64{3.7}
+	grammar, err := grammars.LoadGoIntGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoIntGrammar(): %v", err)
+	}
+
+	got, err := g.Expression(grammar, make(grammars.HelperMap))
+	if err != nil {
+		t.Fatalf("g.Expression error: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("g.Expression got %q, want %q", got, want)
+	}
+}
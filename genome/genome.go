@@ -12,13 +12,16 @@
 package genome
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
+	"sort"
 	"strings"
 
 	"github.com/gmlewis/gep/v2/gene"
 	"github.com/gmlewis/gep/v2/grammars"
+	"github.com/gmlewis/gep/v2/transform"
 )
 
 // Genome contains the genes that make up the genome.
@@ -28,6 +31,13 @@ type Genome struct {
 	Genes    []*gene.Gene
 	LinkFunc string
 	Score    float64
+	// Objectives holds the values returned by a MultiScoringFunc
+	// when the model is run in multi-objective mode.
+	Objectives []float64
+	// Pipeline, if set, transforms the inputs and reverses the transformation
+	// of the target when the genome is evaluated with EvalMath and is written
+	// into the generated code by Write.
+	Pipeline *transform.Pipeline
 
 	SymbolMap map[string]int // do not use directly.  Use SymbolCount() instead.
 }
@@ -61,6 +71,33 @@ func (g *Genome) SymbolCount(sym string) int {
 	return g.SymbolMap[sym]
 }
 
+// Complexity returns the complexity of the expressed portion of the genome.
+// Unless the genome uses the "tuple" linking function, each linking function
+// application counts as an additional node in the resulting expression tree.
+func (g *Genome) Complexity() gene.Complexity {
+	var result gene.Complexity
+	inputs := map[int]bool{}
+	for i, gn := range g.Genes {
+		c := gn.Complexity()
+		result.Size += c.Size
+		for _, v := range c.Inputs {
+			inputs[v] = true
+		}
+		switch {
+		case i == 0 || g.LinkFunc == "tuple":
+			result.Depth = max(result.Depth, c.Depth)
+		default:
+			result.Size++
+			result.Depth = 1 + max(result.Depth, c.Depth)
+		}
+	}
+	for k := range inputs {
+		result.Inputs = append(result.Inputs, k)
+	}
+	sort.Ints(result.Inputs)
+	return result
+}
+
 // String returns the Karva representation of the genome.
 func (g Genome) String() string {
 	var result []string
@@ -113,6 +150,10 @@ func (g *Genome) Dup() *Genome {
 		Genes:    make([]*gene.Gene, len(g.Genes)),
 		LinkFunc: g.LinkFunc,
 		Score:    g.Score,
+		Pipeline: g.Pipeline,
+	}
+	if g.Objectives != nil {
+		dst.Objectives = append([]float64{}, g.Objectives...)
 	}
 	for i := range g.Genes {
 		dst.Genes[i] = g.Genes[i].Dup()
@@ -134,6 +175,21 @@ func (g *Genome) EvaluateWithScore(sf ScoringFunc, c chan<- *Genome) {
 	c <- g
 }
 
+// MultiScoringFunc is the function that is used to evaluate the fitness of the
+// model against several objectives at once. Every objective is maximized, so
+// an objective that should be minimized (such as program size) must be negated.
+type MultiScoringFunc func(g *Genome) []float64
+
+// EvaluateWithObjectives scores a genome against several objectives,
+// storing the result in g.Objectives.
+func (g *Genome) EvaluateWithObjectives(msf MultiScoringFunc) error {
+	if msf == nil {
+		return errors.New("genome.EvaluateWithObjectives: MultiScoringFunc must not be nil")
+	}
+	g.Objectives = msf(g)
+	return nil
+}
+
 // Evaluate runs the model with the observations and populates the provided action
 // based on the link function.
 func (g *Genome) Evaluate(observations []int, action any) error {
@@ -890,6 +890,34 @@ func TestDup(t *testing.T) {
 	validateFuelConsumption(t, mux)
 }
 
+func TestComplexity(t *testing.T) {
+	gn := New([]*gene.Gene{
+		gene.New("+.d0.d1.+.+.+.+.d0.d1.d1.d1.d0.d1.d1.d0", functions.Float64),
+		gene.New("*.d2.*.d0.d0.d0.d0", functions.Float64),
+	}, "+")
+	want := gene.Complexity{Size: 9, Depth: 4, Inputs: []int{0, 1, 2}}
+	if got := gn.Complexity(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Complexity() = %+v, want %+v", got, want)
+	}
+
+	gn.LinkFunc = "tuple"
+	want = gene.Complexity{Size: 8, Depth: 3, Inputs: []int{0, 1, 2}}
+	if got := gn.Complexity(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Complexity() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEvalMathTuple(t *testing.T) {
+	gn := New([]*gene.Gene{
+		gene.New("+.d0.d1.+.+.+.+.d0.d1.d1.d1.d0.d1.d1.d0", functions.Float64),
+		gene.New("*.d2.*.d0.d0.d0.d0", functions.Float64),
+	}, "tuple")
+	want := []float64{5, 16}
+	if got := gn.EvalMathTuple([]float64{2, 3, 4}); !reflect.DeepEqual(got, want) {
+		t.Errorf("EvalMathTuple() = %v, want %v", got, want)
+	}
+}
+
 func TestMutate(t *testing.T) {
 	headSize := 7
 	maxArity := 2
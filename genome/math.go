@@ -12,7 +12,16 @@ import (
 
 // EvalMath evaluates the genome as a floating-point expression and returns the result.
 // in represents the float64 inputs available to the genome.
+// If the genome has a Pipeline, in holds the raw inputs and the result
+// is in raw target units.
 func (g *Genome) EvalMath(in []float64) float64 {
+	if g.Pipeline != nil {
+		return g.Pipeline.ReverseTarget(g.evalMath(g.Pipeline.Transform(in)))
+	}
+	return g.evalMath(in)
+}
+
+func (g *Genome) evalMath(in []float64) float64 {
 	lf, ok := mn.Math[g.LinkFunc]
 	if !ok {
 		log.Printf("Unable to find linking function: %v", g.LinkFunc)
@@ -25,3 +34,14 @@ func (g *Genome) EvalMath(in []float64) float64 {
 	}
 	return result
 }
+
+// EvalMathTuple evaluates the genome by evaluating each gene as a
+// floating-point expression and assigning its output to each element
+// of the tuple. The Pipeline, if any, is not applied.
+func (g *Genome) EvalMathTuple(in []float64) []float64 {
+	result := make([]float64, len(g.Genes))
+	for i, gene := range g.Genes {
+		result[i] = gene.EvalMath(in)
+	}
+	return result
+}
@@ -0,0 +1,17 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package genome
+
+import (
+	"github.com/gmlewis/gep/v2/gene"
+)
+
+// Render renders the math genome in the notation n, such as gene.LaTeX,
+// gene.MathML or gene.SymPy, for publishing or for cross-checking the
+// model in a notebook. Any Pipeline of the genome is not rendered, so
+// the inputs and output are in transformed units.
+func (g *Genome) Render(n gene.Notation) (string, error) {
+	return gene.Render(n, g.LinkFunc, g.Genes...)
+}
@@ -0,0 +1,33 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package genome
+
+import (
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+	"github.com/gmlewis/gep/v2/gene"
+)
+
+func TestRender(t *testing.T) {
+	g1 := gene.New("Sqrt.X2.d0", functions.Float64)
+	g2 := gene.New("-.d1.Gau.d0", functions.Float64)
+	g3 := gene.New("Inv.d1", functions.Float64)
+	gn := New([]*gene.Gene{g1, g2, g3}, "*")
+
+	tests := map[gene.Notation]string{
+		gene.LaTeX: `\sqrt{d_{0}^{2}} \cdot \left(d_{1} - e^{-d_{0}^{2}}\right) \cdot \frac{1}{d_{1}}`,
+		gene.SymPy: "sqrt(d0**2)*(d1 - exp(-d0**2))*1/d1",
+	}
+	for n, want := range tests {
+		got, err := gn.Render(n)
+		if err != nil {
+			t.Fatalf("Render(%v): %v", n, err)
+		}
+		if got != want {
+			t.Errorf("Render(%v) = %q, want %q", n, got, want)
+		}
+	}
+}
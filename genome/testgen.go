@@ -0,0 +1,123 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package genome
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gmlewis/gep/v2/grammars"
+)
+
+// TestCases are the fitness cases checked by the test file written by
+// WriteWithTests. They are either MathCases or BoolCases.
+type TestCases interface {
+	// results returns the formatted inputs of each case and the
+	// formatted output of the genome for each case.
+	results(g *Genome) (inputs [][]string, want []string)
+}
+
+// MathCases are the inputs of fitness cases checked against EvalMath.
+type MathCases [][]float64
+
+func (c MathCases) results(g *Genome) ([][]string, []string) {
+	inputs := make([][]string, len(c))
+	want := make([]string, len(c))
+	for i, in := range c {
+		for _, v := range in {
+			inputs[i] = append(inputs[i], goFloat(v))
+		}
+		want[i] = goFloat(g.EvalMath(in))
+	}
+	return inputs, want
+}
+
+// BoolCases are the inputs of fitness cases checked against EvalBool.
+type BoolCases [][]bool
+
+func (c BoolCases) results(g *Genome) ([][]string, []string) {
+	inputs := make([][]string, len(c))
+	want := make([]string, len(c))
+	for i, in := range c {
+		for _, v := range in {
+			inputs[i] = append(inputs[i], strconv.FormatBool(v))
+		}
+		want[i] = strconv.FormatBool(g.EvalBool(in))
+	}
+	return inputs, want
+}
+
+// goFloat formats v as a Go expression that evaluates to exactly v.
+func goFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "math.NaN()"
+	case math.IsInf(v, 1):
+		return "math.Inf(1)"
+	case math.IsInf(v, -1):
+		return "math.Inf(-1)"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// WriteWithTests generates the source code of the genome like Write,
+// and also writes to tw a Go test file, using the Testing section of
+// the grammar, that checks that the generated gepModel reproduces the
+// output of the genome's own EvalMath (or EvalBool) for each of the cases.
+func (g *Genome) WriteWithTests(w, tw io.Writer, grammar *grammars.Grammar, cases TestCases, opts ...WriteOption) error {
+	m := grammar.Testing.Method
+	if strings.TrimSpace(m.Chardata) == "" || m.Callformat == "" {
+		return errors.New("genome.WriteWithTests: the grammar has no testing method")
+	}
+	if g.Pipeline != nil && g.Pipeline.HasCategorical() {
+		return errors.New("genome.WriteWithTests: categorical inputs are not supported")
+	}
+
+	code, err := newDump(g, grammar, opts).generateCode()
+	if err != nil {
+		return fmt.Errorf("genome.WriteWithTests: %v", err)
+	}
+
+	inputs, want := cases.results(g)
+	var calls strings.Builder
+	for i, in := range inputs {
+		data := make([]string, len(in))
+		for j, v := range in {
+			data[j] = strings.Replace(m.Listformat, "{data}", v, -1)
+		}
+		calls.WriteString(strings.NewReplacer(
+			"{method}", "gepModel",
+			"{params}", strings.Join(data, ", "),
+			"{result}", want[i],
+		).Replace(m.Callformat))
+	}
+
+	test := strings.NewReplacer(
+		"{CALLS}", calls.String(),
+		"{HELPERS}", "",
+		"{FUNCTIONS}", "",
+		"{method}", "gepModel",
+	).Replace(m.Chardata)
+	test = strings.NewReplacer("{CRLF}", "\n", "{TAB}", "\t").Replace(test)
+	testCode := []byte(strings.TrimLeft(test, " \t\r\n"))
+	if grammar.Ext == "go" {
+		clean, err := format.Source(testCode)
+		if err != nil {
+			return fmt.Errorf("genome.WriteWithTests: test file: %v", err)
+		}
+		testCode = clean
+	}
+
+	if _, err := w.Write(code); err != nil {
+		return err
+	}
+	_, err = tw.Write(testCode)
+	return err
+}
@@ -0,0 +1,170 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package genome
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+	"github.com/gmlewis/gep/v2/gene"
+	"github.com/gmlewis/gep/v2/grammars"
+	"github.com/gmlewis/gep/v2/transform"
+)
+
+func TestWriteWithTestsMath(t *testing.T) {
+	wantCode := `package gepModel
+
+import (
+	"math"
+)
+
+func gepModel(d []float64) float64 {
+	var y float64
+
+	y = (math.Log(d[0]) / d[1])
+	y = math.Max(y, (d[0] * d[1]))
+
+	return y
+}
+`
+	wantTest := `package gepModel
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGepModel(t *testing.T) {
+	tests := []struct {
+		d    []float64
+		want float64
+	}{
+		{[]float64{1, 2}, 2},
+		{[]float64{2.5, 0.5}, 1.8325814637483102},
+		{[]float64{0, 0}, 0},
+		{[]float64{1, 0}, math.NaN()},
+		{[]float64{0, 1}, 0},
+	}
+
+	for i, tt := range tests {
+		in := append([]float64(nil), tt.d...)
+		if got := gepModel(tt.d); got != tt.want && !(math.IsNaN(got) && math.IsNaN(tt.want)) {
+			t.Errorf("test #%v: gepModel(%v) = %v, want %v", i, in, got, tt.want)
+		}
+	}
+}
+`
+
+	g1 := gene.New("/.Ln.d1.d0.d1.d0.d1", functions.Float64)
+	g2 := gene.New("*.d0.d1.d0.d1.d0.d1", functions.Float64)
+	gn := New([]*gene.Gene{g1, g2}, "Max2")
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+
+	code, test := new(bytes.Buffer), new(bytes.Buffer)
+	cases := MathCases{{1, 2}, {2.5, 0.5}, {0, 0}, {1, 0}, {0, 1}}
+	if err := gn.WriteWithTests(code, test, grammar, cases); err != nil {
+		t.Fatalf("WriteWithTests: %v", err)
+	}
+	if code.String() != wantCode {
+		t.Errorf("WriteWithTests code got:\n%v\nwant:\n%v", code.String(), wantCode)
+	}
+	if test.String() != wantTest {
+		t.Errorf("WriteWithTests test got:\n%v\nwant:\n%v", test.String(), wantTest)
+	}
+	goTest(t, map[string]string{"model.go": code.String(), "model_test.go": test.String()})
+}
+
+func TestWriteWithTestsPipeline(t *testing.T) {
+	in := [][]float64{{1, 10}, {3, 30}, {-2, 7}}
+	target := []float64{3, 4, 1}
+	p, err := transform.Fit(transform.Standardization, transform.MinMaxNormalization, in, target)
+	if err != nil {
+		t.Fatalf("transform.Fit: %v", err)
+	}
+	g1 := gene.New("+.*.Sqrt.d0.d1.d0.d1", functions.Float64)
+	g2 := gene.New("Exp.d1", functions.Float64)
+	gn := New([]*gene.Gene{g1, g2}, "-")
+	gn.Pipeline = p
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+
+	code, test := new(bytes.Buffer), new(bytes.Buffer)
+	cases := MathCases{{1, 10}, {3, 30}, {-2, 7}, {0.1, -0.3}, {1e6, -1e-6}}
+	if err := gn.WriteWithTests(code, test, grammar, cases); err != nil {
+		t.Fatalf("WriteWithTests: %v", err)
+	}
+	// The generated test compares exactly, so the generated model must
+	// reproduce EvalMath bit for bit, transformations included.
+	goTest(t, map[string]string{"model.go": code.String(), "model_test.go": test.String()})
+}
+
+func TestWriteWithTestsBool(t *testing.T) {
+	wantTest := `package gepModel
+
+import "testing"
+
+func TestGepModel(t *testing.T) {
+	tests := []struct {
+		d    []bool
+		want bool
+	}{
+		{[]bool{false, false}, true},
+		{[]bool{false, true}, true},
+		{[]bool{true, false}, true},
+		{[]bool{true, true}, false},
+	}
+
+	for i, tt := range tests {
+		if got := gepModel(tt.d); got != tt.want {
+			t.Errorf("test #%v: gepModel(%v) = %v, want %v", i, tt.d, got, tt.want)
+		}
+	}
+}
+`
+
+	g1 := gene.New("Nand.d0.d1.d0.d1", functions.Bool)
+	gn := New([]*gene.Gene{g1}, "Nand")
+	grammar, err := grammars.LoadGoBooleanNandGatesGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoBooleanNandGatesGrammar(): %v", err)
+	}
+
+	code, test := new(bytes.Buffer), new(bytes.Buffer)
+	cases := BoolCases{{false, false}, {false, true}, {true, false}, {true, true}}
+	if err := gn.WriteWithTests(code, test, grammar, cases); err != nil {
+		t.Fatalf("WriteWithTests: %v", err)
+	}
+	want := new(bytes.Buffer)
+	gn.Write(want, grammar)
+	if code.String() != want.String() {
+		t.Errorf("WriteWithTests code got:\n%v\nwant:\n%v", code.String(), want.String())
+	}
+	if test.String() != wantTest {
+		t.Errorf("WriteWithTests test got:\n%v\nwant:\n%v", test.String(), wantTest)
+	}
+}
+
+func TestWriteWithTestsNoTesting(t *testing.T) {
+	g1 := gene.New("+.d0.d1", functions.Float64)
+	gn := New([]*gene.Gene{g1}, "+")
+	grammar, err := grammars.LoadPythonMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadPythonMathGrammar(): %v", err)
+	}
+
+	code, test := new(bytes.Buffer), new(bytes.Buffer)
+	if err := gn.WriteWithTests(code, test, grammar, MathCases{{1, 2}}); err == nil {
+		t.Error("WriteWithTests: expected error for grammar without a testing method")
+	}
+	if code.Len() != 0 || test.Len() != 0 {
+		t.Errorf("WriteWithTests wrote %v and %v bytes, want none", code.Len(), test.Len())
+	}
+}
@@ -10,9 +10,11 @@ import (
 	"go/format"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/gmlewis/gep/v2/grammars"
+	"github.com/gmlewis/gep/v2/transform"
 )
 
 type dump struct {
@@ -21,111 +23,576 @@ type dump struct {
 	// fm     functions.FuncMap
 	genome *Genome
 	subs   map[string]string
+
+	// options
+	inputNames []string
+	comments   []string
+	labels     []string
+
+	// state computed by prepare
+	replacementType string
+	categorical     bool
+	exps            []string
+	helpers         grammars.HelperMap
+	inputT          *grammars.Transformation
+	reverseT        *grammars.Transformation
+}
+
+// WriteOption represents an option that can modify the generated code.
+type WriteOption func(d *dump)
+
+// WithInputNames adds a comment to the generated code for each input
+// d[i] naming it names[i], such as the column names of a dataset.
+func WithInputNames(names []string) WriteOption {
+	return func(d *dump) {
+		d.inputNames = names
+	}
+}
+
+// WithComments adds the lines as comments at the top of the generated
+// code (the ModelComments section of the grammar).
+func WithComments(lines ...string) WriteOption {
+	return func(d *dump) {
+		d.comments = lines
+	}
+}
+
+// WithLabels declares a constant for each class label using the Constants
+// section of the grammar, where labels[i] has the value i (plus the
+// labelindex offset of the grammar).
+func WithLabels(labels []string) WriteOption {
+	return func(d *dump) {
+		d.labels = labels
+	}
+}
+
+// Write generates the source code of the genome using the provided grammar.
+func (g *Genome) Write(w io.Writer, grammar *grammars.Grammar, opts ...WriteOption) {
+	code, err := g.Generate(grammar, opts...)
+	if err != nil {
+		fmt.Printf("error generating code: %v", err)
+	}
+
+	fmt.Fprintf(w, "%s", code)
+}
+
+// Generate returns the source code of the genome like Write, but returns
+// any error instead of printing it.
+func (g *Genome) Generate(grammar *grammars.Grammar, opts ...WriteOption) ([]byte, error) {
+	return newDump(g, grammar, opts).generateCode()
 }
 
-func (g *Genome) Write(w io.Writer, grammar *grammars.Grammar) {
+func newDump(g *Genome, grammar *grammars.Grammar, opts []WriteOption) *dump {
 	d := &dump{
 		gr:     grammar,
 		genome: g,
 		subs: map[string]string{
-			"CHARX": "X",
+			"CHARX": "x",
 		},
 	}
-
-	code, err := d.generateCode()
-	if err != nil {
-		fmt.Printf("error generating code: %v", err)
+	for _, f := range opts {
+		f(d)
 	}
+	return d
+}
 
-	fmt.Fprintf(w, "%s", code)
+// defaultOrder is the code structure used when the grammar has no <order>.
+var defaultOrder = []string{
+	"ModelComments", "Open", "HelpersDeclaration", "Prototypes", "Subheader", "Header",
+	"RandomConstants", "Constants", "CallTransform", "DataTransformationCall",
+	"HelpersAssignment", "TemporaryVariable", "Body", "DataReverseTransformationCall",
+	"Footer", "Helpers", "BasicFunctions", "LinkingHelpers", "DDF", "UDF", "TransformHelper",
+	"DataTransformationHelper", "DataReverseTransformationHelper", "Close",
+}
+
+// sections maps the name of each item of the grammar's <order> to the
+// function that writes it.
+var sections = map[string]func(d *dump){
+	"ModelComments":                   (*dump).writeModelComments,
+	"Open":                            (*dump).writeOpen,
+	"HelpersDeclaration":              (*dump).writeHelpersDeclaration,
+	"Prototypes":                      (*dump).writePrototypes,
+	"Subheader":                       (*dump).writeSubheader,
+	"Header":                          (*dump).writeHeader,
+	"RandomConstants":                 (*dump).writeRandomConstants,
+	"Constants":                       (*dump).writeConstants,
+	"CallTransform":                   (*dump).writeCallTransform,
+	"DataTransformationCall":          func(d *dump) { d.writeCall(d.inputT) },
+	"HelpersAssignment":               (*dump).writeHelpersAssignment,
+	"TemporaryVariable":               (*dump).writeTemporaryVariable,
+	"Body":                            (*dump).writeBody,
+	"DataReverseTransformationCall":   func(d *dump) { d.writeCall(d.reverseT) },
+	"Footer":                          (*dump).writeFooter,
+	"Helpers":                         (*dump).writeHelpers,
+	"BasicFunctions":                  (*dump).writeBasicFunctions,
+	"LinkingHelpers":                  (*dump).writeLinkingHelpers,
+	"DDF":                             func(d *dump) {}, // dynamic functions are not supported
+	"UDF":                             func(d *dump) {}, // user-defined functions are not supported
+	"TransformHelper":                 (*dump).writeCategoricalTransformation,
+	"DataTransformationHelper":        (*dump).writeInputTransformation,
+	"DataReverseTransformationHelper": (*dump).writeReverseTransformation,
+	"Close":                           func(d *dump) { d.write(d.gr.Close) },
 }
 
 func (d *dump) generateCode() ([]byte, error) {
-	var buf bytes.Buffer
-	d.w = &buf
-	// d.write("// GML: d.gr.Open\n")
-	d.write(d.gr.Open)
+	if err := d.prepare(); err != nil {
+		return nil, err
+	}
 
-	for _, h := range d.gr.Headers {
-		if h.Type != "default" {
-			continue
+	order := defaultOrder
+	if len(d.gr.Order) > 0 {
+		order = make([]string, 0, len(d.gr.Order))
+		for _, item := range d.gr.Order {
+			order = append(order, item.Name)
 		}
-		// d.write(fmt.Sprintf("// GML: d.gr.Headers: h=%#v\n", h))
-		d.write(h.Chardata)
-		d.write(d.gr.Endline)
 	}
 
-	for _, t := range d.gr.Tempvars {
-		if t.Type != "default" {
-			continue
+	var buf bytes.Buffer
+	d.w = &buf
+	for _, name := range order {
+		f, ok := sections[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown grammar order item: %v", name)
 		}
-		// d.write(fmt.Sprintf("// GML: d.gr.Tempvars: t=%#v\n", t))
-		d.write(t.Chardata)
+		f(d)
+	}
+
+	if d.gr.Ext != "go" {
+		return buf.Bytes(), nil
+	}
+	clean, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), err
+	}
+	return clean, nil
+}
+
+// prepare generates the expressions of the genes, keeping track of any
+// helper functions that are needed, and looks up the transformations
+// before any section is written.
+func (d *dump) prepare() error {
+	d.replacementType = "default"
+	d.categorical = d.genome.Pipeline != nil && d.genome.Pipeline.HasCategorical()
+	tuple := d.genome.LinkFunc == "tuple"
+	switch {
+	case d.categorical:
+		d.replacementType = "nominal"
+	case tuple:
+		// The "tuple" header, temporary variable and footer return
+		// the output of each gene as one element of the result.
+		d.replacementType = "tuple"
+	}
+
+	if t := d.tempvar(); t != nil {
 		d.subs["tempvarname"] = t.Varname
-		d.write(d.gr.Endline)
 	}
+	d.subs["GENE_COUNT"] = strconv.Itoa(len(d.genome.Genes))
 
-	// Generate the expression, keeping track of any helper functions that are needed.
-	helpers := make(grammars.HelperMap)
 	s, ok := d.gr.Functions.FuncMap[d.genome.LinkFunc]
 	if !ok {
-		return nil, fmt.Errorf("unable to find grammar linking function: %v", s.Symbol())
+		return fmt.Errorf("unable to find grammar linking function: %v", d.genome.LinkFunc)
 	}
 
 	glf, ok := s.(*grammars.Function)
 	if !ok {
-		return nil, fmt.Errorf("error casting link function: %v", s.Symbol())
+		return fmt.Errorf("error casting link function: %v", s.Symbol())
 	}
 
-	exps := []string{""}
+	d.helpers = make(grammars.HelperMap)
+	d.exps = nil
 	for i, gene := range d.genome.Genes {
-		// d.write(fmt.Sprintf("// GML: d.genome.Genes: e=%#v\n", e))
-		exp, err := gene.Expression(d.gr, helpers)
+		exp, err := gene.Expression(d.gr, d.helpers)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		if i > 0 {
-			// d.write(fmt.Sprintf("// GML: len(d.genome.Genes)=%v\n", len(d.genome.Genes)))
+		if i > 0 || tuple {
 			merge := strings.Replace(glf.Uniontype, "{tempvarname}", d.subs["tempvarname"], -1)
 			merge = strings.Replace(merge, "{member}", exp, -1)
 			merge = strings.Replace(merge, "{symbol}", glf.SymbolName, -1)
-			exps = append(exps, merge)
+			merge = strings.Replace(merge, "{index}", strconv.Itoa(i), -1)
+			merge = strings.Replace(merge, "{CHARX}", d.subs["CHARX"], -1)
+			d.exps = append(d.exps, merge)
 		} else {
-			// d.write(fmt.Sprintf("// GML: len(d.genome.Genes)=%v\n", len(d.genome.Genes)))
-			exps = append(exps, d.subs["tempvarname"]+" = "+exp)
+			d.exps = append(d.exps, d.subs["tempvarname"]+" = "+exp)
+		}
+	}
+
+	var err error
+	if d.inputT, err = d.transformation(d.gr.Transformations, d.pipelineMethod(true)); err != nil {
+		return err
+	}
+	if d.reverseT, err = d.transformation(d.gr.ReverseTransformations, d.pipelineMethod(false)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// replacement returns the chardata of the replacement matching the type of
+// the model, falling back to the default replacement.
+func (d *dump) replacement(rs []grammars.Replacement) (string, bool) {
+	for _, t := range []string{d.replacementType, "default"} {
+		for _, r := range rs {
+			if r.Type == t {
+				return r.Chardata, true
+			}
+		}
+	}
+	return "", false
+}
+
+// tempvar returns the temporary variable matching the type of the model,
+// falling back to the default temporary variable.
+func (d *dump) tempvar() *grammars.Tempvar {
+	for _, t := range []string{d.replacementType, "default"} {
+		for i, v := range d.gr.Tempvars {
+			if v.Type == t {
+				return &d.gr.Tempvars[i]
+			}
+		}
+	}
+	return nil
+}
+
+// sortedHelpers returns the helpers sorted by symbol, skipping any helper
+// that is identical to an earlier one (such as the safeDiv helper shared
+// by several integer functions).
+func (d *dump) sortedHelpers() []string {
+	keys := make([]string, 0, len(d.helpers))
+	for k := range d.helpers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	seen := make(map[string]bool, len(keys))
+	var result []string
+	for _, k := range keys {
+		if h := d.helpers[k]; !seen[h] {
+			seen[h] = true
+			result = append(result, k)
 		}
 	}
+	return result
+}
+
+func (d *dump) writeModelComments() {
+	for _, line := range d.comments {
+		d.write(strings.TrimRight(fmt.Sprintf("%v %v", d.gr.Commentmark, line), " "))
+		d.write("{CRLF}")
+	}
+}
+
+// writeOpen writes the opening statement without leading blank lines
+// so that it directly follows the model comments.
+func (d *dump) writeOpen() {
+	d.write(strings.TrimLeft(d.gr.Open, " \t\r\n"))
+}
+
+func (d *dump) writeHelpersDeclaration() {
+	if len(d.helpers) > 0 {
+		d.write(d.gr.Helpers.Declaration)
+	}
+}
+
+// writePrototypes writes the forward declarations of the helper and
+// basic functions for languages that need them.
+func (d *dump) writePrototypes() {
+	var prototypes []string
+	seen := map[string]bool{}
+	for _, h := range d.gr.Helpers.Helpers {
+		if _, ok := d.helpers[h.Replaces]; ok && h.Prototype != "" && !seen[h.Prototype] {
+			seen[h.Prototype] = true
+			prototypes = append(prototypes, h.Prototype)
+		}
+	}
+	if f := d.linkingHelper(); f != nil && f.Prototype != "" {
+		prototypes = append(prototypes, f.Prototype)
+	}
+	for _, f := range d.basicFunctions() {
+		if f.Prototype != "" {
+			prototypes = append(prototypes, f.Prototype)
+		}
+	}
+	for _, p := range prototypes {
+		d.write(p)
+		d.write("{CRLF}")
+	}
+	if len(prototypes) > 0 {
+		d.write("{CRLF}")
+	}
+}
+
+func (d *dump) writeSubheader() {
+	if s, ok := d.replacement(d.gr.Subheaders); ok {
+		d.write(strings.TrimSpace(s))
+	}
+}
 
-	exps = append(exps, "") // blank line
-	fmt.Fprintln(d.w, strings.Join(exps, "\n"))
+func (d *dump) writeHeader() {
+	if s, ok := d.replacement(d.gr.Headers); ok {
+		d.write(s)
+		d.write("{CRLF}")
+	}
 
-	for _, f := range d.gr.Footers {
-		if f.Type != "default" {
+	indent := strings.Repeat("{TAB}", d.gr.Indent)
+	for i, name := range d.inputNames {
+		d.write(fmt.Sprintf("%v%v d[%v]: %v", indent, d.gr.Commentmark, i, name))
+		d.write("{CRLF}")
+	}
+}
+
+// writeRandomConstants writes nothing because the random constants
+// of the genes are rendered inline in the expressions.
+func (d *dump) writeRandomConstants() {}
+
+func (d *dump) writeConstants() {
+	for _, c := range d.gr.Constants {
+		if c.Type != "default" {
 			continue
 		}
-		// d.write(fmt.Sprintf("// GML: d.gr.Footers=%#v\n", f))
-		d.write(f.Chardata)
+		for i, label := range d.labels {
+			s := strings.Replace(c.Chardata, "{labelname}", label, -1)
+			d.write(strings.Replace(s, "{labelindex}", strconv.Itoa(i+c.Labelindex), -1))
+		}
+	}
+}
+
+func (d *dump) writeCallTransform() {
+	if d.categorical {
+		d.subs["VARIABLE_COUNT"] = strconv.Itoa(len(d.genome.Pipeline.Categorical))
+		d.write(d.gr.Categories.Functioncall.Call)
+	}
+}
+
+func (d *dump) writeCall(t *grammars.Transformation) {
+	if t != nil {
+		d.write(t.Call)
+	}
+}
+
+func (d *dump) writeHelpersAssignment() {
+	if len(d.helpers) > 0 {
+		d.write(d.gr.Helpers.Assignment)
+	}
+}
+
+func (d *dump) writeTemporaryVariable() {
+	if t := d.tempvar(); t != nil {
+		d.write(t.Chardata)
+		d.write("{CRLF}")
+	}
+}
+
+// writeBody writes one statement per gene, indented by the grammar's
+// indent and terminated by the grammar's endline (such as ";{CRLF}").
+func (d *dump) writeBody() {
+	fmt.Fprintln(d.w) // blank line
+	indent := strings.Repeat("\t", d.gr.Indent)
+	for _, exp := range d.exps {
+		fmt.Fprint(d.w, indent+exp)
 		d.write(d.gr.Endline)
 	}
+}
+
+func (d *dump) writeFooter() {
+	fmt.Fprintln(d.w) // blank line
+	if s, ok := d.replacement(d.gr.Footers); ok {
+		d.write(s)
+		d.write("{CRLF}")
+	}
+}
+
+func (d *dump) writeHelpers() {
+	for _, k := range d.sortedHelpers() {
+		d.write("{CRLF}")
+		d.write(d.helpers[k])
+	}
+}
+
+// writeBasicFunctions writes the functions that the grammar's
+// function definitions rely on.
+func (d *dump) writeBasicFunctions() {
+	for _, f := range d.basicFunctions() {
+		d.write("{CRLF}")
+		d.write(f.Chardata)
+	}
+}
+
+// basicFunctions returns the basic functions of the grammar that are
+// needed by the code. A basic function that names itself in its replaces
+// attribute (such as the guarded gepDiv of the Python grammar) is only
+// needed when the body, the helpers, or another needed basic function
+// calls it.
+func (d *dump) basicFunctions() []grammars.Helper {
+	code := strings.Join(d.exps, "\n")
+	for _, h := range d.helpers {
+		code += h
+	}
+	if f := d.linkingHelper(); f != nil {
+		code += f.Chardata
+	}
+
+	fs := d.gr.BasicFunctions.BasicFunctions
+	needed := make([]bool, len(fs))
+	for changed := true; changed; {
+		changed = false
+		for i, f := range fs {
+			if !needed[i] && (f.Replaces == "" || strings.Contains(code, f.Replaces+"(")) {
+				needed[i], changed = true, true
+				code += f.Chardata
+			}
+		}
+	}
+
+	var result []grammars.Helper
+	for i, f := range fs {
+		if needed[i] {
+			result = append(result, f)
+		}
+	}
+	return result
+}
 
-	if len(helpers) > 0 { // Write out the helpers
-		keys := make([]string, 0, len(helpers))
-		for k := range helpers {
-			keys = append(keys, k)
+// writeLinkingHelpers writes the linking function of the genome
+// unless it has already been written as a helper.
+func (d *dump) writeLinkingHelpers() {
+	if f := d.linkingHelper(); f != nil {
+		d.write("{CRLF}")
+		d.write(f.Chardata)
+	}
+}
+
+// linkingHelper returns the grammar's linking function for the genome,
+// or nil if the genome has a single gene or the linking function is
+// already one of the helpers.
+func (d *dump) linkingHelper() *grammars.Helper {
+	if len(d.genome.Genes) < 2 {
+		return nil
+	}
+	for i, f := range d.gr.LinkingFunctions.LinkingFunctions {
+		if f.Replaces != d.genome.LinkFunc {
+			continue
 		}
-		sort.Strings(keys)
-		for _, k := range keys {
-			d.write(d.gr.Endline)
-			d.write(helpers[k])
+		for _, h := range d.helpers {
+			if h == f.Chardata {
+				return nil
+			}
 		}
+		return &d.gr.LinkingFunctions.LinkingFunctions[i]
 	}
+	return nil
+}
 
-	clean, err := format.Source(buf.Bytes())
-	if err != nil {
-		return buf.Bytes(), err
+// pipelineMethod returns the input (or target) method of the genome's pipeline.
+func (d *dump) pipelineMethod(input bool) transform.Method {
+	p := d.genome.Pipeline
+	switch {
+	case p == nil:
+		return transform.None
+	case input:
+		return p.InputMethod
+	default:
+		return p.TargetMethod
 	}
-	return clean, nil
+}
+
+// transformation finds the grammar transformation for the method.
+func (d *dump) transformation(ts []grammars.Transformation, method transform.Method) (*grammars.Transformation, error) {
+	if method == transform.None {
+		return nil, nil
+	}
+	for i, t := range ts {
+		if t.Name == string(method) {
+			return &ts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find grammar transformation: %v", method)
+}
+
+// writeCategoricalTransformation writes the helper function that encodes
+// the raw string inputs as numbers.
+func (d *dump) writeCategoricalTransformation() {
+	if !d.categorical {
+		return
+	}
+	c := d.gr.Categories
+	var body []string
+	for i, cats := range d.genome.Pipeline.Categorical {
+		var item string
+		if cats == nil {
+			item = c.Equality.Format
+		} else {
+			item = c.Switch.Top
+			for j, name := range cats.Names {
+				q := strconv.Quote(name)
+				s := strings.Replace(c.Case.Format, "{nominal}", q[1:len(q)-1], -1)
+				item += strings.Replace(s, "{numerical}", floatLiteral(cats.Values[j]), -1)
+			}
+			item += c.Switch.Categoricaldefault + c.Switch.Bottom
+		}
+		item = strings.Replace(item, "{index}", strconv.Itoa(i), -1)
+		if c.Switch.Special == "ADD_1_TAB_TO_METHOD_BODY" {
+			item = "{TAB}" + strings.Replace(item, "{CRLF}", "{CRLF}{TAB}", -1)
+		}
+		body = append(body, item)
+	}
+	d.write(c.TransformFunction.Header)
+	d.write(strings.Join(body, ""))
+	d.write(c.TransformFunction.Footer)
+	d.write("{CRLF}")
+}
+
+// writeInputTransformation writes the helper function that transforms the inputs.
+func (d *dump) writeInputTransformation() {
+	t := d.inputT
+	if t == nil {
+		return
+	}
+	p := d.genome.Pipeline
+	var body []string
+	for i, s := range p.Inputs {
+		item := strings.Replace(t.Itemformat, "{index}", strconv.Itoa(i), -1)
+		body = append(body, replaceStats(item, "", s))
+	}
+	d.writeTransformation(t, strings.Join(body, ""))
+}
+
+// writeReverseTransformation writes the helper function that transforms
+// the output of the model back to raw target units.
+func (d *dump) writeReverseTransformation() {
+	t := d.reverseT
+	if t == nil {
+		return
+	}
+	p := d.genome.Pipeline
+	body := replaceStats(t.Itemformat, "MODEL_", p.ModelStats())
+	body = replaceStats(body, "TARGET_", p.Target)
+	d.writeTransformation(t, body)
+}
+
+func (d *dump) writeTransformation(t *grammars.Transformation, body string) {
+	p := d.genome.Pipeline
+	s := strings.Replace(t.Chardata, "{BODY}", body, -1)
+	s = strings.Replace(s, "{NEW_MIN}", floatLiteral(p.NewMin), -1)
+	s = strings.Replace(s, "{NEW_MAX}", floatLiteral(p.NewMax), -1)
+	d.write(s)
+}
+
+// replaceStats replaces the {<prefix>AVERAGE}, {<prefix>STDEV}, {<prefix>MIN},
+// and {<prefix>MAX} placeholders with the statistics.
+func replaceStats(s, prefix string, stats transform.Stats) string {
+	s = strings.Replace(s, "{"+prefix+"AVERAGE}", floatLiteral(stats.Average), -1)
+	s = strings.Replace(s, "{"+prefix+"STDEV}", floatLiteral(stats.Stdev), -1)
+	s = strings.Replace(s, "{"+prefix+"MIN}", floatLiteral(stats.Min), -1)
+	return strings.Replace(s, "{"+prefix+"MAX}", floatLiteral(stats.Max), -1)
+}
+
+// floatLiteral formats v as a floating-point constant with full precision.
+func floatLiteral(v float64) string {
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEnN") { // leave NaN and Inf unchanged
+		s += ".0"
+	}
+	return s
 }
 
 func (d *dump) write(s string) {
@@ -6,11 +6,19 @@ package genome
 
 import (
 	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/gmlewis/gep/v2/functions"
 	"github.com/gmlewis/gep/v2/gene"
 	"github.com/gmlewis/gep/v2/grammars"
+	"github.com/gmlewis/gep/v2/transform"
 )
 
 func TestWriteNand(t *testing.T) {
@@ -114,3 +122,859 @@ func gepNor(x, y bool) bool {
 		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
 	}
 }
+
+func TestWriteWithInputNames(t *testing.T) {
+	want := `package gepModel
+
+import (
+	"math"
+)
+
+func gepModel(d []float64) float64 {
+	// d[0]: width
+	// d[1]: height
+	var y float64
+
+	y = (d[0] * d[1])
+
+	return y
+}
+`
+
+	g1 := gene.New("*.d0.d1.d0.d0", functions.Float64)
+	gn := New([]*gene.Gene{g1}, "+")
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar, WithInputNames([]string{"width", "height"}))
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+}
+
+func TestWriteWithPipeline(t *testing.T) {
+	want := `package gepModel
+
+import (
+	"math"
+)
+
+func gepModel(d []float64) float64 {
+	d = append([]float64(nil), d...) // do not modify the caller's inputs
+	Standardize(d)
+	var y float64
+
+	y = (d[0] * d[1])
+	y = Reverse_Standardization(y)
+
+	return y
+}
+
+func Standardize(input []float64) {
+	const AVERAGE_0 = 2.0
+	const STDEV_0 = 1.0
+	input[0] = (input[0] - AVERAGE_0) / STDEV_0
+
+	const AVERAGE_1 = 20.0
+	const STDEV_1 = 10.0
+	input[1] = (input[1] - AVERAGE_1) / STDEV_1
+
+}
+
+func Reverse_Standardization(modelOutput float64) float64 {
+	// Model standardization
+	const MODEL_AVERAGE = 0.0
+	const MODEL_STDEV = 1.0
+	modelOutput = (modelOutput - MODEL_AVERAGE) / MODEL_STDEV
+
+	// Reverse standardization
+	const TARGET_AVERAGE = 3.5
+	const TARGET_STDEV = 0.5
+
+	return modelOutput*TARGET_STDEV + TARGET_AVERAGE
+}
+`
+
+	in := [][]float64{{1, 10}, {3, 30}}
+	target := []float64{3, 4}
+	p, err := transform.Fit(transform.Standardization, transform.Standardization, in, target)
+	if err != nil {
+		t.Fatalf("transform.Fit: %v", err)
+	}
+
+	g1 := gene.New("*.d0.d1.d0.d0", functions.Float64)
+	gn := New([]*gene.Gene{g1}, "+")
+	gn.Pipeline = p
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar)
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+
+	// The raw inputs {3, 30} standardize to {1, 1}, so the model output of 1
+	// reverses to 1*0.5 + 3.5.
+	if got, want := gn.EvalMath([]float64{3, 30}), 4.0; got != want {
+		t.Errorf("EvalMath = %v, want %v", got, want)
+	}
+}
+
+func TestWriteWithPipelineKeepsInputs(t *testing.T) {
+	in := [][]float64{{1, 10}, {3, 30}, {-2, 7}}
+	target := []float64{3, 4, 1}
+	d := []float64{-3.95, -1.59}
+
+	for _, method := range []transform.Method{transform.Standardization, transform.Normalization, transform.MinMaxNormalization} {
+		t.Run(string(method), func(t *testing.T) {
+			p, err := transform.Fit(method, method, in, target)
+			if err != nil {
+				t.Fatalf("transform.Fit: %v", err)
+			}
+			g1 := gene.New("+.*.Max2.d0.d1.d0.d1", functions.Float64)
+			gn := New([]*gene.Gene{g1}, "+")
+			gn.Pipeline = p
+			grammar, err := grammars.LoadGoMathGrammar()
+			if err != nil {
+				t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+			}
+
+			code := new(bytes.Buffer)
+			gn.Write(code, grammar)
+			want := gn.EvalMath(append([]float64(nil), d...))
+
+			// Calling the generated model twice on the same slice must give the
+			// same answer as EvalMath and must leave the slice unchanged.
+			test := fmt.Sprintf(`package gepModel
+
+import "testing"
+
+func TestGepModel(t *testing.T) {
+	d := []float64{%v, %v}
+	for i := 0; i < 2; i++ {
+		if got, want := gepModel(d), %v; got != want {
+			t.Errorf("call #%%v: gepModel = %%v, want %%v", i, got, want)
+		}
+	}
+	if d[0] != %v || d[1] != %v {
+		t.Errorf("gepModel modified its inputs: %%v", d)
+	}
+}
+`, goFloat(d[0]), goFloat(d[1]), goFloat(want), goFloat(d[0]), goFloat(d[1]))
+			goTest(t, map[string]string{"model.go": code.String(), "model_test.go": test})
+		})
+	}
+}
+
+func TestWriteWithCategoricalInputs(t *testing.T) {
+	want := `package gepModel
+
+import (
+	"math"
+	"strconv"
+)
+
+func gepModel(d_string []string) float64 {
+	d := make([]float64, 2)
+	TransformCategoricalInputs(d_string, d)
+	var y float64
+
+	y = (d[0] * d[1])
+
+	return y
+}
+
+func atof(a string) float64 {
+	if o, err := strconv.ParseFloat(a, 64); err != nil {
+		return 0.0
+	} else {
+		return o
+	}
+}
+
+func TransformCategoricalInputs(input []string, output []float64) {
+
+	switch input[0] {
+	case "red":
+		output[0] = 0.0
+	case "say \"green\"":
+		output[0] = 2.5
+	default:
+		output[0] = 0.0
+	}
+
+	output[1] = atof(input[1])
+
+}
+`
+
+	g1 := gene.New("*.d0.d1.d0.d0", functions.Float64)
+	gn := New([]*gene.Gene{g1}, "+")
+	gn.Pipeline = &transform.Pipeline{
+		Categorical: []*transform.Categories{
+			{Names: []string{"red", `say "green"`}, Values: []float64{0, 2.5}},
+			nil,
+		},
+	}
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar)
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+}
+
+func TestWriteLinkingHelper(t *testing.T) {
+	want := `package gepModel
+
+func gepModel(d []bool) bool {
+	var y bool
+
+	y = (d[0] && d[1])
+	y = gepNand(y, (d[1] || d[0]))
+
+	return y
+}
+
+func gepNand(x, y bool) bool {
+	return (!(x && y))
+}
+`
+
+	g1 := gene.New("And.d0.d1", functions.Bool)
+	g2 := gene.New("Or.d1.d0", functions.Bool)
+	gn := New([]*gene.Gene{g1, g2}, "Nand")
+	grammar, err := grammars.LoadGoBooleanAllGatesGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoBooleanAllGatesGrammar(): %v", err)
+	}
+
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar)
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+}
+
+// The uniontype of Nxor escapes its x as {CHARX}, which must be expanded
+// back to an x rather than left in the generated code.
+func TestWriteLinkingCHARX(t *testing.T) {
+	want := `package gepModel
+
+func gepModel(d []bool) bool {
+	var y bool
+
+	y = (d[0] && d[1])
+	y = gepNxor(y, (d[1] || d[0]))
+
+	return y
+}
+
+func gepNxor(x, y bool) bool {
+	return ((!(x || y)) || (x && y))
+}
+`
+
+	g1 := gene.New("And.d0.d1", functions.Bool)
+	g2 := gene.New("Or.d1.d0", functions.Bool)
+	gn := New([]*gene.Gene{g1, g2}, "Nxor")
+	grammar, err := grammars.LoadGoBooleanAllGatesGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoBooleanAllGatesGrammar(): %v", err)
+	}
+
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar)
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+}
+
+func TestWriteIntTuple(t *testing.T) {
+	want := `package gepModel
+
+func gepModel(d []int) []int {
+	y := make([]int, 3)
+
+	y[0] = gepMax3(gepMax2(d[0], d[1]), 3, d[1])
+	y[1] = safeDiv(safeDiv(d[0], d[1]), d[1])
+	y[2] = (safeDiv(d[1], ((3 + 2) / 2)))
+
+	return y
+}
+
+func safeDiv(a, b int) int {
+	if b == 0 {
+		return int(^uint(0) >> 1)
+	}
+	return a / b
+}
+
+func gepMax2(x, y int) int {
+	if x < y {
+		return y
+	}
+	return x
+}
+
+func gepMax3(x, y, z int) int {
+	varTemp := x
+	if varTemp < y {
+		varTemp = y
+	}
+	if varTemp < z {
+		varTemp = z
+	}
+	return varTemp
+}
+`
+
+	g1 := gene.New("Max3.Max2.c0.d1.d0.d1.d0", functions.Int)
+	g1.Constants = []float64{3.7}
+	g2 := gene.New("Div3.d0.d1.d1.d0", functions.Int)
+	g3 := gene.New("/.d1.Avg2.c0.c1.d0", functions.Int)
+	g3.Constants = []float64{3, 2}
+	gn := New([]*gene.Gene{g1, g2, g3}, "tuple")
+	grammar, err := grammars.LoadGoIntGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoIntGrammar(): %v", err)
+	}
+
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar)
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+
+	// The generated code evaluates to [5 1 1] and [9 0 4].
+	for in, want := range map[[2]int][3]int{{5, 2}: {5, 1, 1}, {0, 9}: {9, 0, 4}} {
+		got := gn.EvalIntTuple(in[:])
+		if len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+			t.Errorf("EvalIntTuple(%v) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestWriteVectorInt(t *testing.T) {
+	want := `package gepModel
+
+func gepModel(d [][]int) []int {
+	var y []int
+
+	y = gepVAdd(d[0], gepVSub(gepVConst(d, 2), d[1]))
+	y = gepVMul(y, gepVDiv(d[1], d[0]))
+
+	return y
+}
+
+func gepVAdd(x, y []int) []int {
+	result := make([]int, len(x))
+	for i := range x {
+		result[i] = x[i] + y[i]
+	}
+	return result
+}
+
+func gepVSub(x, y []int) []int {
+	result := make([]int, len(x))
+	for i := range x {
+		result[i] = x[i] - y[i]
+	}
+	return result
+}
+
+func gepVDiv(x, y []int) []int {
+	result := make([]int, len(x))
+	for i := range x {
+		if x[i] != 0 {
+			result[i] = x[i] / y[i]
+		}
+	}
+	return result
+}
+
+func gepVConst(d [][]int, c int) []int {
+	if len(d) == 0 {
+		return nil
+	}
+	result := make([]int, len(d[0]))
+	for i := range result {
+		result[i] = c
+	}
+	return result
+}
+
+func gepVMul(x, y []int) []int {
+	result := make([]int, len(x))
+	for i := range x {
+		result[i] = x[i] * y[i]
+	}
+	return result
+}
+`
+
+	g1 := gene.New("+.d0.-.c0.d1", functions.VectorInts)
+	g1.Constants = []float64{2}
+	g2 := gene.New("/.d1.d0", functions.VectorInts)
+	gn := New([]*gene.Gene{g1, g2}, "*")
+	grammar, err := grammars.LoadGoVectorIntGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoVectorIntGrammar(): %v", err)
+	}
+
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar)
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+}
+
+func TestWriteCommentsAndLabels(t *testing.T) {
+	want := `// Model for the iris dataset.
+//
+// Karva: +.d0.d1
+package gepModel
+
+import (
+	"math"
+)
+
+func gepModel(d []float64) float64 {
+	const setosa = 0
+	const versicolor = 1
+	var y float64
+
+	y = (d[0] + d[1])
+
+	return y
+}
+`
+
+	g1 := gene.New("+.d0.d1", functions.Float64)
+	gn := New([]*gene.Gene{g1}, "+")
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar, WithComments("Model for the iris dataset.", "", "Karva: +.d0.d1"), WithLabels([]string{"setosa", "versicolor"}))
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+}
+
+func TestWriteOrder(t *testing.T) {
+	g1 := gene.New("+.d0.d1", functions.Float64)
+	gn := New([]*gene.Gene{g1}, "+")
+	grammar, err := grammars.LoadGoMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoMathGrammar(): %v", err)
+	}
+
+	// The sections are rendered in the order listed by the grammar.
+	grammar.Order = []grammars.OrderItem{{Name: "Open"}, {Name: "Header"}, {Name: "Footer"}, {Name: "Close"}}
+	want := `package gepModel
+
+import (
+	"math"
+)
+
+func gepModel(d []float64) float64 {
+
+	return y
+}
+`
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar)
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+
+	grammar.Order = append(grammar.Order, grammars.OrderItem{Name: "Bogus"})
+	d := &dump{gr: grammar, genome: gn, subs: map[string]string{}}
+	if _, err := d.generateCode(); err == nil {
+		t.Error("generateCode: expected error for unknown order item")
+	}
+}
+
+func TestWriteBasicFunctions(t *testing.T) {
+	want := `package gepModel
+
+func gepModel(d []bool) bool {
+	var y bool
+
+	y = gepNand(d[0], d[1])
+
+	return y
+}
+
+func gepNand(x, y bool) bool {
+	return (!(x && y))
+}
+`
+
+	g1 := gene.New("Nand.d0.d1", functions.Bool)
+	gn := New([]*gene.Gene{g1}, "Nand")
+	grammar, err := grammars.LoadGoBooleanNandGatesGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadGoBooleanNandGatesGrammar(): %v", err)
+	}
+
+	b := new(bytes.Buffer)
+	gn.Write(b, grammar)
+	if b.String() != want {
+		t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), want)
+	}
+}
+
+func TestWriteLanguages(t *testing.T) {
+	mathGenome := func() *Genome {
+		g1 := gene.New("Mod.d0.Logi.d1.d0.d1", functions.Float64)
+		g2 := gene.New("Min2.c0.Neg.d1.d0.d1", functions.Float64)
+		g2.Constants = []float64{3}
+		return New([]*gene.Gene{g1, g2}, "+")
+	}
+	boolGenome := func() *Genome {
+		g1 := gene.New("And.Not.d1.d0.d1", functions.Bool)
+		g2 := gene.New("Xor.d0.d1.d0", functions.Bool)
+		return New([]*gene.Gene{g1, g2}, "Nand")
+	}
+
+	tests := []struct {
+		name   string
+		load   func() (*grammars.Grammar, error)
+		genome *Genome
+		want   string
+	}{
+		{
+			name:   "PythonMath",
+			load:   grammars.LoadPythonMathGrammar,
+			genome: mathGenome(),
+			want: `# Karva model
+import math
+
+def gepModel(d):
+	y = 0.0
+
+	y = gepMod(d[0],gepLogi(d[1]))
+	y += min(3.0,(-(d[1])))
+
+	return y
+
+def gepLogi(x):
+	if abs(x) > 709.0:
+		return gepDiv(1.0, (1.0 + gepExp(gepDiv(abs(x), x)*709.0)))
+	return gepDiv(1.0, (1.0 + gepExp(-x)))
+
+def gepMod(x, y):
+	# The built-in function is incorrect for cases such as -1.0 and 0.2.
+	return (gepDiv(x, y) - gepTrunc(gepDiv(x, y))) * y
+
+def gepDiv(x, y):
+	try:
+		return x / y
+	except ZeroDivisionError:
+		if x == 0 or math.isnan(x):
+			return math.nan
+		return math.copysign(math.inf, x) * math.copysign(1.0, y)
+
+def gepExp(x):
+	try:
+		return math.exp(x)
+	except OverflowError:
+		return math.inf
+
+def gepTrunc(x):
+	if math.isfinite(x):
+		return float(math.trunc(x))
+	return x
+`,
+		},
+		{
+			name:   "CMath",
+			load:   grammars.LoadCMathGrammar,
+			genome: mathGenome(),
+			want: `// Karva model
+#include <math.h>
+
+double gepMod(double x, double y);
+double gepLogi(double x);
+
+double gepModel(double d[]) {
+	double y = 0.0;
+
+	y = gepMod(d[0],gepLogi(d[1]));
+	y += fmin(3.0,(-(d[1])));
+
+	return y;
+}
+
+double gepLogi(double x) {
+	if (fabs(x) > 709.0) {
+		return 1.0 / (1.0 + exp(fabs(x) / x * 709.0));
+	}
+	return 1.0 / (1.0 + exp(-x));
+}
+
+double gepMod(double x, double y) {
+	// The built-in function is incorrect for cases such as -1.0 and 0.2.
+	return ((x / y) - trunc(x/y)) * y;
+}
+`,
+		},
+		{
+			name:   "JavaScriptMath",
+			load:   grammars.LoadJavaScriptMathGrammar,
+			genome: mathGenome(),
+			want: `// Karva model
+"use strict";
+
+function gepModel(d) {
+	var y = 0.0;
+
+	y = gepMod(d[0],gepLogi(d[1]));
+	y += Math.min(3.0,(-(d[1])));
+
+	return y;
+}
+
+function gepLogi(x) {
+	if (Math.abs(x) > 709.0) {
+		return 1.0 / (1.0 + Math.exp(Math.abs(x) / x * 709.0));
+	}
+	return 1.0 / (1.0 + Math.exp(-x));
+}
+
+function gepMod(x, y) {
+	// The built-in function is incorrect for cases such as -1.0 and 0.2.
+	return ((x / y) - Math.trunc(x/y)) * y;
+}
+`,
+		},
+		{
+			name:   "JavaMath",
+			load:   grammars.LoadJavaMathGrammar,
+			genome: mathGenome(),
+			want: `// Karva model
+public final class GepModel {
+	public static double gepModel(double[] d) {
+		double y = 0.0;
+
+		y = gepMod(d[0],gepLogi(d[1]));
+		y += Math.min(3.0,(-(d[1])));
+
+		return y;
+	}
+
+	private static double gepLogi(double x) {
+		if (Math.abs(x) > 709.0) {
+			return 1.0 / (1.0 + Math.exp(Math.abs(x) / x * 709.0));
+		}
+		return 1.0 / (1.0 + Math.exp(-x));
+	}
+
+	private static double gepMod(double x, double y) {
+		// The built-in function is incorrect for cases such as -1.0 and 0.2.
+		return ((x / y) - (double)(long)(x/y)) * y;
+	}
+}
+`,
+		},
+		{
+			name:   "PythonBoolean",
+			load:   grammars.LoadPythonBooleanAllGatesGrammar,
+			genome: boolGenome(),
+			want: `# Karva model
+def gepModel(d):
+	y = False
+
+	y = ((not (d[0])) and d[1])
+	y = gepNand(y,(d[0] != d[1]))
+
+	return y
+
+def gepNand(x, y):
+	return (not (x and y))
+`,
+		},
+		{
+			name:   "CBoolean",
+			load:   grammars.LoadCBooleanAllGatesGrammar,
+			genome: boolGenome(),
+			want: `// Karva model
+#include <stdbool.h>
+
+bool gepNand(bool x, bool y);
+
+bool gepModel(bool d[]) {
+	bool y = false;
+
+	y = ((!(d[0])) && d[1]);
+	y = gepNand(y,(d[0] != d[1]));
+
+	return y;
+}
+
+bool gepNand(bool x, bool y) {
+	return (!(x && y));
+}
+`,
+		},
+		{
+			name:   "JavaScriptBoolean",
+			load:   grammars.LoadJavaScriptBooleanAllGatesGrammar,
+			genome: boolGenome(),
+			want: `// Karva model
+"use strict";
+
+function gepModel(d) {
+	var y = false;
+
+	y = ((!(d[0])) && d[1]);
+	y = gepNand(y,(d[0] != d[1]));
+
+	return y;
+}
+
+function gepNand(x, y) {
+	return (!(x && y));
+}
+`,
+		},
+		{
+			name:   "JavaBoolean",
+			load:   grammars.LoadJavaBooleanAllGatesGrammar,
+			genome: boolGenome(),
+			want: `// Karva model
+public final class GepModel {
+	public static boolean gepModel(boolean[] d) {
+		boolean y = false;
+
+		y = ((!(d[0])) && d[1]);
+		y = gepNand(y,(d[0] != d[1]));
+
+		return y;
+	}
+
+	private static boolean gepNand(boolean x, boolean y) {
+		return (!(x && y));
+	}
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grammar, err := tt.load()
+			if err != nil {
+				t.Fatalf("unable to load grammar: %v", err)
+			}
+
+			b := new(bytes.Buffer)
+			tt.genome.Write(b, grammar, WithComments("Karva model"))
+			if b.String() != tt.want {
+				t.Errorf("gen.Write() got:\n%v\nwant:\n%v", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWritePythonMathEdgeCases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping python run of the generated code in short mode")
+	}
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not found")
+	}
+	grammar, err := grammars.LoadPythonMathGrammar()
+	if err != nil {
+		t.Fatalf("unable to LoadPythonMathGrammar(): %v", err)
+	}
+
+	inf, nan := math.Inf(1), math.NaN()
+	cases := [][]float64{{1, 0}, {-1, 0}, {0, 0}, {0, -1}, {-1, 0.5}, {-4, 3}, {1000, 2}, {-1000, 3}, {inf, 2}, {nan, 1}, {2, 3}}
+	// The Python code must return the same ±Inf and NaN as Go instead of
+	// raising ZeroDivisionError, ValueError or OverflowError.
+	for _, karva := range []string{"/.d0.d1", "Ln.d0", "Log2.d0.d1", "Sqrt.d0", "Pow.d0.d1", "Exp.d0", "Mod.d0.d1", "Floor.d0", "Sin.d0", "Acos.d0", "Atanh.d1"} {
+		t.Run(karva, func(t *testing.T) {
+			gn := New([]*gene.Gene{gene.New(karva, functions.Float64)}, "+")
+			var code strings.Builder
+			gn.Write(&code, grammar)
+			code.WriteString("\nfor d in [")
+			for _, in := range cases {
+				fmt.Fprintf(&code, "[float('%v'), float('%v')], ", in[0], in[1])
+			}
+			code.WriteString("]:\n\tprint(repr(float(gepModel(d))))\n")
+
+			path := filepath.Join(t.TempDir(), "model.py")
+			if err := os.WriteFile(path, []byte(code.String()), 0644); err != nil {
+				t.Fatal(err)
+			}
+			out, err := exec.Command(python, path).CombinedOutput()
+			if err != nil {
+				t.Fatalf("python3: %v\n%s\n%s", err, out, code.String())
+			}
+			lines := strings.Fields(string(out))
+			if len(lines) != len(cases) {
+				t.Fatalf("python3 printed %v results, want %v:\n%s", len(lines), len(cases), out)
+			}
+			for i, in := range cases {
+				got, err := strconv.ParseFloat(lines[i], 64)
+				if err != nil {
+					t.Fatalf("case %v: %v", in, err)
+				}
+				if karva == "Mod.d0.d1" && math.IsInf(in[0], 0) {
+					// Go's conversion of an infinite float64 to int is implementation-specific.
+					continue
+				}
+				want := gn.EvalMath(in)
+				if got == want || math.IsNaN(got) && math.IsNaN(want) {
+					continue
+				}
+				// CPython uses the C library, whose last bit may differ from Go's math package.
+				if math.IsInf(want, 0) || math.Abs(got-want) > 1e-15*math.Abs(want) {
+					t.Errorf("python gepModel(%v) = %v, want %v", in, got, want)
+				}
+			}
+		})
+	}
+}
+
+// goTest writes the files to a temporary module and runs "go test" in it,
+// checking that the generated code compiles and behaves as expected.
+func goTest(t *testing.T, files map[string]string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go test of the generated code in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	dir := t.TempDir()
+	files["go.mod"] = "module gepmodel\n\ngo 1.24\n"
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command(goTool, "test", "./...")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test of the generated code: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,1194 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+// gen-grammars generates the Python, C, JavaScript and Java grammars by
+// translating the functions and helpers of the Go grammars, so that all
+// target languages share the same GeneXproTools semantics.
+//
+// It also generates the Go grammars of the integer and vector of integers
+// function sets from the functions themselves, so that the generated code
+// evaluates exactly like the models.
+//
+// It is meant to be used by the authors to update the bundled grammars.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/gmlewis/gep/v2/grammars"
+)
+
+var (
+	verbose = flag.Bool("v", false, "Print verbose log messages")
+
+	funcMap = map[string]any{"esc": esc.Replace}
+
+	sourceTmpl = template.Must(template.New("source").Funcs(funcMap).Parse(source))
+
+	esc = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// language describes how the Go grammars are translated to a target language.
+type language struct {
+	Name   string // the grammar name, such as "Python"
+	Prefix string // the prefix of the generated file names, such as "python"
+	Ext    string // the extension of the generated source code
+	// Comment is the comment mark of the language.
+	Comment string
+	// Endline terminates every statement of the model body.
+	Endline string
+	// Indent is the indentation of the model body.
+	Indent int
+	// Types maps the Go types of the helpers to the language.
+	Types    map[string]string
+	Keywords []string
+	// Kinds holds the grammar sections that depend on the function set.
+	Kinds map[string]*kind
+}
+
+// kind holds the sections of a grammar that depend on the function set.
+type kind struct {
+	Open, Close, Header, Footer string
+	Typename, Tempvar, Constant string
+	// Tuple (optional) holds the header, tempvar and footer used with
+	// the "tuple" linking function.
+	Tuple *kind
+	// Vector (optional) renders a random constant as a vector.
+	Vector string
+}
+
+var languages = []*language{
+	{
+		Name:     "Python",
+		Prefix:   "python",
+		Ext:      "py",
+		Comment:  "#",
+		Endline:  "{CRLF}",
+		Indent:   1,
+		Types:    map[string]string{"float64": "float", "bool": "bool"},
+		Keywords: []string{"import", "def", "return", "if", "elif", "else", "and", "or", "not", "True", "False"},
+		Kinds: map[string]*kind{
+			"Math": {
+				Open:     "import math{CRLF}{CRLF}",
+				Header:   "def gepModel(d):",
+				Footer:   "{TAB}return {tempvarname}",
+				Typename: "float",
+				Tempvar:  "{TAB}y = 0.0",
+				Constant: "{TAB}{labelname} = {labelindex}{CRLF}",
+			},
+			"Boolean": {
+				Header:   "def gepModel(d):",
+				Footer:   "{TAB}return {tempvarname}",
+				Typename: "bool",
+				Tempvar:  "{TAB}y = False",
+				Constant: "{TAB}{labelname} = {labelindex}{CRLF}",
+			},
+		},
+	},
+	{
+		Name:     "C",
+		Prefix:   "c",
+		Ext:      "c",
+		Comment:  "//",
+		Endline:  ";{CRLF}",
+		Indent:   1,
+		Types:    map[string]string{"float64": "double", "bool": "bool"},
+		Keywords: []string{"include", "double", "bool", "const", "int", "return", "if", "else", "true", "false"},
+		Kinds: map[string]*kind{
+			"Math": {
+				Open:     "#include &lt;math.h&gt;{CRLF}{CRLF}",
+				Header:   "double gepModel(double d[]) {",
+				Footer:   "{TAB}return {tempvarname};{CRLF}}",
+				Typename: "double",
+				Tempvar:  "{TAB}double y = 0.0;",
+				Constant: "{TAB}const int {labelname} = {labelindex};{CRLF}",
+			},
+			"Boolean": {
+				Open:     "#include &lt;stdbool.h&gt;{CRLF}{CRLF}",
+				Header:   "bool gepModel(bool d[]) {",
+				Footer:   "{TAB}return {tempvarname};{CRLF}}",
+				Typename: "bool",
+				Tempvar:  "{TAB}bool y = false;",
+				Constant: "{TAB}const int {labelname} = {labelindex};{CRLF}",
+			},
+		},
+	},
+	{
+		Name:     "JavaScript",
+		Prefix:   "javascript",
+		Ext:      "js",
+		Comment:  "//",
+		Endline:  ";{CRLF}",
+		Indent:   1,
+		Types:    map[string]string{"float64": "number", "bool": "boolean"},
+		Keywords: []string{"function", "var", "const", "return", "if", "else", "true", "false"},
+		Kinds: map[string]*kind{
+			"Math": {
+				Open:     "&quot;use strict&quot;;{CRLF}{CRLF}",
+				Header:   "function gepModel(d) {",
+				Footer:   "{TAB}return {tempvarname};{CRLF}}",
+				Typename: "number",
+				Tempvar:  "{TAB}var y = 0.0;",
+				Constant: "{TAB}const {labelname} = {labelindex};{CRLF}",
+			},
+			"Boolean": {
+				Open:     "&quot;use strict&quot;;{CRLF}{CRLF}",
+				Header:   "function gepModel(d) {",
+				Footer:   "{TAB}return {tempvarname};{CRLF}}",
+				Typename: "boolean",
+				Tempvar:  "{TAB}var y = false;",
+				Constant: "{TAB}const {labelname} = {labelindex};{CRLF}",
+			},
+		},
+	},
+	{
+		Name:     "Java",
+		Prefix:   "java",
+		Ext:      "java",
+		Comment:  "//",
+		Endline:  ";{CRLF}",
+		Indent:   2,
+		Types:    map[string]string{"float64": "double", "bool": "boolean"},
+		Keywords: []string{"public", "private", "final", "static", "class", "double", "boolean", "int", "return", "if", "else", "true", "false"},
+		Kinds: map[string]*kind{
+			"Math": {
+				Open:     "public final class GepModel {{CRLF}",
+				Close:    "}{CRLF}",
+				Header:   "{TAB}public static double gepModel(double[] d) {",
+				Footer:   "{TAB}{TAB}return {tempvarname};{CRLF}{TAB}}",
+				Typename: "double",
+				Tempvar:  "{TAB}{TAB}double y = 0.0;",
+				Constant: "{TAB}{TAB}final int {labelname} = {labelindex};{CRLF}",
+			},
+			"Boolean": {
+				Open:     "public final class GepModel {{CRLF}",
+				Close:    "}{CRLF}",
+				Header:   "{TAB}public static boolean gepModel(boolean[] d) {",
+				Footer:   "{TAB}{TAB}return {tempvarname};{CRLF}{TAB}}",
+				Typename: "boolean",
+				Tempvar:  "{TAB}{TAB}boolean y = false;",
+				Constant: "{TAB}{TAB}final int {labelname} = {labelindex};{CRLF}",
+			},
+		},
+	},
+}
+
+// goLanguage describes the Go grammars generated from the function sets.
+var goLanguage = &language{
+	Name:     "Go",
+	Prefix:   "go",
+	Ext:      "go",
+	Comment:  "//",
+	Endline:  "{CRLF}",
+	Indent:   1,
+	Keywords: []string{"package", "func", "const", "var", "return", "if", "else", "for", "range", "make", "int"},
+	Kinds: map[string]*kind{
+		"Int": {
+			Open:     "package gepModel{CRLF}{CRLF}",
+			Header:   "func gepModel(d []int) int {",
+			Footer:   "return {tempvarname}{CRLF}}",
+			Typename: "int",
+			Tempvar:  "var y int",
+			Constant: "{TAB}const {labelname} = {labelindex}{CRLF}",
+			Tuple: &kind{
+				Header:   "func gepModel(d []int) []int {",
+				Footer:   "return {tempvarname}{CRLF}}",
+				Typename: "[]int",
+				Tempvar:  "y := make([]int, {GENE_COUNT})",
+			},
+		},
+		"VectorInt": {
+			Open:     "package gepModel{CRLF}{CRLF}",
+			Header:   "func gepModel(d [][]int) []int {",
+			Footer:   "return {tempvarname}{CRLF}}",
+			Typename: "[]int",
+			Tempvar:  "var y []int",
+			Constant: "{TAB}const {labelname} = {labelindex}{CRLF}",
+			Vector:   "gepVConst(d, {value})",
+			Tuple: &kind{
+				Header:   "func gepModel(d [][]int) [][]int {",
+				Footer:   "return {tempvarname}{CRLF}}",
+				Typename: "[][]int",
+				Tempvar:  "y := make([][]int, {GENE_COUNT})",
+			},
+		},
+	},
+}
+
+// goOrder is the code structure of the generated Go grammars.
+var goOrder = []string{
+	"ModelComments", "Open", "Header", "RandomConstants", "Constants", "TemporaryVariable",
+	"Body", "Footer", "Helpers", "LinkingHelpers", "DDF", "UDF", "Close",
+}
+
+// tuple is the linking function that returns the output of each gene
+// as one element of the result.
+var tuple = grammars.Function{
+	Idx:           10000,
+	SymbolName:    "tuple",
+	TerminalCount: 2,
+	Uniontype:     "{tempvarname}[{index}] = {member}",
+}
+
+// goSources lists the Go grammars that are generated from the function sets.
+var goSources = []struct {
+	kind     string
+	filename string
+	generate func() (*templateData, error)
+}{
+	{kind: "Int", filename: "go.Int.00.default.grm.xml", generate: intGrammar},
+	{kind: "VectorInt", filename: "go.VectorInt.00.default.grm.xml", generate: vectorIntGrammar},
+}
+
+// sources lists the Go grammars that are translated.
+var sources = []struct {
+	kind     string
+	filename string // the generated filename without the language prefix
+	load     func() (*grammars.Grammar, error)
+}{
+	{kind: "Math", filename: "Math.00.default.grm.xml", load: grammars.LoadGoMathGrammar},
+	{kind: "Boolean", filename: "Boolean.01.AllGates.grm.xml", load: grammars.LoadGoBooleanAllGatesGrammar},
+}
+
+// javaHelpers provides the inverse hyperbolic functions that are
+// missing from java.lang.Math.
+var javaHelpers = []grammars.Helper{
+	{Replaces: "Asinh", Chardata: "func gepAsinh(x float64) float64 {\n\treturn math.Log(x + math.Sqrt(x*x+1.0))\n}\n"},
+	{Replaces: "Acosh", Chardata: "func gepAcosh(x float64) float64 {\n\treturn math.Log(x + math.Sqrt(x*x-1.0))\n}\n"},
+	{Replaces: "Atanh", Chardata: "func gepAtanh(x float64) float64 {\n\treturn 0.5 * math.Log((1.0+x)/(1.0-x))\n}\n"},
+}
+
+func main() {
+	flag.Parse()
+
+	for _, src := range goSources {
+		t, err := src.generate()
+		if err != nil {
+			log.Fatalf("Go %v: %v", src.kind, err)
+		}
+		if err := t.dump(src.filename); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	for _, src := range sources {
+		gr, err := src.load()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, lang := range languages {
+			t, err := lang.translate(src.kind, gr)
+			if err != nil {
+				log.Fatalf("%v %v: %v", lang.Name, src.kind, err)
+			}
+			t.Source = fmt.Sprintf("go.%v", src.filename)
+			if err := t.dump(fmt.Sprintf("%v.%v", lang.Prefix, src.filename)); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	logf("Done.")
+}
+
+func logf(fmt string, args ...any) {
+	if *verbose {
+		log.Printf(fmt, args...)
+	}
+}
+
+type templateData struct {
+	*language
+	*kind
+	Source           string
+	Type             string
+	Order            []string
+	Functions        []grammars.Function
+	Helpers          []grammars.Helper
+	LinkingFunctions []grammars.Helper
+	BasicFunctions   []grammars.Helper
+}
+
+func (t *templateData) dump(filename string) error {
+	var buf bytes.Buffer
+	if err := sourceTmpl.Execute(&buf, t); err != nil {
+		return err
+	}
+	logf("Writing %v...", filename)
+	return os.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// translate translates the Go grammar gr of the given kind to the language.
+func (l *language) translate(kind string, gr *grammars.Grammar) (*templateData, error) {
+	t := &templateData{language: l, kind: l.Kinds[kind], Type: gr.Type}
+	if l.Prefix == "python" && kind == "Math" {
+		t.BasicFunctions = pythonBasicFunctions
+	}
+	for _, item := range gr.Order {
+		t.Order = append(t.Order, item.Name)
+		if item.Name == "Open" && l.Prefix == "c" {
+			t.Order = append(t.Order, "Prototypes")
+		}
+		if item.Name == "Helpers" && len(t.BasicFunctions) > 0 {
+			t.Order = append(t.Order, "BasicFunctions")
+		}
+	}
+
+	for _, f := range gr.Functions.Functions {
+		chardata, err := l.guard(f.Chardata)
+		if err != nil {
+			return nil, fmt.Errorf("function %v: %w", f.SymbolName, err)
+		}
+		if chardata != f.Chardata && f.Uniontype != "" {
+			// The union type (such as "{tempvarname} {symbol}= {member}")
+			// must call the same guarded functions.
+			f.Uniontype = "{tempvarname} = " + x0RE.ReplaceAllString(x1RE.ReplaceAllString(chardata, "{member}"), "{tempvarname}")
+		}
+		if f.Chardata, err = l.expression(chardata); err != nil {
+			return nil, fmt.Errorf("function %v: %w", f.SymbolName, err)
+		}
+		if f.Uniontype, err = l.expression(f.Uniontype); err != nil {
+			return nil, fmt.Errorf("function %v: %w", f.SymbolName, err)
+		}
+		t.Functions = append(t.Functions, f)
+	}
+
+	helpers := gr.Helpers.Helpers
+	if l.Prefix == "java" && kind == "Math" {
+		helpers = append(helpers, javaHelpers...)
+	}
+	var err error
+	if t.Helpers, err = l.helpers(helpers); err != nil {
+		return nil, err
+	}
+	if t.LinkingFunctions, err = l.helpers(gr.LinkingFunctions.LinkingFunctions); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (l *language) helpers(hs []grammars.Helper) ([]grammars.Helper, error) {
+	var result []grammars.Helper
+	for _, h := range hs {
+		chardata, prototype, err := l.helper(h.Chardata)
+		if err != nil {
+			return nil, fmt.Errorf("helper %v: %w", h.Replaces, err)
+		}
+		result = append(result, grammars.Helper{Replaces: h.Replaces, Prototype: prototype, Chardata: chardata})
+	}
+	return result, nil
+}
+
+// intSource is the integer function set from which the Go Int grammar is generated.
+const intSource = "../functions/int_nodes/ints.go"
+
+var argRE = regexp.MustCompile(`\bx\[(\d)\]`)
+
+// intGrammar generates the Go grammar of the integer function set by
+// parsing the function literals of intNodes.Int and the helpers they call.
+func intGrammar() (*templateData, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, intSource, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	decls := map[string]*ast.FuncDecl{}
+	var funcs *ast.CompositeLit
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				decls[decl.Name.Name] = decl
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok && len(vs.Names) == 1 && vs.Names[0].Name == "Int" {
+					funcs, _ = vs.Values[0].(*ast.CompositeLit)
+				}
+			}
+		}
+	}
+	if funcs == nil {
+		return nil, fmt.Errorf("unable to find the Int function map in %v", intSource)
+	}
+
+	t := &templateData{
+		language: goLanguage,
+		kind:     goLanguage.Kinds["Int"],
+		Source:   "functions/int_nodes/ints.go",
+		Order:    goOrder,
+	}
+	for _, elt := range funcs.Elts {
+		fn, ret, err := intNode(fset, elt)
+		if err != nil {
+			return nil, err
+		}
+		var helpers []string
+		for _, name := range calls(ret, decls, nil) {
+			var buf bytes.Buffer
+			if err := printer.Fprint(&buf, fset, decls[name]); err != nil {
+				return nil, err
+			}
+			helpers = append(helpers, buf.String())
+		}
+		t.addFunction(fn, strings.Join(helpers, "\n\n"))
+	}
+	t.Functions = append(t.Functions, tuple)
+
+	for _, h := range t.Helpers {
+		if strings.Contains(h.Chardata, "math.") {
+			return nil, fmt.Errorf("helper %v: unsupported use of the math package", h.Replaces)
+		}
+	}
+	return t, nil
+}
+
+// intNode parses one entry of the form `"+": IntNode{0, "+", 2, func(x []int) int { return (x[0] + x[1]) }}`
+// and returns its function and the expression it returns.
+func intNode(fset *token.FileSet, elt ast.Expr) (grammars.Function, ast.Expr, error) {
+	var fn grammars.Function
+	kv, ok := elt.(*ast.KeyValueExpr)
+	if !ok {
+		return fn, nil, fmt.Errorf("unexpected Int entry at %v", fset.Position(elt.Pos()))
+	}
+	node, ok := kv.Value.(*ast.CompositeLit)
+	if !ok || len(node.Elts) != 4 {
+		return fn, nil, fmt.Errorf("unexpected IntNode at %v", fset.Position(kv.Pos()))
+	}
+	lits := make([]string, 3)
+	for i := range lits {
+		lit, ok := node.Elts[i].(*ast.BasicLit)
+		if !ok {
+			return fn, nil, fmt.Errorf("unexpected IntNode field at %v", fset.Position(node.Elts[i].Pos()))
+		}
+		lits[i] = lit.Value
+	}
+	body, ok := node.Elts[3].(*ast.FuncLit)
+	if !ok || len(body.Body.List) != 1 {
+		return fn, nil, fmt.Errorf("unexpected IntNode function at %v", fset.Position(node.Elts[3].Pos()))
+	}
+	ret, ok := body.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return fn, nil, fmt.Errorf("unexpected IntNode function at %v", fset.Position(body.Pos()))
+	}
+
+	var err error
+	if fn.Idx, err = strconv.Atoi(lits[0]); err != nil {
+		return fn, nil, err
+	}
+	if fn.SymbolName, err = strconv.Unquote(lits[1]); err != nil {
+		return fn, nil, err
+	}
+	if fn.TerminalCount, err = strconv.Atoi(lits[2]); err != nil {
+		return fn, nil, err
+	}
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, ret.Results[0]); err != nil {
+		return fn, nil, err
+	}
+	fn.Chardata = argRE.ReplaceAllString(buf.String(), "x$1")
+	return fn, ret.Results[0], nil
+}
+
+// calls returns the names of the functions in decls that are called by
+// node, directly or indirectly, in the order they are first called.
+func calls(node ast.Node, decls map[string]*ast.FuncDecl, names []string) []string {
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		id, ok := call.Fun.(*ast.Ident)
+		if !ok || decls[id.Name] == nil {
+			return true
+		}
+		for _, name := range names {
+			if name == id.Name {
+				return true
+			}
+		}
+		names = calls(decls[id.Name].Body, decls, append(names, id.Name))
+		return true
+	})
+	return names
+}
+
+// vectorIntHelpers are the Go sources of the vector of integers functions,
+// which apply the integer operation to each index of their arguments
+// just like vectorIntNodes.ProcessVector.
+var vectorIntHelpers = []struct {
+	symbol, name, op string
+}{
+	{symbol: "+", name: "gepVAdd", op: "result[i] = x[i] + y[i]"},
+	{symbol: "-", name: "gepVSub", op: "result[i] = x[i] - y[i]"},
+	{symbol: "*", name: "gepVMul", op: "result[i] = x[i] * y[i]"},
+	{symbol: "/", name: "gepVDiv", op: "if x[i] != 0 {\n\t\t\tresult[i] = x[i] / y[i]\n\t\t}"},
+}
+
+// vectorConstant broadcasts a random constant to the length of the first input.
+const vectorConstant = `func gepVConst(d [][]int, c int) []int {
+	if len(d) == 0 {
+		return nil
+	}
+	result := make([]int, len(d[0]))
+	for i := range result {
+		result[i] = c
+	}
+	return result
+}`
+
+// vectorIntGrammar generates the Go grammar of the vector of integers function set.
+func vectorIntGrammar() (*templateData, error) {
+	t := &templateData{
+		language: goLanguage,
+		kind:     goLanguage.Kinds["VectorInt"],
+		Source:   "functions/vector_int_nodes/vector_int.go",
+		Order:    goOrder,
+	}
+	for i, h := range vectorIntHelpers {
+		fn := grammars.Function{Idx: i, SymbolName: h.symbol, TerminalCount: 2, Chardata: h.name + "(x0, x1)"}
+		src := fmt.Sprintf("func %v(x, y []int) []int {\n\tresult := make([]int, len(x))\n\tfor i := range x {\n\t\t%v\n\t}\n\treturn result\n}", h.name, h.op)
+		t.addFunction(fn, src)
+	}
+	t.Functions = append(t.Functions, tuple)
+	t.Helpers = append(t.Helpers, goHelper("c", vectorConstant))
+	return t, nil
+}
+
+// addFunction adds the Go function and its helper source (if any) to the
+// grammar. Diadic functions may also link the genes, so their union type
+// is derived from their definition and their helper is also a linking function.
+func (t *templateData) addFunction(fn grammars.Function, helper string) {
+	if fn.TerminalCount == 2 {
+		fn.Uniontype = "{tempvarname} = " + strings.NewReplacer("x0", "{tempvarname}", "x1", "{member}").Replace(fn.Chardata)
+	}
+	t.Functions = append(t.Functions, fn)
+	if helper == "" {
+		return
+	}
+	h := goHelper(fn.SymbolName, helper)
+	t.Helpers = append(t.Helpers, h)
+	if fn.TerminalCount == 2 {
+		t.LinkingFunctions = append(t.LinkingFunctions, h)
+	}
+}
+
+// goHelper encodes the Go source of a helper function for a grammar.
+func goHelper(symbol, src string) grammars.Helper {
+	// The generated helpers must not need any imports.
+	src = strings.ReplaceAll(src, "math.MaxInt", "int(^uint(0) >> 1)")
+	chardata := strings.NewReplacer("\n", "{CRLF}", "\t", "{TAB}").Replace(src + "\n")
+	return grammars.Helper{Replaces: symbol, Chardata: chardata}
+}
+
+var (
+	funcRE    = regexp.MustCompile(`^func (\w+)\((.*)\) (\w+) {$`)
+	ifRE      = regexp.MustCompile(`^if (.*) {$`)
+	elseIfRE  = regexp.MustCompile(`^} else if (.*) {$`)
+	elseRE    = regexp.MustCompile(`^} else {$`)
+	varRE     = regexp.MustCompile(`^var (\w+) (\w+)$`)
+	constRE   = regexp.MustCompile(`^const (\w+) = (.*)$`)
+	defineRE  = regexp.MustCompile(`^(\w+) := (.*)$`)
+	assignRE  = regexp.MustCompile(`^(\w+) = (.*)$`)
+	returnRE  = regexp.MustCompile(`^return (.*)$`)
+	commentRE = regexp.MustCompile(`^//(.*)$`)
+)
+
+// helper translates the Go source of a helper function line by line and
+// returns the translation and its prototype (for C only).
+func (l *language) helper(chardata string) (string, string, error) {
+	src := strings.NewReplacer("{CRLF}", "\n", "{TAB}", "\t", "{CHARX}", "x").Replace(chardata)
+	var lines []string
+	var prototype, retType string
+	for _, line := range strings.Split(strings.TrimRight(src, "\n"), "\n") {
+		depth := len(line) - len(strings.TrimLeft(line, "\t"))
+		line = strings.TrimSpace(line)
+		indent := strings.Repeat("\t", depth)
+		if l.Prefix == "java" {
+			indent += "\t" // methods are nested within the class
+		}
+
+		var s, e string
+		var err error
+		switch {
+		case line == "":
+			lines = append(lines, "")
+			continue
+		case funcRE.MatchString(line):
+			m := funcRE.FindStringSubmatch(line)
+			retType = m[3]
+			s, prototype = l.signature(m[1], m[2], retType)
+		case ifRE.MatchString(line):
+			if e, err = l.guard(ifRE.FindStringSubmatch(line)[1]); err == nil {
+				s, err = l.block("if", e)
+			}
+		case elseIfRE.MatchString(line):
+			if e, err = l.guard(elseIfRE.FindStringSubmatch(line)[1]); err == nil {
+				s, err = l.block("else if", e)
+			}
+		case elseRE.MatchString(line):
+			s, err = l.block("else", "")
+		case line == "}":
+			if l.Prefix == "python" {
+				continue
+			}
+			s = "}"
+		case varRE.MatchString(line):
+			m := varRE.FindStringSubmatch(line)
+			s = l.declare("var", m[1], m[2], zero(m[2]))
+		case constRE.MatchString(line):
+			m := constRE.FindStringSubmatch(line)
+			if e, err = l.guard(m[2]); err == nil {
+				s = l.declare("const", m[1], retType, e)
+			}
+		case defineRE.MatchString(line):
+			m := defineRE.FindStringSubmatch(line)
+			if e, err = l.guard(m[2]); err == nil {
+				s = l.declare("var", m[1], retType, e)
+			}
+		case assignRE.MatchString(line):
+			m := assignRE.FindStringSubmatch(line)
+			if e, err = l.guard(m[2]); err == nil {
+				s = m[1] + " = " + e + l.semicolon()
+			}
+		case returnRE.MatchString(line):
+			if e, err = l.guard(returnRE.FindStringSubmatch(line)[1]); err == nil {
+				s = "return " + e + l.semicolon()
+			}
+		case commentRE.MatchString(line):
+			s = l.Comment + commentRE.FindStringSubmatch(line)[1]
+		default:
+			return "", "", fmt.Errorf("unable to translate line %q", line)
+		}
+		if err == nil {
+			s, err = l.expression(s)
+		}
+		if err != nil {
+			return "", "", err
+		}
+		lines = append(lines, indent+s)
+	}
+
+	result := strings.Join(lines, "\n") + "\n"
+	return strings.NewReplacer("\n", "{CRLF}", "\t", "{TAB}").Replace(result), prototype, nil
+}
+
+// signature translates the parameter list of a Go function, such as
+// "x, y float64", and returns the function header and its prototype.
+func (l *language) signature(name, params, retType string) (string, string) {
+	fields := strings.Split(params, ", ")
+	names := make([]string, len(fields))
+	types := make([]string, len(fields))
+	typ := ""
+	for i := len(fields) - 1; i >= 0; i-- {
+		parts := strings.Fields(fields[i])
+		if len(parts) == 2 {
+			typ = parts[1]
+		}
+		names[i], types[i] = parts[0], l.Types[typ]
+	}
+
+	typed := make([]string, len(names))
+	for i := range names {
+		typed[i] = types[i] + " " + names[i]
+	}
+
+	switch l.Prefix {
+	case "python":
+		return fmt.Sprintf("def %v(%v):", name, strings.Join(names, ", ")), ""
+	case "c":
+		header := fmt.Sprintf("%v %v(%v)", l.Types[retType], name, strings.Join(typed, ", "))
+		return header + " {", header + ";"
+	case "javascript":
+		return fmt.Sprintf("function %v(%v) {", name, strings.Join(names, ", ")), ""
+	default:
+		return fmt.Sprintf("private static %v %v(%v) {", l.Types[retType], name, strings.Join(typed, ", ")), ""
+	}
+}
+
+// block translates the opening line of an if, else if or else block.
+func (l *language) block(keyword, cond string) (string, error) {
+	cond = strings.TrimSpace(cond)
+	if l.Prefix == "python" {
+		switch keyword {
+		case "if":
+			return "if " + cond + ":", nil
+		case "else if":
+			return "elif " + cond + ":", nil
+		default:
+			return "else:", nil
+		}
+	}
+	switch keyword {
+	case "if":
+		return "if (" + cond + ") {", nil
+	case "else if":
+		return "} else if (" + cond + ") {", nil
+	default:
+		return "} else {", nil
+	}
+}
+
+// declare translates the declaration of a variable or constant of the given Go type.
+func (l *language) declare(decl, name, typ, value string) string {
+	switch l.Prefix {
+	case "python":
+		return name + " = " + value
+	case "javascript":
+		return decl + " " + name + " = " + value + ";"
+	}
+	if decl == "const" {
+		if l.Prefix == "java" {
+			return "final " + l.Types[typ] + " " + name + " = " + value + ";"
+		}
+		return "const " + l.Types[typ] + " " + name + " = " + value + ";"
+	}
+	return l.Types[typ] + " " + name + " = " + value + ";"
+}
+
+func (l *language) semicolon() string {
+	if l.Prefix == "python" {
+		return ""
+	}
+	return ";"
+}
+
+func zero(typ string) string {
+	if typ == "bool" {
+		return "false"
+	}
+	return "0.0"
+}
+
+var (
+	mathRE  = regexp.MustCompile(`math\.([A-Z]\w*)`)
+	andRE   = regexp.MustCompile(`\s*&&\s*`)
+	orRE    = regexp.MustCompile(`\s*\|\|\s*`)
+	notRE   = regexp.MustCompile(`!\(`)
+	trueRE  = regexp.MustCompile(`\btrue\b`)
+	falseRE = regexp.MustCompile(`\bfalse\b`)
+)
+
+const truncPrefix = "float64(int("
+
+// expression translates a Go expression (or a whole translated statement).
+func (l *language) expression(s string) (string, error) {
+	s = strings.ReplaceAll(s, "{CHARX}", "x")
+
+	// float64(int(e)) truncates e toward zero.
+	for {
+		i := strings.Index(s, truncPrefix)
+		if i < 0 {
+			break
+		}
+		start := i + len(truncPrefix)
+		end, err := closing(s, start)
+		if err != nil {
+			return "", err
+		}
+		if end+1 >= len(s) || s[end+1] != ')' {
+			return "", fmt.Errorf("unable to translate %q", s)
+		}
+		s = s[:i] + l.trunc(s[start:end]) + s[end+2:]
+	}
+
+	var err error
+	s = mathRE.ReplaceAllStringFunc(s, func(m string) string {
+		name := m[len("math."):]
+		v, ok := l.math(name)
+		if !ok {
+			err = fmt.Errorf("unsupported function math.%v", name)
+		}
+		return v
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if l.Prefix == "python" {
+		s = andRE.ReplaceAllString(s, " and ")
+		s = orRE.ReplaceAllString(s, " or ")
+		s = notRE.ReplaceAllString(s, "not (")
+		s = trueRE.ReplaceAllString(s, "True")
+		s = falseRE.ReplaceAllString(s, "False")
+	}
+	return s, nil
+}
+
+// closing returns the index of the parenthesis that closes the one opened just before start.
+func closing(s string, start int) (int, error) {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses in %q", s)
+}
+
+func (l *language) trunc(e string) string {
+	switch l.Prefix {
+	case "python":
+		return "gepTrunc(" + e + ")"
+	case "c":
+		return "trunc(" + e + ")"
+	case "javascript":
+		return "Math.trunc(" + e + ")"
+	default:
+		return "(double)(long)(" + e + ")"
+	}
+}
+
+// math translates the name of a function or constant of Go's math package.
+func (l *language) math(name string) (string, bool) {
+	switch name {
+	case "Pi":
+		switch l.Prefix {
+		case "python":
+			return "math.pi", true
+		case "c":
+			return "3.141592653589793", true
+		default:
+			return "Math.PI", true
+		}
+	case "E":
+		switch l.Prefix {
+		case "python":
+			return "math.e", true
+		case "c":
+			return "2.718281828459045", true
+		default:
+			return "Math.E", true
+		}
+	case "Abs", "Min", "Max":
+		switch l.Prefix {
+		case "python":
+			return strings.ToLower(name), true
+		case "c":
+			return "f" + strings.ToLower(name), true
+		}
+	case "Asinh", "Acosh", "Atanh":
+		if l.Prefix == "java" {
+			return "gep" + name, true
+		}
+	case "Pow", "Sqrt", "Exp", "Log", "Log10", "Floor", "Ceil",
+		"Sin", "Cos", "Tan", "Asin", "Acos", "Atan", "Sinh", "Cosh", "Tanh":
+	default:
+		return "", false
+	}
+
+	lower := strings.ToLower(name[:1]) + name[1:]
+	switch l.Prefix {
+	case "python":
+		return "math." + lower, true
+	case "c":
+		return lower, true
+	default:
+		return "Math." + lower, true
+	}
+}
+
+// pythonGuards maps the functions of Go's math package that raise an
+// exception in Python, instead of returning ±Inf or NaN like Go, to the
+// basic functions of the Python grammar that return the same values as Go.
+var pythonGuards = map[string]string{
+	"Pow": "gepPow", "Sqrt": "gepSqrt", "Exp": "gepExp", "Log": "gepLog", "Log10": "gepLog10",
+	"Floor": "gepFloor", "Ceil": "gepCeil", "Sin": "gepSin", "Cos": "gepCos", "Tan": "gepTan",
+	"Asin": "gepAsin", "Acos": "gepAcos", "Sinh": "gepSinh", "Cosh": "gepCosh",
+	"Acosh": "gepAcosh", "Atanh": "gepAtanh",
+}
+
+var (
+	x0RE = regexp.MustCompile(`\bx0\b`)
+	x1RE = regexp.MustCompile(`\bx1\b`)
+)
+
+// guard rewrites the Go expression for Python so that divisions (other
+// than by a non-zero constant) call gepDiv and the functions in
+// pythonGuards call their guarded basic functions. The expression is
+// returned unchanged for the other languages, which follow IEEE 754
+// like Go, or when there is nothing to guard.
+func (l *language) guard(s string) (string, error) {
+	if l.Prefix != "python" {
+		return s, nil
+	}
+	// Union types hold placeholders such as {tempvarname}.
+	placeholders := strings.NewReplacer("{tempvarname}", "gepTempvarname", "{member}", "gepMember", "{CHARX}", "x")
+	e, err := parser.ParseExpr(placeholders.Replace(s))
+	if err != nil {
+		return s, nil // not an expression, such as a union type statement
+	}
+	var changed bool
+	e = guardExpr(e, &changed)
+	if !changed {
+		return s, nil
+	}
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), e); err != nil {
+		return "", err
+	}
+	return strings.NewReplacer("gepTempvarname", "{tempvarname}", "gepMember", "{member}").Replace(buf.String()), nil
+}
+
+func guardExpr(e ast.Expr, changed *bool) ast.Expr {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		e.X = guardExpr(e.X, changed)
+		if call, ok := e.X.(*ast.CallExpr); ok {
+			return call
+		}
+	case *ast.UnaryExpr:
+		e.X = guardExpr(e.X, changed)
+	case *ast.BinaryExpr:
+		e.X, e.Y = guardExpr(e.X, changed), guardExpr(e.Y, changed)
+		if e.Op == token.QUO && !nonZeroConstant(e.Y) {
+			*changed = true
+			return &ast.CallExpr{Fun: ast.NewIdent("gepDiv"), Args: []ast.Expr{e.X, e.Y}}
+		}
+	case *ast.CallExpr:
+		for i, arg := range e.Args {
+			arg = guardExpr(arg, changed)
+			if paren, ok := arg.(*ast.ParenExpr); ok {
+				arg = paren.X // the parentheses are redundant within the arguments
+			}
+			e.Args[i] = arg
+		}
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "math" && pythonGuards[sel.Sel.Name] != "" {
+				*changed = true
+				e.Fun = ast.NewIdent(pythonGuards[sel.Sel.Name])
+			}
+		}
+	}
+	return e
+}
+
+// nonZeroConstant reports whether e is a non-zero numeric literal.
+func nonZeroConstant(e ast.Expr) bool {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || (lit.Kind != token.INT && lit.Kind != token.FLOAT) {
+		return false
+	}
+	v, err := strconv.ParseFloat(lit.Value, 64)
+	return err == nil && v != 0
+}
+
+// pythonBasicFunctions are the guarded Python functions called by the
+// translated Math functions and helpers, so that the generated code returns
+// ±Inf or NaN like the Go model instead of raising ZeroDivisionError,
+// ValueError or OverflowError. Each one is only written when it is called.
+var pythonBasicFunctions = []grammars.Helper{
+	pythonBasic("gepDiv", `def gepDiv(x, y):
+	try:
+		return x / y
+	except ZeroDivisionError:
+		if x == 0 or math.isnan(x):
+			return math.nan
+		return math.copysign(math.inf, x) * math.copysign(1.0, y)`),
+	pythonBasic("gepPow", `def gepPow(x, y):
+	try:
+		return math.pow(x, y)
+	except OverflowError:
+		if x < 0 and y % 2 == 1:
+			return -math.inf
+		return math.inf
+	except ValueError:
+		if x != 0:
+			return math.nan
+		if y % 2 == 1:
+			return math.copysign(math.inf, x)
+		return math.inf`),
+	pythonBasic("gepSqrt", `def gepSqrt(x):
+	if x >= 0:
+		return math.sqrt(x)
+	return math.nan`),
+	pythonBasic("gepExp", `def gepExp(x):
+	try:
+		return math.exp(x)
+	except OverflowError:
+		return math.inf`),
+	pythonBasic("gepLog", `def gepLog(x):
+	if x > 0:
+		return math.log(x)
+	if x == 0:
+		return -math.inf
+	return math.nan`),
+	pythonBasic("gepLog10", `def gepLog10(x):
+	if x > 0:
+		return math.log10(x)
+	if x == 0:
+		return -math.inf
+	return math.nan`),
+	pythonBasic("gepTrunc", `def gepTrunc(x):
+	if math.isfinite(x):
+		return float(math.trunc(x))
+	return x`),
+	pythonBasic("gepFloor", `def gepFloor(x):
+	if math.isfinite(x):
+		return float(math.floor(x))
+	return x`),
+	pythonBasic("gepCeil", `def gepCeil(x):
+	if math.isfinite(x):
+		return float(math.ceil(x))
+	return x`),
+	pythonBasic("gepSin", `def gepSin(x):
+	if math.isinf(x):
+		return math.nan
+	return math.sin(x)`),
+	pythonBasic("gepCos", `def gepCos(x):
+	if math.isinf(x):
+		return math.nan
+	return math.cos(x)`),
+	pythonBasic("gepTan", `def gepTan(x):
+	if math.isinf(x):
+		return math.nan
+	return math.tan(x)`),
+	pythonBasic("gepAsin", `def gepAsin(x):
+	if -1 <= x <= 1:
+		return math.asin(x)
+	return math.nan`),
+	pythonBasic("gepAcos", `def gepAcos(x):
+	if -1 <= x <= 1:
+		return math.acos(x)
+	return math.nan`),
+	pythonBasic("gepSinh", `def gepSinh(x):
+	try:
+		return math.sinh(x)
+	except OverflowError:
+		return math.copysign(math.inf, x)`),
+	pythonBasic("gepCosh", `def gepCosh(x):
+	try:
+		return math.cosh(x)
+	except OverflowError:
+		return math.inf`),
+	pythonBasic("gepAcosh", `def gepAcosh(x):
+	if x >= 1:
+		return math.acosh(x)
+	return math.nan`),
+	pythonBasic("gepAtanh", `def gepAtanh(x):
+	if -1 < x < 1:
+		return math.atanh(x)
+	if x == 1 or x == -1:
+		return math.copysign(math.inf, x)
+	return math.nan`),
+}
+
+// pythonBasic encodes the Python source of a basic function for a grammar.
+func pythonBasic(name, src string) grammars.Helper {
+	chardata := strings.NewReplacer("\n", "{CRLF}", "\t", "{TAB}").Replace(src + "\n")
+	return grammars.Helper{Replaces: name, Chardata: chardata}
+}
+
+const source = `<?xml version="1.0" standalone="no"?>
+<!DOCTYPE grammar SYSTEM "grammar.dtd">
+<grammar name="{{.Name}}" version="5" ext="{{.Ext}}" type="{{.Type}}">
+  <!--Copyright 2014 Google Inc. All rights reserved.-->
+  <!--Use of this source code is governed by the Apache 2.0-->
+  <!--license that can be found in the LICENSE file.-->
+  <!--Code generated by gen-grammars.go from {{.Source}}. DO NOT EDIT.-->
+{{- if .Vector}}
+  <!--Random constants are broadcast to the length of d[0] by gepVConst.-->
+{{- end}}
+  <!--To generate Carriage Return Line Feeds (CrLf) use the token {CRLF} (curly braces included).-->
+  <!--To generate Tabs use the token {TAB} (curly braces included).-->
+  <functions count="{{len .Functions}}">
+{{- range .Functions}}
+    <function idx="{{.Idx}}" symbol="{{esc .SymbolName}}" terminals="{{.TerminalCount}}" uniontype="{{esc .Uniontype}}">{{esc .Chardata}}</function>
+{{- end}}
+  </functions>
+  <!-- Code Structure -->
+  <order>
+{{- range .Order}}
+    <item name="{{.}}" />
+{{- end}}
+  </order>
+  <!-- Opening and Closing Statements -->
+  <open>{{.Open}}</open>
+  <close>{{.Close}}</close>
+  <!-- The default header is applied to all non specified cases. -->
+  <headers>
+    <header type="default" replace="no">{{.Header}}</header>
+{{- with .Tuple}}
+    <header type="tuple" replace="no">{{.Header}}</header>
+{{- end}}
+  </headers>
+  <subheaders>
+    <subheader type="default" replace="no"></subheader>
+  </subheaders>
+  <randomconstants>
+    <randomconst type="default" replace="no">{{.Constant}}</randomconst>
+{{- with .Vector}}
+    <randomconst type="vector" replace="no">{{.}}</randomconst>
+{{- end}}
+  </randomconstants>
+  <!-- Label constants -->
+  <constants>
+    <constant type="default" replace="no" labelindex="0">{{.Constant}}</constant>
+  </constants>
+  <!-- The default temporary variable name is applied to all non specified cases. -->
+  <tempvars>
+    <tempvar type="default" typename="{{esc .Typename}}" varname="y">{{.Tempvar}}</tempvar>
+{{- with .Tuple}}
+    <tempvar type="tuple" typename="{{esc .Typename}}" varname="y">{{.Tempvar}}</tempvar>
+{{- end}}
+  </tempvars>
+  <endline>{{.Endline}}</endline>
+  <!-- Number of TABs to add to each line in the code block -->
+  <indent>{{.Indent}}</indent>
+  <!-- parenstype can be either 0->() or 1->[]. Defines the parentheses used in arrays-->
+  <parenstype>1</parenstype>
+  <footers>
+    <footer type="default" replace="no">{{.Footer}}</footer>
+{{- with .Tuple}}
+    <footer type="tuple" replace="no">{{.Footer}}</footer>
+{{- end}}
+  </footers>
+  <helpers count="{{len .Helpers}}" declaration="" assignment="">
+{{- range .Helpers}}
+    <helper replaces="{{esc .Replaces}}" prototype="{{esc .Prototype}}">{{esc .Chardata}}</helper>
+{{- end}}
+  </helpers>
+  <keywords>
+{{- range .Keywords}}
+    <keyword>{{.}}</keyword>
+{{- end}}
+  </keywords>
+  <commentmark>{{.Comment}}</commentmark>
+  <linkingFunctions count="{{len .LinkingFunctions}}">
+{{- range .LinkingFunctions}}
+    <linkingFunction replaces="{{esc .Replaces}}" prototype="{{esc .Prototype}}">{{esc .Chardata}}</linkingFunction>
+{{- end}}
+  </linkingFunctions>
+{{- with .BasicFunctions}}
+  <basicFunctions count="{{len .}}">
+{{- range .}}
+    <basicFunction replaces="{{esc .Replaces}}" prototype="{{esc .Prototype}}">{{esc .Chardata}}</basicFunction>
+{{- end}}
+  </basicFunctions>
+{{- end}}
+  <ddfcomment>{{.Comment}} Add a DDF with the name {FUNCTION_SYMBOL} in {LANGUAGE}{CRLF}{{.Comment}} and a parameter list equivalent to {PARAMETER_LIST}{CRLF}</ddfcomment>
+  <udfcomment>{{.Comment}} Add a UDF with the name {FUNCTION_SYMBOL} in {LANGUAGE}{CRLF}</udfcomment>
+</grammar>
+`
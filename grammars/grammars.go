@@ -2,17 +2,18 @@
 // Use of this source code is governed by the Apache 2.0
 // license that can be found in the LICENSE file.
 
+//go:generate go run gen-grammars.go
+
 // Package grammars parses XML GEP grammar representations for a particular output language.
 // It can then be used to generate code from Karva expressions for that language.
 package grammars
 
 import (
+	"embed"
 	"encoding/xml"
-	"log"
-	"os"
-	"path/filepath"
-	"runtime"
-	"strings"
+	"fmt"
+	"io"
+	"io/fs"
 
 	"github.com/gmlewis/gep/v2/functions"
 )
@@ -257,19 +258,14 @@ type Grammar struct {
 	Testing Testing `xml:"testing"`
 }
 
-func loadGrammar(path string) (*Grammar, error) {
-	v := &Grammar{}
-
-	data, err := os.ReadFile(path)
-	if err != nil {
-		log.Printf("unable to read file %q: %q", path, err)
-		return nil, err
-	}
+//go:embed *.grm.xml
+var bundled embed.FS
 
-	err = xml.Unmarshal(data, &v)
-	if err != nil {
-		log.Printf("error unmarshaling %q: %q", path, err)
-		return nil, err
+// LoadGrammar loads a grammar from its XML representation.
+func LoadGrammar(r io.Reader) (*Grammar, error) {
+	v := &Grammar{}
+	if err := xml.NewDecoder(r).Decode(v); err != nil {
+		return nil, fmt.Errorf("error unmarshaling grammar: %w", err)
 	}
 
 	// Build the function map lookups for fast access
@@ -287,63 +283,110 @@ func loadGrammar(path string) (*Grammar, error) {
 	return v, nil
 }
 
-func getPath(filename string) string {
-	_, packageFile, _, _ := runtime.Caller(0)
-	grammarPath := filepath.Dir(packageFile)
-
-	// Support Travis CI automated builds by searching for files
-	dirs := strings.Split(os.Getenv("GOPATH"), ":")
-	for _, dir := range dirs {
-		name := filepath.Join(dir, "src", grammarPath, filename)
-		if _, err := os.Stat(name); err == nil {
-			return name
-		}
+// LoadGrammarFS loads the named grammar file from the file system,
+// such as an embed.FS holding custom grammars or os.DirFS(dir).
+func LoadGrammarFS(fsys fs.FS, name string) (*Grammar, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
 	}
-	name := filepath.Join(grammarPath, filename)
-	if _, err := os.Stat(name); err == nil {
-		return name
+	defer f.Close()
+
+	v, err := LoadGrammar(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", name, err)
 	}
-	return filename
+	return v, nil
+}
+
+// loadBundled loads one of the grammar files embedded in this package.
+func loadBundled(name string) (*Grammar, error) {
+	return LoadGrammarFS(bundled, name)
 }
 
 // LoadGoMathGrammar loads the floating-point math grammer for Go as the target language.
 func LoadGoMathGrammar() (*Grammar, error) {
-	path := getPath("go.Math.00.default.grm.xml")
-	return loadGrammar(path)
+	return loadBundled("go.Math.00.default.grm.xml")
 }
 
 // LoadGoBooleanAllGatesGrammar loads the general boolean grammer for Go as the target language.
 func LoadGoBooleanAllGatesGrammar() (*Grammar, error) {
-	path := getPath("go.Boolean.01.AllGates.grm.xml")
-	return loadGrammar(path)
+	return loadBundled("go.Boolean.01.AllGates.grm.xml")
 }
 
 // LoadGoBooleanNotAndOrGatesGrammar loads the specialized boolean grammer for Go as the target language.
 func LoadGoBooleanNotAndOrGatesGrammar() (*Grammar, error) {
-	path := getPath("go.Boolean.02.NotAndOrGates.grm.xml")
-	return loadGrammar(path)
+	return loadBundled("go.Boolean.02.NotAndOrGates.grm.xml")
 }
 
 // LoadGoBooleanNandGatesGrammar loads the specialized boolean grammer for Go as the target language.
 func LoadGoBooleanNandGatesGrammar() (*Grammar, error) {
-	path := getPath("go.Boolean.03.NandGates.grm.xml")
-	return loadGrammar(path)
+	return loadBundled("go.Boolean.03.NandGates.grm.xml")
 }
 
 // LoadGoBooleanNorGatesGrammar loads the specialized boolean grammer for Go as the target language.
 func LoadGoBooleanNorGatesGrammar() (*Grammar, error) {
-	path := getPath("go.Boolean.04.NorGates.grm.xml")
-	return loadGrammar(path)
+	return loadBundled("go.Boolean.04.NorGates.grm.xml")
 }
 
 // LoadGoBooleanMuxSystemGrammar loads the specialized boolean grammer for Go as the target language.
 func LoadGoBooleanMuxSystemGrammar() (*Grammar, error) {
-	path := getPath("go.Boolean.05.MuxSystem.grm.xml")
-	return loadGrammar(path)
+	return loadBundled("go.Boolean.05.MuxSystem.grm.xml")
 }
 
 // LoadGoReedMullerSystemGrammar loads the specialized boolean grammer for Go as the target language.
 func LoadGoReedMullerSystemGrammar() (*Grammar, error) {
-	path := getPath("go.Boolean.06.ReedMullerSystem.grm.xml")
-	return loadGrammar(path)
+	return loadBundled("go.Boolean.06.ReedMullerSystem.grm.xml")
+}
+
+// LoadGoIntGrammar loads the integer grammar for Go as the target language.
+// The "tuple" linking function renders a model returning []int.
+func LoadGoIntGrammar() (*Grammar, error) {
+	return loadBundled("go.Int.00.default.grm.xml")
+}
+
+// LoadGoVectorIntGrammar loads the vector of integers grammar for Go as the target language.
+// The "tuple" linking function renders a model returning [][]int.
+func LoadGoVectorIntGrammar() (*Grammar, error) {
+	return loadBundled("go.VectorInt.00.default.grm.xml")
+}
+
+// LoadPythonMathGrammar loads the floating-point math grammar for Python as the target language.
+func LoadPythonMathGrammar() (*Grammar, error) {
+	return loadBundled("python.Math.00.default.grm.xml")
+}
+
+// LoadPythonBooleanAllGatesGrammar loads the general boolean grammar for Python as the target language.
+func LoadPythonBooleanAllGatesGrammar() (*Grammar, error) {
+	return loadBundled("python.Boolean.01.AllGates.grm.xml")
+}
+
+// LoadCMathGrammar loads the floating-point math grammar for C as the target language.
+func LoadCMathGrammar() (*Grammar, error) {
+	return loadBundled("c.Math.00.default.grm.xml")
+}
+
+// LoadCBooleanAllGatesGrammar loads the general boolean grammar for C as the target language.
+func LoadCBooleanAllGatesGrammar() (*Grammar, error) {
+	return loadBundled("c.Boolean.01.AllGates.grm.xml")
+}
+
+// LoadJavaScriptMathGrammar loads the floating-point math grammar for JavaScript as the target language.
+func LoadJavaScriptMathGrammar() (*Grammar, error) {
+	return loadBundled("javascript.Math.00.default.grm.xml")
+}
+
+// LoadJavaScriptBooleanAllGatesGrammar loads the general boolean grammar for JavaScript as the target language.
+func LoadJavaScriptBooleanAllGatesGrammar() (*Grammar, error) {
+	return loadBundled("javascript.Boolean.01.AllGates.grm.xml")
+}
+
+// LoadJavaMathGrammar loads the floating-point math grammar for Java as the target language.
+func LoadJavaMathGrammar() (*Grammar, error) {
+	return loadBundled("java.Math.00.default.grm.xml")
+}
+
+// LoadJavaBooleanAllGatesGrammar loads the general boolean grammar for Java as the target language.
+func LoadJavaBooleanAllGatesGrammar() (*Grammar, error) {
+	return loadBundled("java.Boolean.01.AllGates.grm.xml")
 }
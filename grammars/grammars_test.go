@@ -0,0 +1,197 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package grammars
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gmlewis/gep/v2/functions"
+	in "github.com/gmlewis/gep/v2/functions/int_nodes"
+	vin "github.com/gmlewis/gep/v2/functions/vector_int_nodes"
+)
+
+func TestLoadBundledGrammars(t *testing.T) {
+	loaders := map[string]struct {
+		load func() (*Grammar, error)
+		want string
+	}{
+		"Math":               {LoadGoMathGrammar, "Go"},
+		"AllGates":           {LoadGoBooleanAllGatesGrammar, "Go"},
+		"NotAndOrGates":      {LoadGoBooleanNotAndOrGatesGrammar, "Go"},
+		"NandGates":          {LoadGoBooleanNandGatesGrammar, "Go"},
+		"NorGates":           {LoadGoBooleanNorGatesGrammar, "Go"},
+		"MuxSystem":          {LoadGoBooleanMuxSystemGrammar, "Go"},
+		"ReedMullerSystem":   {LoadGoReedMullerSystemGrammar, "Go"},
+		"Int":                {LoadGoIntGrammar, "Go"},
+		"VectorInt":          {LoadGoVectorIntGrammar, "Go"},
+		"PythonMath":         {LoadPythonMathGrammar, "Python"},
+		"PythonAllGates":     {LoadPythonBooleanAllGatesGrammar, "Python"},
+		"CMath":              {LoadCMathGrammar, "C"},
+		"CAllGates":          {LoadCBooleanAllGatesGrammar, "C"},
+		"JavaScriptMath":     {LoadJavaScriptMathGrammar, "JavaScript"},
+		"JavaScriptAllGates": {LoadJavaScriptBooleanAllGatesGrammar, "JavaScript"},
+		"JavaMath":           {LoadJavaMathGrammar, "Java"},
+		"JavaAllGates":       {LoadJavaBooleanAllGatesGrammar, "Java"},
+	}
+
+	for name, tt := range loaders {
+		t.Run(name, func(t *testing.T) {
+			g, err := tt.load()
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if g.Name != tt.want || len(g.Functions.FuncMap) == 0 {
+				t.Errorf("got grammar %q with %v functions, want %q", g.Name, len(g.Functions.FuncMap), tt.want)
+			}
+		})
+	}
+
+	names, err := fs.Glob(bundled, "*.grm.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(names), len(loaders); got != want {
+		t.Errorf("bundled %v grammars, want %v", got, want)
+	}
+}
+
+func TestTranslatedGrammars(t *testing.T) {
+	tests := []struct {
+		name string
+		src  func() (*Grammar, error)
+		load func() (*Grammar, error)
+	}{
+		{"PythonMath", LoadGoMathGrammar, LoadPythonMathGrammar},
+		{"PythonAllGates", LoadGoBooleanAllGatesGrammar, LoadPythonBooleanAllGatesGrammar},
+		{"CMath", LoadGoMathGrammar, LoadCMathGrammar},
+		{"CAllGates", LoadGoBooleanAllGatesGrammar, LoadCBooleanAllGatesGrammar},
+		{"JavaScriptMath", LoadGoMathGrammar, LoadJavaScriptMathGrammar},
+		{"JavaScriptAllGates", LoadGoBooleanAllGatesGrammar, LoadJavaScriptBooleanAllGatesGrammar},
+		{"JavaMath", LoadGoMathGrammar, LoadJavaMathGrammar},
+		{"JavaAllGates", LoadGoBooleanAllGatesGrammar, LoadJavaBooleanAllGatesGrammar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := tt.src()
+			if err != nil {
+				t.Fatal(err)
+			}
+			g, err := tt.load()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for sym := range src.Functions.FuncMap {
+				if _, ok := g.Functions.FuncMap[sym]; !ok {
+					t.Errorf("missing function %q", sym)
+				}
+			}
+			for sym := range src.Helpers.HelperMap {
+				if _, ok := g.Helpers.HelperMap[sym]; !ok {
+					t.Errorf("missing helper %q", sym)
+				}
+			}
+			if got, want := len(g.LinkingFunctions.LinkingFunctions), len(src.LinkingFunctions.LinkingFunctions); got != want {
+				t.Errorf("got %v linking functions, want %v", got, want)
+			}
+			for _, f := range g.Functions.Functions {
+				if strings.Contains(f.Chardata, "math.") && g.Name != "Python" {
+					t.Errorf("function %q was not translated: %v", f.SymbolName, f.Chardata)
+				}
+			}
+		})
+	}
+}
+
+const customGrammar = `<?xml version="1.0" standalone="no"?>
+<grammar name="Custom" version="5" ext="cst" type="math">
+  <functions count="1">
+    <function idx="0" symbol="+" terminals="2" uniontype="{tempvarname} {symbol}= {member}">(x0+x1)</function>
+  </functions>
+  <helpers count="1">
+    <helper replaces="inc">inc(x)</helper>
+  </helpers>
+</grammar>
+`
+
+func TestGeneratedGrammars(t *testing.T) {
+	tests := []struct {
+		name  string
+		load  func() (*Grammar, error)
+		funcs functions.FuncMap
+	}{
+		{"Int", LoadGoIntGrammar, in.Int},
+		{"VectorInt", LoadGoVectorIntGrammar, vin.VectorIntFuncs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := tt.load()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for sym, f := range tt.funcs {
+				gf, ok := g.Functions.FuncMap[sym]
+				if !ok {
+					t.Errorf("missing function %q", sym)
+					continue
+				}
+				if got, want := gf.Terminals(), f.Terminals(); got != want {
+					t.Errorf("function %q has %v terminals, want %v", sym, got, want)
+				}
+			}
+			if _, ok := g.Functions.FuncMap["tuple"]; !ok {
+				t.Error("missing tuple linking function")
+			}
+			for _, h := range g.Helpers.Helpers {
+				if strings.Contains(h.Chardata, "math.") {
+					t.Errorf("helper %q needs the math package: %v", h.Replaces, h.Chardata)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadGrammar(t *testing.T) {
+	g, err := LoadGrammar(strings.NewReader(customGrammar))
+	if err != nil {
+		t.Fatalf("LoadGrammar: %v", err)
+	}
+	if g.Name != "Custom" {
+		t.Errorf("Name = %q, want Custom", g.Name)
+	}
+	if _, ok := g.Functions.FuncMap["+"]; !ok {
+		t.Error("FuncMap is missing +")
+	}
+	if got, want := g.Helpers.HelperMap["inc"], "inc(x)"; got != want {
+		t.Errorf("HelperMap[inc] = %q, want %q", got, want)
+	}
+
+	if _, err := LoadGrammar(strings.NewReader("<grammar")); err == nil {
+		t.Error("LoadGrammar: expected error for invalid XML")
+	}
+}
+
+func TestLoadGrammarFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"custom.grm.xml": &fstest.MapFile{Data: []byte(customGrammar)},
+		"broken.grm.xml": &fstest.MapFile{Data: []byte("<grammar")},
+	}
+
+	if _, err := LoadGrammarFS(fsys, "custom.grm.xml"); err != nil {
+		t.Errorf("LoadGrammarFS: %v", err)
+	}
+	if _, err := LoadGrammarFS(fsys, "missing.grm.xml"); err == nil {
+		t.Error("LoadGrammarFS: expected error for missing file")
+	}
+	if _, err := LoadGrammarFS(fsys, "broken.grm.xml"); err == nil || !strings.Contains(err.Error(), "broken.grm.xml") {
+		t.Errorf("LoadGrammarFS = %v, want error naming the file", err)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package grammars
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gmlewis/gep/v2/functions"
+)
+
+// IssueKind is the kind of inconsistency found by Validate.
+type IssueKind int
+
+const (
+	// CountMismatch means that a count attribute (such as <functions count="318">)
+	// does not match the number of elements.
+	CountMismatch IssueKind = iota
+	// DuplicateFunction means that a function symbol is defined more than once.
+	DuplicateFunction
+	// MissingFunction means that a function of the function map is not in the grammar.
+	MissingFunction
+	// TerminalMismatch means that the grammar and the function map disagree
+	// on the number of terminals of a function.
+	TerminalMismatch
+	// ArgumentMismatch means that a function refers to an argument such as
+	// x2 that is beyond its number of terminals.
+	ArgumentMismatch
+	// MissingHelper means that a gep* function called by a function or
+	// helper is not defined by its helper or by the basic functions.
+	MissingHelper
+)
+
+var issueKinds = map[IssueKind]string{
+	CountMismatch:     "count mismatch",
+	DuplicateFunction: "duplicate function",
+	MissingFunction:   "missing function",
+	TerminalMismatch:  "terminal mismatch",
+	ArgumentMismatch:  "argument mismatch",
+	MissingHelper:     "missing helper",
+}
+
+func (k IssueKind) String() string {
+	if s, ok := issueKinds[k]; ok {
+		return s
+	}
+	return fmt.Sprintf("IssueKind(%d)", int(k))
+}
+
+// Issue is a single inconsistency found by Validate.
+type Issue struct {
+	Kind IssueKind
+	// Symbol is the function symbol (or section name for CountMismatch).
+	Symbol string
+	// Detail describes the inconsistency.
+	Detail string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%v %q: %v", i.Kind, i.Symbol, i.Detail)
+}
+
+// Report is the result of validating a grammar against a function map.
+type Report struct {
+	// Issues lists the inconsistencies, sorted by kind and symbol.
+	Issues []Issue
+	// Unknown lists the symbols of the grammar functions that are not in
+	// the function map. They are not issues since a grammar (such as the
+	// GeneXproTools math grammar) may render more functions than the models use.
+	Unknown []string
+}
+
+// OK reports whether the grammar is consistent with the function map.
+func (r *Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Err returns an error listing all the issues, or nil if there are none.
+func (r *Report) Err() error {
+	if r.OK() {
+		return nil
+	}
+	lines := make([]string, len(r.Issues))
+	for i, issue := range r.Issues {
+		lines[i] = issue.String()
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
+
+func (r *Report) add(kind IssueKind, symbol, format string, args ...any) {
+	r.Issues = append(r.Issues, Issue{Kind: kind, Symbol: symbol, Detail: fmt.Sprintf(format, args...)})
+}
+
+var (
+	argRE  = regexp.MustCompile(`\bx(\d+)\b`)
+	callRE = regexp.MustCompile(`\b(gep\w+)\s*\(`)
+	// declRE matches the first line of a function definition in any of the
+	// bundled languages, such as "func gepMod(x, y float64) float64 {" or "def gepMod(x, y):".
+	declRE = regexp.MustCompile(`^\s*(\w+\s+)*(gep\w+)\s*\(.*[{:]\s*$`)
+)
+
+// Validate checks that the grammar is consistent with the function map
+// used by the models, so that any gene can be rendered, and that every
+// gep* function called in the generated code is defined.
+func Validate(g *Grammar, funcMap functions.FuncMap) *Report {
+	r := &Report{}
+	counts := []struct {
+		section    string
+		count, got int
+	}{
+		{"functions", g.Functions.Count, len(g.Functions.Functions)},
+		{"helpers", g.Helpers.Count, len(g.Helpers.Helpers)},
+		{"linkingFunctions", g.LinkingFunctions.Count, len(g.LinkingFunctions.LinkingFunctions)},
+		{"basicFunctions", g.BasicFunctions.Count, len(g.BasicFunctions.BasicFunctions)},
+	}
+	for _, c := range counts {
+		if c.count != c.got {
+			r.add(CountMismatch, c.section, "count=%v, but there are %v", c.count, c.got)
+		}
+	}
+
+	var basic []string
+	for _, f := range g.BasicFunctions.BasicFunctions {
+		basic = append(basic, f.Chardata)
+	}
+	linking := map[string]string{}
+	for _, f := range g.LinkingFunctions.LinkingFunctions {
+		linking[f.Replaces] = f.Chardata
+	}
+
+	seen := map[string]bool{}
+	for _, f := range g.Functions.Functions {
+		sym := f.SymbolName
+		if seen[sym] {
+			r.add(DuplicateFunction, sym, "defined more than once")
+			continue
+		}
+		seen[sym] = true
+
+		if fn, ok := funcMap[sym]; !ok {
+			r.Unknown = append(r.Unknown, sym)
+		} else if fn.Terminals() != f.TerminalCount {
+			r.add(TerminalMismatch, sym, "terminals=%v, but the function map has %v", f.TerminalCount, fn.Terminals())
+		}
+
+		for _, m := range argRE.FindAllStringSubmatch(f.Chardata, -1) {
+			if n, _ := strconv.Atoi(m[1]); n >= f.TerminalCount {
+				r.add(ArgumentMismatch, sym, "%v is beyond terminals=%v", m[0], f.TerminalCount)
+				break
+			}
+		}
+
+		helper, hasHelper := g.Helpers.HelperMap[sym]
+		available := append([]string{helper}, basic...)
+		for _, name := range undefined(f.Chardata, available) {
+			r.add(MissingHelper, sym, "%v is not defined", name)
+		}
+		for _, name := range undefined(f.Uniontype, append(available, linking[sym])) {
+			r.add(MissingHelper, sym, "%v (in the uniontype) is not defined", name)
+		}
+		if hasHelper {
+			for _, name := range undefined(helper, available) {
+				r.add(MissingHelper, sym, "%v (called by its helper) is not defined", name)
+			}
+		}
+	}
+
+	for sym := range funcMap {
+		if !seen[sym] {
+			r.add(MissingFunction, sym, "not in the grammar")
+		}
+	}
+
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		a, b := r.Issues[i], r.Issues[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Symbol < b.Symbol
+	})
+	sort.Strings(r.Unknown)
+	return r
+}
+
+// undefined returns the gep* functions called in s that are not defined by any of the sources.
+func undefined(s string, sources []string) []string {
+	defined := map[string]bool{}
+	for _, src := range sources {
+		for _, line := range strings.Split(strings.NewReplacer("{CRLF}", "\n", "{TAB}", "\t").Replace(src), "\n") {
+			if m := declRE.FindStringSubmatch(line); m != nil && !isKeyword(m[1]) {
+				defined[m[2]] = true
+			}
+		}
+	}
+
+	var result []string
+	for _, m := range callRE.FindAllStringSubmatch(s, -1) {
+		if name := m[1]; !defined[name] {
+			defined[name] = true // report it once
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// isKeyword reports whether the word preceding a call is a statement
+// keyword, so that the line calls a function rather than defining it.
+func isKeyword(word string) bool {
+	switch strings.TrimSpace(word) {
+	case "if", "elif", "while", "for", "return":
+		return true
+	}
+	return false
+}
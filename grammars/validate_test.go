@@ -0,0 +1,114 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package grammars
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+	bn "github.com/gmlewis/gep/v2/functions/bool_nodes"
+	in "github.com/gmlewis/gep/v2/functions/int_nodes"
+	mn "github.com/gmlewis/gep/v2/functions/math_nodes"
+	vin "github.com/gmlewis/gep/v2/functions/vector_int_nodes"
+)
+
+func TestValidateBundledGrammars(t *testing.T) {
+	tests := []struct {
+		name  string
+		load  func() (*Grammar, error)
+		funcs functions.FuncMap
+	}{
+		{"Math", LoadGoMathGrammar, mn.Math},
+		{"AllGates", LoadGoBooleanAllGatesGrammar, bn.BoolAllGates},
+		{"NotAndOrGates", LoadGoBooleanNotAndOrGatesGrammar, bn.BoolNotAndOrOnly},
+		{"NandGates", LoadGoBooleanNandGatesGrammar, bn.BoolNandOnly},
+		{"NorGates", LoadGoBooleanNorGatesGrammar, bn.BoolNorOnly},
+		{"MuxSystem", LoadGoBooleanMuxSystemGrammar, bn.BoolMuxSystem},
+		{"ReedMullerSystem", LoadGoReedMullerSystemGrammar, bn.BoolAllGates},
+		{"Int", LoadGoIntGrammar, in.Int},
+		{"VectorInt", LoadGoVectorIntGrammar, vin.VectorIntFuncs},
+		{"PythonMath", LoadPythonMathGrammar, mn.Math},
+		{"PythonAllGates", LoadPythonBooleanAllGatesGrammar, bn.BoolAllGates},
+		{"CMath", LoadCMathGrammar, mn.Math},
+		{"CAllGates", LoadCBooleanAllGatesGrammar, bn.BoolAllGates},
+		{"JavaScriptMath", LoadJavaScriptMathGrammar, mn.Math},
+		{"JavaScriptAllGates", LoadJavaScriptBooleanAllGatesGrammar, bn.BoolAllGates},
+		{"JavaMath", LoadJavaMathGrammar, mn.Math},
+		{"JavaAllGates", LoadJavaBooleanAllGatesGrammar, bn.BoolAllGates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := tt.load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := Validate(g, tt.funcs)
+			if err := r.Err(); err != nil {
+				t.Errorf("Validate:\n%v", err)
+			}
+			if got, want := len(r.Unknown), len(g.Functions.Functions)-len(tt.funcs); got != want {
+				t.Errorf("Validate: %v unknown functions, want %v", got, want)
+			}
+		})
+	}
+}
+
+const brokenGrammar = `<?xml version="1.0" standalone="no"?>
+<grammar name="Broken" version="5" ext="go" type="math">
+  <functions count="3">
+    <function idx="0" symbol="+" terminals="2" uniontype="{tempvarname} = gepAdd({tempvarname}, {member})">(x0+x1)</function>
+    <function idx="1" symbol="-" terminals="1">(x0-x1)</function>
+    <function idx="2" symbol="Mod" terminals="2">gepMod(x0, x1)</function>
+    <function idx="2" symbol="Mod" terminals="2">gepMod(x0, x1)</function>
+  </functions>
+  <linkingFunctions count="1">
+    <linkingFunction replaces="+">func gepAdd(x, y float64) float64 {{CRLF}{TAB}return x + y{CRLF}}</linkingFunction>
+  </linkingFunctions>
+  <helpers count="1">
+    <helper replaces="Mod">func gepMod(x, y float64) float64 {{CRLF}{TAB}return gepFrac(x/y){CRLF}}</helper>
+  </helpers>
+</grammar>
+`
+
+func TestValidate(t *testing.T) {
+	g, err := LoadGrammar(strings.NewReader(brokenGrammar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Validate(g, functions.FuncMap{
+		"+":   mn.Math["+"],
+		"-":   mn.Math["-"],
+		"*":   mn.Math["*"],
+		"Mod": mn.Math["Mod"],
+	})
+	if r.OK() {
+		t.Fatal("Validate: OK, want issues")
+	}
+
+	want := []Issue{
+		{Kind: CountMismatch, Symbol: "functions", Detail: "count=3, but there are 4"},
+		{Kind: DuplicateFunction, Symbol: "Mod", Detail: "defined more than once"},
+		{Kind: MissingFunction, Symbol: "*", Detail: "not in the grammar"},
+		{Kind: TerminalMismatch, Symbol: "-", Detail: "terminals=1, but the function map has 2"},
+		{Kind: ArgumentMismatch, Symbol: "-", Detail: "x1 is beyond terminals=1"},
+		{Kind: MissingHelper, Symbol: "Mod", Detail: "gepFrac (called by its helper) is not defined"},
+	}
+	if len(r.Issues) != len(want) {
+		t.Fatalf("Validate issues =\n%v\nwant %v issues", r.Err(), len(want))
+	}
+	for i, got := range r.Issues {
+		if got != want[i] {
+			t.Errorf("issue #%v = %v, want %v", i, got, want[i])
+		}
+	}
+	if len(r.Unknown) != 0 {
+		t.Errorf("Unknown = %v, want none", r.Unknown)
+	}
+	if err := r.Err(); err == nil || !strings.Contains(err.Error(), `missing function "*"`) {
+		t.Errorf("Err = %v, want missing function", err)
+	}
+}
@@ -0,0 +1,265 @@
+// Package acrobot implements the Acrobot environment in Go.
+// It is a port of: https://github.com/Farama-Foundation/Gymnasium/blob/main/gymnasium/envs/classic_control/acrobot.py
+//
+// # Description
+// The Acrobot environment is based on Sutton's work in "Generalization in
+// Reinforcement Learning: Successful Examples Using Sparse Coarse Coding"
+// and Sutton and Barto's book. The system consists of two links connected
+// linearly to form a chain, with one end of the chain fixed. The joint
+// between the two links is actuated. The goal is to apply torques on the
+// actuated joint to swing the free end of the linear chain above a given
+// height while starting from the initial state of hanging downwards.
+//
+// # Action Space
+// The action is discrete, deterministic, and represents the torque applied
+// on the actuated joint between the two links.
+//
+//	| Num | Action                                | Unit         |
+//	|-----|---------------------------------------|--------------|
+//	| 0   | apply -1 torque to the actuated joint | torque (N m) |
+//	| 1   | apply 0 torque to the actuated joint  | torque (N m) |
+//	| 2   | apply 1 torque to the actuated joint  | torque (N m) |
+//
+// # Observation Space
+// The observation is a Box of shape `(6,)` that provides information about
+// the two rotational joint angles as well as their angular velocities:
+//
+//	| Num | Observation                  | Min                 | Max               |
+//	|-----|------------------------------|---------------------|-------------------|
+//	| 0   | Cosine of theta1             | -1                  | 1                 |
+//	| 1   | Sine of theta1               | -1                  | 1                 |
+//	| 2   | Cosine of theta2             | -1                  | 1                 |
+//	| 3   | Sine of theta2               | -1                  | 1                 |
+//	| 4   | Angular velocity of theta1   | ~ -12.567 (-4 * pi) | ~ 12.567 (4 * pi) |
+//	| 5   | Angular velocity of theta2   | ~ -28.274 (-9 * pi) | ~ 28.274 (9 * pi) |
+//
+// where theta1 is the angle of the first joint, where an angle of 0
+// indicates the first link is pointing directly downwards, and theta2 is
+// relative to the angle of the first link. An angle of 0 corresponds to
+// having the same angle between the two links.
+//
+// The observation is returned as `[]float64` of length 6.
+//
+// # Rewards
+// The goal is to have the free end reach a designated target height in as
+// few steps as possible, and as such all steps that do not reach the goal
+// incur a reward of -1. Achieving the target height results in termination
+// with a reward of 0. The reward threshold is -100.
+//
+// # Starting State
+// Each parameter in the underlying state (theta1, theta2, and the two
+// angular velocities) is initialized uniformly between -0.1 and 0.1.
+// This means both links are pointing downwards with some initial stochasticity.
+//
+// # Episode End
+// The episode ends if one of the following occurs:
+//
+// 1. Termination: The free end reaches the target height, which is
+// constructed as: `-cos(theta1) - cos(theta2 + theta1) > 1.0`
+// 2. Truncation: Episode length is greater than 500
+//
+// # Arguments
+//
+// ```
+// gymnasium.Make("Acrobot-v1")
+// ```
+//
+// The dynamics follow the "book" version of Sutton and Barto, integrated
+// with a fourth-order Runge-Kutta method over each 0.2s step.
+package acrobot
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+const (
+	dt = 0.2
+
+	linkLength1 = 1.0 // [m]
+	linkMass1   = 1.0 // [kg] mass of link 1
+	linkMass2   = 1.0 // [kg] mass of link 2
+	linkCOMPos1 = 0.5 // [m] position of the center of mass of link 1
+	linkCOMPos2 = 0.5 // [m] position of the center of mass of link 2
+	linkMOI     = 1.0 // moments of inertia for both links
+
+	maxVel1 = 4 * math.Pi
+	maxVel2 = 9 * math.Pi
+
+	// MaxEpisodeSteps is the number of steps after which gymnasium.Make
+	// truncates an episode by default.
+	MaxEpisodeSteps = 500
+)
+
+var availTorque = []float64{-1, 0, 1}
+
+// Environment represents an Acrobot environment.
+type Environment struct {
+	// state is (theta1, theta2, dtheta1, dtheta2).
+	state [4]float64
+	rng   *rand.Rand
+}
+
+// New returns a new Acrobot environment.
+func New() *Environment {
+	return &Environment{rng: rand.New(rand.NewSource(rand.Int63()))}
+}
+
+func (e *Environment) ActionSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: 3}, nil
+}
+
+func (e *Environment) ObservationSpace() (*common.Space, error) {
+	high := []float64{1, 1, 1, 1, maxVel1, maxVel2}
+	low := make([]float64, len(high))
+	for i, v := range high {
+		low[i] = -v
+	}
+	return &common.Space{Type: "Box", Low: low, High: high, Shape: []int{6}}, nil
+}
+
+func (e *Environment) SampleAction(action any) error {
+	switch v := action.(type) {
+	case *int:
+		*v = e.rng.Intn(3)
+	default:
+		return fmt.Errorf("unsupported SampleAction type %T", action)
+	}
+	return nil
+}
+
+func (e *Environment) Close() error { return nil }
+
+// Step performs the provided action and returns the next observation,
+// the reward for this action, and whether this episode is terminated.
+// If the action is invalid, truncated will be true and info is the error.
+func (e *Environment) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	actionInt, ok := action.(int)
+	if !ok {
+		info = fmt.Errorf("error: Acrobot: invalid action type %T; must be int", action)
+		return e.getObs(), reward, terminated, true, info
+	}
+
+	if actionInt < 0 || actionInt > 2 {
+		info = fmt.Errorf("error: Acrobot: invalid action=%v; must be 0 (-1 torque), 1 (no torque) or 2 (+1 torque)", actionInt)
+		return e.getObs(), reward, terminated, true, info
+	}
+
+	s := [5]float64{e.state[0], e.state[1], e.state[2], e.state[3], availTorque[actionInt]}
+	ns := rk4(s, dt)
+	ns[0] = wrap(ns[0], -math.Pi, math.Pi)
+	ns[1] = wrap(ns[1], -math.Pi, math.Pi)
+	ns[2] = bound(ns[2], -maxVel1, maxVel1)
+	ns[3] = bound(ns[3], -maxVel2, maxVel2)
+	e.state = [4]float64{ns[0], ns[1], ns[2], ns[3]}
+
+	terminated = e.terminal()
+	if !terminated {
+		reward = -1
+	}
+	return e.getObs(), reward, terminated, false, nil
+}
+
+// Reset resets to a brand new episode.
+// The "low" and "high" options change the bounds of the uniformly
+// distributed initial state, which default to -0.1 and 0.1.
+func (e *Environment) Reset(opts ...common.ResetOption) (obs common.Obs, info any) {
+	o := common.NewResetOptions(opts...)
+	if o.Seed != nil {
+		e.rng.Seed(*o.Seed)
+	}
+	low, high := o.Float64("low", -0.1), o.Float64("high", 0.1)
+	for i := range e.state {
+		e.state[i] = low + (high-low)*e.rng.Float64()
+	}
+	return e.getObs(), nil
+}
+
+func (e *Environment) terminal() bool {
+	s := e.state
+	return -math.Cos(s[0])-math.Cos(s[1]+s[0]) > 1
+}
+
+type obsT [6]float64
+
+var _ common.Obs = obsT{}
+
+func (o obsT) Unmarshal(dst any) error {
+	switch v := dst.(type) {
+	case *[]float64:
+		*v = append((*v)[:0], o[:]...)
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
+
+func (e *Environment) getObs() common.Obs {
+	s := e.state
+	return obsT{math.Cos(s[0]), math.Sin(s[0]), math.Cos(s[1]), math.Sin(s[1]), s[2], s[3]}
+}
+
+// dsdt returns the derivatives of the augmented state (the state and the torque).
+func dsdt(s [5]float64) [5]float64 {
+	const (
+		m1  = linkMass1
+		m2  = linkMass2
+		l1  = linkLength1
+		lc1 = linkCOMPos1
+		lc2 = linkCOMPos2
+		i1  = linkMOI
+		i2  = linkMOI
+		g   = 9.8
+	)
+	theta1, theta2, dtheta1, dtheta2, a := s[0], s[1], s[2], s[3], s[4]
+	d1 := m1*lc1*lc1 + m2*(l1*l1+lc2*lc2+2*l1*lc2*math.Cos(theta2)) + i1 + i2
+	d2 := m2*(lc2*lc2+l1*lc2*math.Cos(theta2)) + i2
+	phi2 := m2 * lc2 * g * math.Cos(theta1+theta2-math.Pi/2)
+	phi1 := -m2*l1*lc2*dtheta2*dtheta2*math.Sin(theta2) -
+		2*m2*l1*lc2*dtheta2*dtheta1*math.Sin(theta2) +
+		(m1*lc1+m2*l1)*g*math.Cos(theta1-math.Pi/2) + phi2
+	// The "book" dynamics.
+	ddtheta2 := (a + d2/d1*phi1 - m2*l1*lc2*dtheta1*dtheta1*math.Sin(theta2) - phi2) / (m2*lc2*lc2 + i2 - d2*d2/d1)
+	ddtheta1 := -(d2*ddtheta2 + phi1) / d1
+	return [5]float64{dtheta1, dtheta2, ddtheta1, ddtheta2, 0}
+}
+
+// rk4 integrates the augmented state over one step of duration h
+// with the fourth-order Runge-Kutta method.
+func rk4(y0 [5]float64, h float64) [5]float64 {
+	h2 := h / 2
+	add := func(y, k [5]float64, h float64) (r [5]float64) {
+		for i := range y {
+			r[i] = y[i] + h*k[i]
+		}
+		return r
+	}
+	k1 := dsdt(y0)
+	k2 := dsdt(add(y0, k1, h2))
+	k3 := dsdt(add(y0, k2, h2))
+	k4 := dsdt(add(y0, k3, h))
+	var y [5]float64
+	for i := range y {
+		y[i] = y0[i] + h/6*(k1[i]+2*k2[i]+2*k3[i]+k4[i])
+	}
+	return y
+}
+
+// wrap wraps x into the range [m, M], as for angles.
+func wrap(x, m, M float64) float64 {
+	diff := M - m
+	for x > M {
+		x -= diff
+	}
+	for x < m {
+		x += diff
+	}
+	return x
+}
+
+func bound(x, m, M float64) float64 {
+	return math.Min(math.Max(x, m), M)
+}
@@ -0,0 +1,128 @@
+package acrobot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+func checkObs(t *testing.T, obs common.Obs, want []float64) {
+	t.Helper()
+	var got []float64
+	if err := obs.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("obs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Errorf("obs = %v, want %v", got, want)
+			return
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	// Reference values from the Gymnasium Acrobot-v1 dynamics.
+	e := New()
+	e.state = [4]float64{0.05, -0.03, 0.02, 0.01}
+
+	obs, reward, terminated, truncated, info := e.Step(2)
+	if reward != -1 || terminated || truncated || info != nil {
+		t.Errorf("Step(2) = (%v, %v, %v, %v), want (-1, false, false, nil)", reward, terminated, truncated, info)
+	}
+	checkObs(t, obs, []float64{0.9994232696918266, 0.03395773841848709, 0.9999016042257141, 0.014027896023404499, -0.175464650251537, 0.4197156022652691})
+
+	obs, _, _, _, _ = e.Step(0)
+	checkObs(t, obs, []float64{0.9999474035213403, 0.010256226934397235, 0.998121475729519, 0.06126597495777821, -0.05558414607650376, 0.0417371113112644})
+}
+
+func TestTermination(t *testing.T) {
+	e := New()
+	e.Reset()
+	e.state = [4]float64{0.05, 0, 0, 0}
+
+	var steps int
+	for {
+		steps++
+		action := 0
+		if e.state[3] >= 0 {
+			action = 2
+		}
+		_, reward, terminated, truncated, _ := e.Step(action)
+		if truncated {
+			t.Fatalf("truncated after %v steps", steps)
+		}
+		if terminated {
+			if reward != 0 {
+				t.Errorf("terminal reward = %v, want 0", reward)
+			}
+			break
+		}
+	}
+	if steps != 74 {
+		t.Errorf("terminated after %v steps, want 74", steps)
+	}
+}
+
+func TestReset(t *testing.T) {
+	e := New()
+	e.Reset()
+	for i, v := range e.state {
+		if v < -0.1 || v > 0.1 {
+			t.Errorf("state[%v] = %v, want in [-0.1, 0.1]", i, v)
+		}
+	}
+
+	opts := []common.ResetOption{common.WithSeed(5), common.WithOptions(map[string]any{"low": -0.5, "high": 0.5})}
+	e.Reset(opts...)
+	state := e.state
+	for i, v := range state {
+		if v < -0.5 || v > 0.5 {
+			t.Errorf("state[%v] = %v, want in [-0.5, 0.5]", i, v)
+		}
+	}
+	e.Step(1)
+	e.Reset(opts...)
+	if e.state != state {
+		t.Errorf("Reset(WithSeed(5)) state = %v, want %v", e.state, state)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		x, want float64
+	}{
+		{x: 0, want: 0},
+		{x: 4, want: 4 - 2*math.Pi},
+		{x: -4, want: -4 + 2*math.Pi},
+		{x: 7, want: 7 - 2*math.Pi},
+	}
+	for _, tt := range tests {
+		if got := wrap(tt.x, -math.Pi, math.Pi); math.Abs(got-tt.want) > 1e-15 {
+			t.Errorf("wrap(%v) = %v, want %v", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestInvalidAction(t *testing.T) {
+	e := New()
+	for _, action := range []any{3, -1, 1.0} {
+		if _, _, _, truncated, info := e.Step(action); !truncated || info == nil {
+			t.Errorf("Step(%v) = (truncated=%v, info=%v), want truncated with an error", action, truncated, info)
+		}
+	}
+}
+
+func TestSpaces(t *testing.T) {
+	as, err := New().ActionSpace()
+	if err != nil || as.Type != "Discrete" || as.N != 3 {
+		t.Errorf("ActionSpace = %+v, %v", as, err)
+	}
+	os, err := New().ObservationSpace()
+	if err != nil || os.Type != "Box" || os.Low[4] != -4*math.Pi || os.High[5] != 9*math.Pi || os.Shape[0] != 6 {
+		t.Errorf("ObservationSpace = %+v, %v", os, err)
+	}
+}
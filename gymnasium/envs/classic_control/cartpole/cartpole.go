@@ -0,0 +1,226 @@
+// Package cartpole implements the CartPole environment in Go.
+// It is a port of: https://github.com/Farama-Foundation/Gymnasium/blob/main/gymnasium/envs/classic_control/cartpole.py
+//
+// This environment corresponds to the version of the cart-pole problem
+// described by Barto, Sutton, and Anderson in "Neuronlike Adaptive Elements
+// That Can Solve Difficult Learning Control Problem".
+//
+// # Description
+// A pole is attached by an un-actuated joint to a cart, which moves along a
+// frictionless track. The pendulum is placed upright on the cart and the goal
+// is to balance the pole by applying forces in the left and right direction
+// on the cart.
+//
+// # Action Space
+// The action is an int which can take values `{0, 1}` indicating the
+// direction of the fixed force the cart is pushed with.
+//
+// - 0: Push cart to the left
+// - 1: Push cart to the right
+//
+// Note that the velocity that is reduced or increased by the applied force
+// is not fixed and it depends on the angle the pole is pointing. The center
+// of gravity of the pole varies the amount of energy needed to move the cart
+// underneath it.
+//
+// # Observation Space
+// The observation is a Box of shape `(4,)` with the values corresponding
+// to the following positions and velocities:
+//
+//	| Num | Observation           | Min                 | Max               |
+//	|-----|-----------------------|---------------------|-------------------|
+//	| 0   | Cart Position         | -4.8                | 4.8               |
+//	| 1   | Cart Velocity         | -Inf                | Inf               |
+//	| 2   | Pole Angle            | ~ -0.418 rad (-24°) | ~ 0.418 rad (24°) |
+//	| 3   | Pole Angular Velocity | -Inf                | Inf               |
+//
+// While the ranges above denote the possible values for observation space
+// of each element, it is not reflective of the allowed values of the state
+// space in an unterminated episode. Particularly:
+//
+// - The cart x-position (index 0) can take values between `(-4.8, 4.8)`,
+// but the episode terminates if the cart leaves the `(-2.4, 2.4)` range.
+// - The pole angle can be observed between `(-.418, .418)` radians (or ±24°),
+// but the episode terminates if the pole angle is not in the range
+// `(-.2095, .2095)` (or ±12°).
+//
+// The observation is returned as `[]float64` of length 4.
+//
+// # Rewards
+// Since the goal is to keep the pole upright for as long as possible,
+// a reward of +1 is given for every step taken, including the termination
+// step. The threshold for rewards is 500.
+//
+// # Starting State
+// All observations are assigned a uniformly random value in `(-0.05, 0.05)`.
+//
+// # Episode End
+// The episode ends if any one of the following occurs:
+//
+// 1. Termination: Pole Angle is greater than ±12°
+// 2. Termination: Cart Position is greater than ±2.4 (center of the cart reaches the edge of the display)
+// 3. Truncation: Episode length is greater than 500
+//
+// # Arguments
+//
+// ```
+// gymnasium.Make("CartPole-v1")
+// ```
+package cartpole
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+// Physical constants of the cart-pole system.
+const (
+	gravity               = 9.8
+	massCart              = 1.0
+	massPole              = 0.1
+	totalMass             = massPole + massCart
+	length                = 0.5 // actually half the pole's length
+	poleMassLength        = massPole * length
+	forceMag              = 10.0
+	tau                   = 0.02 // seconds between state updates
+	thetaThresholdRadians = 12 * 2 * math.Pi / 360
+	xThreshold            = 2.4
+
+	// MaxEpisodeSteps is the number of steps after which gymnasium.Make
+	// truncates an episode by default.
+	MaxEpisodeSteps = 500
+)
+
+// Environment represents a CartPole environment.
+type Environment struct {
+	// state is (x, x_dot, theta, theta_dot).
+	state [4]float64
+	// stepsBeyondTerminated is -1 until the episode terminates.
+	stepsBeyondTerminated int
+	rng                   *rand.Rand
+}
+
+// New returns a new CartPole environment.
+func New() *Environment {
+	return &Environment{
+		stepsBeyondTerminated: -1,
+		rng:                   rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+func (e *Environment) ActionSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: 2}, nil
+}
+
+func (e *Environment) ObservationSpace() (*common.Space, error) {
+	high := []float64{xThreshold * 2, math.MaxFloat32, thetaThresholdRadians * 2, math.MaxFloat32}
+	low := make([]float64, len(high))
+	for i, v := range high {
+		low[i] = -v
+	}
+	return &common.Space{Type: "Box", Low: low, High: high, Shape: []int{4}}, nil
+}
+
+func (e *Environment) SampleAction(action any) error {
+	switch v := action.(type) {
+	case *int:
+		*v = e.rng.Intn(2)
+	default:
+		return fmt.Errorf("unsupported SampleAction type %T", action)
+	}
+	return nil
+}
+
+func (e *Environment) Close() error { return nil }
+
+// Step performs the provided action and returns the next observation,
+// the reward for this action, and whether this episode is terminated.
+// If the action is invalid, truncated will be true and info is the error.
+func (e *Environment) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	actionInt, ok := action.(int)
+	if !ok {
+		info = fmt.Errorf("error: CartPole: invalid action type %T; must be int", action)
+		return e.getObs(), reward, terminated, true, info
+	}
+
+	if actionInt < 0 || actionInt > 1 {
+		info = fmt.Errorf("error: CartPole: invalid action=%v; must be 0 (left) or 1 (right)", actionInt)
+		return e.getObs(), reward, terminated, true, info
+	}
+
+	x, xDot, theta, thetaDot := e.state[0], e.state[1], e.state[2], e.state[3]
+	force := -forceMag
+	if actionInt == 1 {
+		force = forceMag
+	}
+	cosTheta, sinTheta := math.Cos(theta), math.Sin(theta)
+
+	// For the interested reader:
+	// https://coneural.org/florian/papers/05_cart_pole.pdf
+	temp := (force + poleMassLength*thetaDot*thetaDot*sinTheta) / totalMass
+	thetaAcc := (gravity*sinTheta - cosTheta*temp) / (length * (4.0/3.0 - massPole*cosTheta*cosTheta/totalMass))
+	xAcc := temp - poleMassLength*thetaAcc*cosTheta/totalMass
+
+	// Euler integration.
+	x += tau * xDot
+	xDot += tau * xAcc
+	theta += tau * thetaDot
+	thetaDot += tau * thetaAcc
+	e.state = [4]float64{x, xDot, theta, thetaDot}
+
+	terminated = x < -xThreshold || x > xThreshold || theta < -thetaThresholdRadians || theta > thetaThresholdRadians
+
+	switch {
+	case !terminated:
+		reward = 1
+	case e.stepsBeyondTerminated < 0:
+		// Pole just fell!
+		e.stepsBeyondTerminated = 0
+		reward = 1
+	default:
+		if e.stepsBeyondTerminated == 0 {
+			log.Printf("You are calling Step() even though this environment has already returned terminated = true. You should always call Reset() once you receive terminated = true -- any further steps are undefined behavior.")
+		}
+		e.stepsBeyondTerminated++
+	}
+
+	return e.getObs(), reward, terminated, false, nil
+}
+
+// Reset resets to a brand new episode.
+// The "low" and "high" options change the bounds of the uniformly
+// distributed initial state, which default to -0.05 and 0.05.
+func (e *Environment) Reset(opts ...common.ResetOption) (obs common.Obs, info any) {
+	o := common.NewResetOptions(opts...)
+	if o.Seed != nil {
+		e.rng.Seed(*o.Seed)
+	}
+	low, high := o.Float64("low", -0.05), o.Float64("high", 0.05)
+	for i := range e.state {
+		e.state[i] = low + (high-low)*e.rng.Float64()
+	}
+	e.stepsBeyondTerminated = -1
+	return e.getObs(), nil
+}
+
+type obsT [4]float64
+
+var _ common.Obs = obsT{}
+
+func (o obsT) Unmarshal(dst any) error {
+	switch v := dst.(type) {
+	case *[]float64:
+		*v = append((*v)[:0], o[:]...)
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
+
+func (e *Environment) getObs() common.Obs {
+	return obsT(e.state)
+}
@@ -0,0 +1,146 @@
+package cartpole
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+func obs(t *testing.T, e *Environment) []float64 {
+	t.Helper()
+	var result []float64
+	if err := e.getObs().Unmarshal(&result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func checkObs(t *testing.T, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("obs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Errorf("obs = %v, want %v", got, want)
+			return
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	// Reference values from the Gymnasium CartPole-v1 dynamics.
+	e := New()
+	e.state = [4]float64{0.01, 0.02, 0.03, 0.04}
+
+	_, reward, terminated, truncated, info := e.Step(1)
+	if reward != 1 || terminated || truncated || info != nil {
+		t.Errorf("Step(1) = (%v, %v, %v, %v), want (1, false, false, nil)", reward, terminated, truncated, info)
+	}
+	checkObs(t, obs(t, e), []float64{0.0104, 0.21467919574755523, 0.030799999999999998, -0.2430687179600081})
+
+	e.Step(0)
+	checkObs(t, obs(t, e), []float64{0.014693583914951104, 0.019131151051807987, 0.025938625640799837, 0.0591679999394166})
+}
+
+func TestTermination(t *testing.T) {
+	e := New()
+	e.Reset()
+	e.state = [4]float64{}
+
+	var steps int
+	for {
+		steps++
+		_, reward, terminated, _, _ := e.Step(1)
+		if reward != 1 {
+			t.Fatalf("step %v: reward = %v, want 1", steps, reward)
+		}
+		if terminated {
+			break
+		}
+	}
+	if steps != 9 {
+		t.Errorf("terminated after %v steps, want 9", steps)
+	}
+	checkObs(t, obs(t, e), []float64{0.14065097203306187, 1.7603811257683097, -0.21518604988500967, -2.777886494012814})
+
+	if _, reward, terminated, _, _ := e.Step(1); reward != 0 || !terminated {
+		t.Errorf("Step after termination = (%v, %v), want (0, true)", reward, terminated)
+	}
+}
+
+func TestReset(t *testing.T) {
+	e := New()
+	e.Step(1)
+	o, info := e.Reset()
+	if info != nil {
+		t.Errorf("Reset info = %v, want nil", info)
+	}
+	var got []float64
+	if err := o.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range got {
+		if v < -0.05 || v > 0.05 {
+			t.Errorf("Reset obs = %v, want all in (-0.05, 0.05)", got)
+		}
+	}
+	if e.stepsBeyondTerminated != -1 {
+		t.Errorf("Reset did not reset the episode: stepsBeyondTerminated=%v", e.stepsBeyondTerminated)
+	}
+}
+
+func TestResetOptions(t *testing.T) {
+	e := New()
+	o, _ := e.Reset(common.WithSeed(42), common.WithOptions(map[string]any{"low": 0.1, "high": 0.2}))
+	var got []float64
+	if err := o.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range got {
+		if v < 0.1 || v > 0.2 {
+			t.Errorf("Reset obs = %v, want all in (0.1, 0.2)", got)
+		}
+	}
+
+	// The same seed results in the same initial state.
+	e.Step(1)
+	o, _ = e.Reset(common.WithSeed(42), common.WithOptions(map[string]any{"low": 0.1, "high": 0.2}))
+	var again []float64
+	if err := o.Unmarshal(&again); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, again) {
+		t.Errorf("Reset(WithSeed(42)) = %v, want %v", again, got)
+	}
+}
+
+func TestInvalidAction(t *testing.T) {
+	e := New()
+	e.Reset()
+	for _, action := range []any{2, -1, 1.0} {
+		if _, _, _, truncated, info := e.Step(action); !truncated || info == nil {
+			t.Errorf("Step(%v) = (truncated=%v, info=%v), want truncated with an error", action, truncated, info)
+		}
+	}
+}
+
+func TestSpaces(t *testing.T) {
+	e := New()
+	as, err := e.ActionSpace()
+	if err != nil || as.Type != "Discrete" || as.N != 2 {
+		t.Errorf("ActionSpace = %+v, %v", as, err)
+	}
+	os, err := e.ObservationSpace()
+	if err != nil || os.Type != "Box" || len(os.Low) != 4 || len(os.High) != 4 || os.Shape[0] != 4 {
+		t.Fatalf("ObservationSpace = %+v, %v", os, err)
+	}
+	if got, want := os.High[2], 0.41887902047863906; math.Abs(got-want) > 1e-15 {
+		t.Errorf("theta high = %v, want %v", got, want)
+	}
+	if os.Low[0] != -4.8 || os.High[1] != math.MaxFloat32 {
+		t.Errorf("ObservationSpace = %+v", os)
+	}
+}
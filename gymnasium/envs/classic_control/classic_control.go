@@ -0,0 +1,2 @@
+// Package classic_control provides pure Go implementations of classic_control Gymnasium environments.
+package classic_control
@@ -0,0 +1,109 @@
+package mountaincar
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+const (
+	minAction = -1.0
+	maxAction = 1.0
+
+	// MaxContinuousEpisodeSteps is the number of steps after which gymnasium.Make
+	// truncates a MountainCarContinuous-v0 episode by default.
+	MaxContinuousEpisodeSteps = 999
+)
+
+// ContinuousEnvironment represents a MountainCarContinuous-v0 environment.
+type ContinuousEnvironment struct {
+	goalPosition float64
+	goalVelocity float64
+	power        float64
+
+	// state is (position, velocity), stored with float32 precision
+	// like the reference implementation.
+	state [2]float64
+	rng   *rand.Rand
+}
+
+// NewContinuous returns a new MountainCarContinuous-v0 environment.
+func NewContinuous(goalVelocity float64) *ContinuousEnvironment {
+	return &ContinuousEnvironment{
+		goalPosition: 0.45,
+		goalVelocity: goalVelocity,
+		power:        0.0015,
+		rng:          rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+func (e *ContinuousEnvironment) ActionSpace() (*common.Space, error) {
+	return &common.Space{
+		Type:  "Box",
+		Low:   []float64{minAction},
+		High:  []float64{maxAction},
+		Shape: []int{1},
+	}, nil
+}
+
+func (e *ContinuousEnvironment) ObservationSpace() (*common.Space, error) {
+	return observationSpace(), nil
+}
+
+func (e *ContinuousEnvironment) SampleAction(action any) error {
+	switch v := action.(type) {
+	case *[]float64:
+		*v = append((*v)[:0], minAction+(maxAction-minAction)*e.rng.Float64())
+	default:
+		return fmt.Errorf("unsupported SampleAction type %T", action)
+	}
+	return nil
+}
+
+func (e *ContinuousEnvironment) Close() error { return nil }
+
+// Step performs the provided action (a []float64 of length 1) and returns
+// the next observation, the reward for this action, and whether this
+// episode is terminated. If the action is invalid, truncated will be true
+// and info is the error.
+func (e *ContinuousEnvironment) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	actions, ok := action.([]float64)
+	if !ok || len(actions) != 1 {
+		info = fmt.Errorf("error: MountainCarContinuous: invalid action %T(%v); must be []float64 of length 1", action, action)
+		return e.getObs(), reward, terminated, true, info
+	}
+
+	position, velocity := e.state[0], e.state[1]
+	force := clip(actions[0], minAction, maxAction)
+
+	velocity += force*e.power - gravity*math.Cos(3*position)
+	velocity = clip(velocity, -maxSpeed, maxSpeed)
+	position += velocity
+	position = clip(position, minPosition, maxPosition)
+	if position == minPosition && velocity < 0 {
+		velocity = 0
+	}
+
+	terminated = position >= e.goalPosition && velocity >= e.goalVelocity
+	if terminated {
+		reward = 100
+	}
+	reward -= actions[0] * actions[0] * 0.1
+
+	e.state = [2]float64{float64(float32(position)), float64(float32(velocity))}
+	return e.getObs(), reward, terminated, false, nil
+}
+
+// Reset resets to a brand new episode.
+// The "low" and "high" options change the bounds of the uniformly
+// distributed initial position, which default to -0.6 and -0.4.
+func (e *ContinuousEnvironment) Reset(opts ...common.ResetOption) (obs common.Obs, info any) {
+	e.state = [2]float64{float64(float32(startPosition(e.rng, opts))), 0}
+	return e.getObs(), nil
+}
+
+func (e *ContinuousEnvironment) getObs() common.Obs {
+	return obsT(e.state)
+}
@@ -0,0 +1,218 @@
+// Package mountaincar implements the MountainCar environments in Go.
+// They are ports of: https://github.com/Farama-Foundation/Gymnasium/blob/main/gymnasium/envs/classic_control/mountain_car.py
+// and: https://github.com/Farama-Foundation/Gymnasium/blob/main/gymnasium/envs/classic_control/continuous_mountain_car.py
+//
+// The Mountain Car MDP is a deterministic MDP that consists of a car placed
+// stochastically at the bottom of a sinusoidal valley, with the only possible
+// actions being the accelerations that can be applied to the car in either
+// direction. The goal of the MDP is to strategically accelerate the car to
+// reach the goal state on top of the right hill. There are two versions of
+// the mountain car domain in gymnasium: one with discrete actions
+// (MountainCar-v0, see New) and one with continuous actions
+// (MountainCarContinuous-v0, see NewContinuous).
+//
+// This MDP first appeared in Andrew Moore's PhD Thesis (1990).
+//
+// # Observation Space
+// The observation is a Box of shape `(2,)` where the elements correspond to the following:
+//
+//	| Num | Observation                          | Min   | Max  |
+//	|-----|--------------------------------------|-------|------|
+//	| 0   | position of the car along the x-axis | -1.2  | 0.6  |
+//	| 1   | velocity of the car                  | -0.07 | 0.07 |
+//
+// The observation is returned as `[]float64` of length 2.
+//
+// # Action Space
+// MountainCar-v0 has 3 deterministic actions (an int):
+//
+// - 0: Accelerate to the left
+// - 1: Don't accelerate
+// - 2: Accelerate to the right
+//
+// MountainCarContinuous-v0 has a Box action of shape `(1,)` (a `[]float64`)
+// representing the directional force applied on the car. The action is
+// clipped in the range `[-1, 1]` and multiplied by a power of 0.0015.
+//
+// # Transition Dynamics
+// Given an action, the mountain car follows the following transition dynamics:
+//
+//	velocity_{t+1} = velocity_t + (action - 1) * force - cos(3 * position_t) * gravity
+//	position_{t+1} = position_t + velocity_{t+1}
+//
+// where force = 0.001 and gravity = 0.0025 (for the continuous version,
+// `(action - 1) * force` is replaced by `action * power`). The collisions
+// at either end are inelastic with the velocity set to 0 upon collision
+// with the wall. The position is clipped to the range `[-1.2, 0.6]` and
+// velocity is clipped to the range `[-0.07, 0.07]`.
+//
+// # Reward
+// MountainCar-v0: the goal is to reach the flag placed on top of the right
+// hill as quickly as possible, as such the agent is penalised with a reward
+// of -1 for each timestep.
+//
+// MountainCarContinuous-v0: a negative reward of `-0.1 * action^2` is received
+// at each timestep to penalise for taking actions of large magnitude. If the
+// mountain car reaches the goal then a positive reward of +100 is added to
+// the negative reward for that timestep.
+//
+// # Starting State
+// The position of the car is assigned a uniform random value in `[-0.6, -0.4]`.
+// The starting velocity of the car is always assigned to 0.
+//
+// # Episode End
+// The episode ends if either of the following happens:
+//
+// 1. Termination: The position of the car is greater than or equal to 0.5
+// (0.45 for the continuous version, the goal position on top of the right hill)
+// and its velocity is greater than or equal to goal_velocity.
+// 2. Truncation: The length of the episode is 200 (999 for the continuous version).
+//
+// # Arguments
+//
+// ```
+// gymnasium.Make("MountainCar-v0")
+// gymnasium.Make("MountainCarContinuous-v0")
+// ```
+//
+// `goal_velocity=0`: the minimum velocity of the car at the goal.
+package mountaincar
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+const (
+	minPosition = -1.2
+	maxPosition = 0.6
+	maxSpeed    = 0.07
+	gravity     = 0.0025
+
+	// MaxEpisodeSteps is the number of steps after which gymnasium.Make
+	// truncates a MountainCar-v0 episode by default.
+	MaxEpisodeSteps = 200
+)
+
+// Environment represents a MountainCar-v0 environment.
+type Environment struct {
+	goalPosition float64
+	goalVelocity float64
+	force        float64
+
+	// state is (position, velocity).
+	state [2]float64
+	rng   *rand.Rand
+}
+
+// New returns a new MountainCar-v0 environment.
+func New(goalVelocity float64) *Environment {
+	return &Environment{
+		goalPosition: 0.5,
+		goalVelocity: goalVelocity,
+		force:        0.001,
+		rng:          rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+func (e *Environment) ActionSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: 3}, nil
+}
+
+func (e *Environment) ObservationSpace() (*common.Space, error) {
+	return observationSpace(), nil
+}
+
+func observationSpace() *common.Space {
+	return &common.Space{
+		Type:  "Box",
+		Low:   []float64{minPosition, -maxSpeed},
+		High:  []float64{maxPosition, maxSpeed},
+		Shape: []int{2},
+	}
+}
+
+func (e *Environment) SampleAction(action any) error {
+	switch v := action.(type) {
+	case *int:
+		*v = e.rng.Intn(3)
+	default:
+		return fmt.Errorf("unsupported SampleAction type %T", action)
+	}
+	return nil
+}
+
+func (e *Environment) Close() error { return nil }
+
+// Step performs the provided action and returns the next observation,
+// the reward for this action, and whether this episode is terminated.
+// If the action is invalid, truncated will be true and info is the error.
+func (e *Environment) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	actionInt, ok := action.(int)
+	if !ok {
+		info = fmt.Errorf("error: MountainCar: invalid action type %T; must be int", action)
+		return e.getObs(), reward, terminated, true, info
+	}
+
+	if actionInt < 0 || actionInt > 2 {
+		info = fmt.Errorf("error: MountainCar: invalid action=%v; must be 0 (left), 1 (none) or 2 (right)", actionInt)
+		return e.getObs(), reward, terminated, true, info
+	}
+
+	position, velocity := e.state[0], e.state[1]
+	velocity += float64(actionInt-1)*e.force + math.Cos(3*position)*(-gravity)
+	velocity = clip(velocity, -maxSpeed, maxSpeed)
+	position += velocity
+	position = clip(position, minPosition, maxPosition)
+	if position == minPosition && velocity < 0 {
+		velocity = 0
+	}
+	e.state = [2]float64{position, velocity}
+
+	terminated = position >= e.goalPosition && velocity >= e.goalVelocity
+	return e.getObs(), -1, terminated, false, nil
+}
+
+// Reset resets to a brand new episode.
+// The "low" and "high" options change the bounds of the uniformly
+// distributed initial position, which default to -0.6 and -0.4.
+func (e *Environment) Reset(opts ...common.ResetOption) (obs common.Obs, info any) {
+	e.state = [2]float64{startPosition(e.rng, opts), 0}
+	return e.getObs(), nil
+}
+
+func (e *Environment) getObs() common.Obs {
+	return obsT(e.state)
+}
+
+// startPosition re-seeds rng if requested by opts and returns a random
+// start position within the bounds of the "low" and "high" options.
+func startPosition(rng *rand.Rand, opts []common.ResetOption) float64 {
+	o := common.NewResetOptions(opts...)
+	if o.Seed != nil {
+		rng.Seed(*o.Seed)
+	}
+	low, high := o.Float64("low", -0.6), o.Float64("high", -0.4)
+	return low + (high-low)*rng.Float64()
+}
+
+func clip(v, low, high float64) float64 {
+	return math.Min(math.Max(v, low), high)
+}
+
+type obsT [2]float64
+
+var _ common.Obs = obsT{}
+
+func (o obsT) Unmarshal(dst any) error {
+	switch v := dst.(type) {
+	case *[]float64:
+		*v = append((*v)[:0], o[:]...)
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
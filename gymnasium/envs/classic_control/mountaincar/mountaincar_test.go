@@ -0,0 +1,186 @@
+package mountaincar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+func checkObs(t *testing.T, obs common.Obs, want []float64) {
+	t.Helper()
+	var got []float64
+	if err := obs.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("obs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Errorf("obs = %v, want %v", got, want)
+			return
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	// Reference values from the Gymnasium MountainCar-v0 dynamics.
+	e := New(0)
+	e.state = [2]float64{-0.5, 0}
+
+	obs, reward, terminated, truncated, info := e.Step(2)
+	if reward != -1 || terminated || truncated || info != nil {
+		t.Errorf("Step(2) = (%v, %v, %v, %v), want (-1, false, false, nil)", reward, terminated, truncated, info)
+	}
+	checkObs(t, obs, []float64{-0.49917684300416926, 0.0008231569958307428})
+
+	obs, _, _, _, _ = e.Step(0)
+	checkObs(t, obs, []float64{-0.49953668667935325, -0.00035984367518397545})
+
+	// Inelastic collision with the left wall.
+	e.state = [2]float64{-1.19, -0.05}
+	obs, _, _, _, _ = e.Step(0)
+	checkObs(t, obs, []float64{-1.2, 0})
+}
+
+func TestTermination(t *testing.T) {
+	e := New(0)
+	e.Reset()
+	e.state = [2]float64{-0.5, 0}
+
+	var steps int
+	for {
+		steps++
+		action := 0
+		if e.state[1] >= 0 {
+			action = 2
+		}
+		_, _, terminated, truncated, _ := e.Step(action)
+		if truncated {
+			t.Fatalf("truncated after %v steps", steps)
+		}
+		if terminated {
+			break
+		}
+	}
+	if steps != 124 {
+		t.Errorf("terminated after %v steps, want 124", steps)
+	}
+}
+
+func TestReset(t *testing.T) {
+	for _, env := range []interface {
+		Reset(opts ...common.ResetOption) (common.Obs, any)
+	}{New(0), NewContinuous(0)} {
+		obs, _ := env.Reset()
+		var got []float64
+		if err := obs.Unmarshal(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got[0] < -0.6 || got[0] > -0.4 || got[1] != 0 {
+			t.Errorf("%T.Reset obs = %v, want position in [-0.6, -0.4] and velocity 0", env, got)
+		}
+
+		opts := []common.ResetOption{common.WithSeed(7), common.WithOptions(map[string]any{"low": 0.1, "high": 0.2})}
+		obs, _ = env.Reset(opts...)
+		if err := obs.Unmarshal(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got[0] < 0.1 || got[0] > 0.2 {
+			t.Errorf("%T.Reset obs = %v, want position in [0.1, 0.2]", env, got)
+		}
+		position := got[0]
+		obs, _ = env.Reset(opts...)
+		if err := obs.Unmarshal(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got[0] != position {
+			t.Errorf("%T.Reset(WithSeed(7)) position = %v, want %v", env, got[0], position)
+		}
+	}
+}
+
+func TestContinuousStep(t *testing.T) {
+	// Reference values from the Gymnasium MountainCarContinuous-v0 dynamics.
+	e := NewContinuous(0)
+	e.state = [2]float64{float64(float32(-0.5)), 0}
+
+	obs, reward, terminated, truncated, info := e.Step([]float64{0.5})
+	if reward != -0.025 || terminated || truncated || info != nil {
+		t.Errorf("Step(0.5) = (%v, %v, %v, %v), want (-0.025, false, false, nil)", reward, terminated, truncated, info)
+	}
+	checkObs(t, obs, []float64{-0.49942684173583984, 0.0005731569835916162})
+
+	// The force is clipped, but the action cost is not.
+	obs, reward, _, _, _ = e.Step([]float64{2})
+	if reward != -0.4 {
+		t.Errorf("Step(2) reward = %v, want -0.4", reward)
+	}
+	checkObs(t, obs, []float64{-0.4975348114967346, 0.0018920262809842825})
+}
+
+func TestContinuousTermination(t *testing.T) {
+	e := NewContinuous(0)
+	e.Reset()
+	e.state = [2]float64{float64(float32(-0.5)), 0}
+
+	var steps int
+	var total float64
+	for {
+		steps++
+		action := -1.0
+		if e.state[1] >= 0 {
+			action = 1
+		}
+		_, reward, terminated, _, _ := e.Step([]float64{action})
+		total += reward
+		if terminated {
+			break
+		}
+	}
+	if steps != 106 {
+		t.Errorf("terminated after %v steps, want 106", steps)
+	}
+	if want := 89.4; math.Abs(total-want) > 1e-9 {
+		t.Errorf("total reward = %v, want %v", total, want)
+	}
+}
+
+func TestInvalidAction(t *testing.T) {
+	e := New(0)
+	for _, action := range []any{3, -1, 1.0} {
+		if _, _, _, truncated, info := e.Step(action); !truncated || info == nil {
+			t.Errorf("Step(%v) = (truncated=%v, info=%v), want truncated with an error", action, truncated, info)
+		}
+	}
+
+	c := NewContinuous(0)
+	for _, action := range []any{1, []float64{}, []float64{1, 2}} {
+		if _, _, _, truncated, info := c.Step(action); !truncated || info == nil {
+			t.Errorf("Step(%v) = (truncated=%v, info=%v), want truncated with an error", action, truncated, info)
+		}
+	}
+}
+
+func TestSpaces(t *testing.T) {
+	as, err := New(0).ActionSpace()
+	if err != nil || as.Type != "Discrete" || as.N != 3 {
+		t.Errorf("ActionSpace = %+v, %v", as, err)
+	}
+	as, err = NewContinuous(0).ActionSpace()
+	if err != nil || as.Type != "Box" || as.Low[0] != -1 || as.High[0] != 1 || as.Shape[0] != 1 {
+		t.Errorf("continuous ActionSpace = %+v, %v", as, err)
+	}
+	os, err := New(0).ObservationSpace()
+	if err != nil || os.Type != "Box" || os.Low[0] != -1.2 || os.High[1] != 0.07 || os.Shape[0] != 2 {
+		t.Errorf("ObservationSpace = %+v, %v", os, err)
+	}
+
+	var action []float64
+	for i := 0; i < 100; i++ {
+		if err := NewContinuous(0).SampleAction(&action); err != nil || len(action) != 1 || action[0] < -1 || action[0] > 1 {
+			t.Fatalf("SampleAction = %v, %v", action, err)
+		}
+	}
+}
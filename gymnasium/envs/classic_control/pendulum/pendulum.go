@@ -0,0 +1,187 @@
+// Package pendulum implements the Pendulum environment in Go.
+// It is a port of: https://github.com/Farama-Foundation/Gymnasium/blob/main/gymnasium/envs/classic_control/pendulum.py
+//
+// # Description
+// The inverted pendulum swingup problem is based on the classic problem in
+// control theory. The system consists of a pendulum attached at one end to
+// a fixed point, and the other end being free. The pendulum starts in a
+// random position and the goal is to apply torque on the free end to swing
+// it into an upright position, with its center of gravity right above the
+// fixed point.
+//
+// # Action Space
+// The action is a Box of shape `(1,)` (a `[]float64`) representing the
+// torque applied to free end of the pendulum.
+//
+//	| Num | Action | Min  | Max |
+//	|-----|--------|------|-----|
+//	| 0   | Torque | -2.0 | 2.0 |
+//
+// # Observation Space
+// The observation is a Box of shape `(3,)` representing the x-y coordinates
+// of the pendulum's free end and its angular velocity.
+//
+//	| Num | Observation      | Min  | Max |
+//	|-----|------------------|------|-----|
+//	| 0   | x = cos(theta)   | -1.0 | 1.0 |
+//	| 1   | y = sin(theta)   | -1.0 | 1.0 |
+//	| 2   | Angular Velocity | -8.0 | 8.0 |
+//
+// The observation is returned as `[]float64` of length 3.
+//
+// # Rewards
+// The reward function is defined as:
+//
+//	r = -(theta^2 + 0.1 * theta_dt^2 + 0.001 * torque^2)
+//
+// where theta is the pendulum's angle normalized between [-pi, pi]
+// (with 0 being in the upright position). Based on the above equation,
+// the minimum reward that can be obtained is
+// -(pi^2 + 0.1 * 8^2 + 0.001 * 2^2) = -16.2736044, while the maximum
+// reward is zero (pendulum is upright with zero velocity and no torque applied).
+//
+// # Starting State
+// The starting state is a random angle in [-pi, pi] and a random angular
+// velocity in [-1, 1].
+//
+// # Episode Truncation
+// The episode is truncated at 200 time steps.
+//
+// # Arguments
+//
+// ```
+// gymnasium.Make("Pendulum-v1")
+// ```
+//
+// `g=10.0`: the acceleration of gravity measured in (m s^-2) used to
+// calculate the pendulum dynamics.
+package pendulum
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+const (
+	maxSpeed  = 8.0
+	maxTorque = 2.0
+	dt        = 0.05
+	m         = 1.0
+	l         = 1.0
+
+	// MaxEpisodeSteps is the number of steps after which gymnasium.Make
+	// truncates an episode by default.
+	MaxEpisodeSteps = 200
+)
+
+// Environment represents a Pendulum environment.
+type Environment struct {
+	g float64
+
+	// state is (theta, theta_dot).
+	state [2]float64
+	rng   *rand.Rand
+}
+
+// New returns a new Pendulum environment with the acceleration of gravity g.
+func New(g float64) *Environment {
+	return &Environment{g: g, rng: rand.New(rand.NewSource(rand.Int63()))}
+}
+
+func (e *Environment) ActionSpace() (*common.Space, error) {
+	return &common.Space{
+		Type:  "Box",
+		Low:   []float64{-maxTorque},
+		High:  []float64{maxTorque},
+		Shape: []int{1},
+	}, nil
+}
+
+func (e *Environment) ObservationSpace() (*common.Space, error) {
+	return &common.Space{
+		Type:  "Box",
+		Low:   []float64{-1, -1, -maxSpeed},
+		High:  []float64{1, 1, maxSpeed},
+		Shape: []int{3},
+	}, nil
+}
+
+func (e *Environment) SampleAction(action any) error {
+	switch v := action.(type) {
+	case *[]float64:
+		*v = append((*v)[:0], -maxTorque+2*maxTorque*e.rng.Float64())
+	default:
+		return fmt.Errorf("unsupported SampleAction type %T", action)
+	}
+	return nil
+}
+
+func (e *Environment) Close() error { return nil }
+
+// Step performs the provided action (a []float64 of length 1) and returns
+// the next observation and the reward for this action. A Pendulum episode
+// never terminates. If the action is invalid, truncated will be true and
+// info is the error.
+func (e *Environment) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	actions, ok := action.([]float64)
+	if !ok || len(actions) != 1 {
+		info = fmt.Errorf("error: Pendulum: invalid action %T(%v); must be []float64 of length 1", action, action)
+		return e.getObs(), reward, terminated, true, info
+	}
+
+	th, thdot := e.state[0], e.state[1]
+	u := math.Min(math.Max(actions[0], -maxTorque), maxTorque)
+	an := angleNormalize(th)
+	costs := an*an + 0.1*thdot*thdot + 0.001*(u*u)
+
+	newthdot := thdot + (3*e.g/(2*l)*math.Sin(th)+3.0/(m*l*l)*u)*dt
+	newthdot = math.Min(math.Max(newthdot, -maxSpeed), maxSpeed)
+	newth := th + newthdot*dt
+
+	e.state = [2]float64{newth, newthdot}
+	return e.getObs(), -costs, false, false, nil
+}
+
+// Reset resets to a brand new episode.
+// The "x_init" and "y_init" options change the bounds of the initial
+// angle and angular velocity, which default to pi and 1.
+func (e *Environment) Reset(opts ...common.ResetOption) (obs common.Obs, info any) {
+	o := common.NewResetOptions(opts...)
+	if o.Seed != nil {
+		e.rng.Seed(*o.Seed)
+	}
+	x, y := o.Float64("x_init", math.Pi), o.Float64("y_init", 1)
+	e.state = [2]float64{-x + 2*x*e.rng.Float64(), -y + 2*y*e.rng.Float64()}
+	return e.getObs(), nil
+}
+
+// angleNormalize normalizes the angle to [-pi, pi).
+func angleNormalize(x float64) float64 {
+	r := math.Mod(x+math.Pi, 2*math.Pi)
+	if r < 0 { // Python's modulo has the sign of the divisor.
+		r += 2 * math.Pi
+	}
+	return r - math.Pi
+}
+
+type obsT [3]float64
+
+var _ common.Obs = obsT{}
+
+func (o obsT) Unmarshal(dst any) error {
+	switch v := dst.(type) {
+	case *[]float64:
+		*v = append((*v)[:0], o[:]...)
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
+
+func (e *Environment) getObs() common.Obs {
+	th, thdot := e.state[0], e.state[1]
+	return obsT{math.Cos(th), math.Sin(th), thdot}
+}
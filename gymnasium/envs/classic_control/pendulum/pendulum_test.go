@@ -0,0 +1,125 @@
+package pendulum
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+func checkObs(t *testing.T, obs common.Obs, want []float64) {
+	t.Helper()
+	var got []float64
+	if err := obs.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("obs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Errorf("obs = %v, want %v", got, want)
+			return
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	// Reference values from the Gymnasium Pendulum-v1 dynamics.
+	e := New(10)
+	e.state = [2]float64{1, 0.5}
+
+	obs, reward, terminated, truncated, info := e.Step([]float64{1.5})
+	if math.Abs(reward-(-1.02725)) > 1e-12 || terminated || truncated || info != nil {
+		t.Errorf("Step(1.5) = (%v, %v, %v, %v), want (-1.02725, false, false, nil)", reward, terminated, truncated, info)
+	}
+	checkObs(t, obs, []float64{0.48204838409011624, 0.8761445973103457, 1.3561032386059226})
+
+	// The torque is clipped, including in the cost.
+	obs, reward, _, _, _ = e.Step([]float64{-5})
+	if want := -1.3281094632207342; math.Abs(reward-want) > 1e-12 {
+		t.Errorf("Step(-5) reward = %v, want %v", reward, want)
+	}
+	checkObs(t, obs, []float64{0.40532158575502103, 0.9141741694671947, 1.713211686588682})
+
+	// The angle is normalized in the cost and the velocity is clipped.
+	e = New(9.81)
+	e.state = [2]float64{7, -8}
+	obs, reward, _, _, _ = e.Step([]float64{0})
+	if want := -6.913823303843224; math.Abs(reward-want) > 1e-12 {
+		t.Errorf("Step(0) reward = %v, want %v", reward, want)
+	}
+	checkObs(t, obs, []float64{0.9424261958746178, 0.33441421221188633, -7.516622109992651})
+}
+
+func TestAngleNormalize(t *testing.T) {
+	tests := []struct {
+		x, want float64
+	}{
+		{x: 0, want: 0},
+		{x: -4, want: 2.2831853071795862},
+		{x: 7, want: 0.7168146928204138},
+	}
+	for _, tt := range tests {
+		if got := angleNormalize(tt.x); math.Abs(got-tt.want) > 1e-15 {
+			t.Errorf("angleNormalize(%v) = %v, want %v", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestNeverEnds(t *testing.T) {
+	e := New(10)
+	e.Reset()
+	for i := 1; i <= 2*MaxEpisodeSteps; i++ {
+		if _, _, terminated, truncated, _ := e.Step([]float64{0}); terminated || truncated {
+			t.Fatalf("step %v: terminated = %v, truncated = %v", i, terminated, truncated)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	e := New(10)
+	e.Reset()
+	if th, thdot := e.state[0], e.state[1]; th < -math.Pi || th > math.Pi || thdot < -1 || thdot > 1 {
+		t.Errorf("Reset state = %v, want theta in [-pi, pi] and theta_dot in [-1, 1]", e.state)
+	}
+
+	opts := []common.ResetOption{common.WithSeed(3), common.WithOptions(map[string]any{"x_init": 0.5, "y_init": 0.25})}
+	e.Reset(opts...)
+	state := e.state
+	if th, thdot := state[0], state[1]; th < -0.5 || th > 0.5 || thdot < -0.25 || thdot > 0.25 {
+		t.Errorf("Reset state = %v, want theta in [-0.5, 0.5] and theta_dot in [-0.25, 0.25]", state)
+	}
+	e.Step([]float64{1})
+	e.Reset(opts...)
+	if e.state != state {
+		t.Errorf("Reset(WithSeed(3)) state = %v, want %v", e.state, state)
+	}
+}
+
+func TestInvalidAction(t *testing.T) {
+	e := New(10)
+	for _, action := range []any{1, 1.0, []float64{}, []float64{1, 2}} {
+		if _, _, _, truncated, info := e.Step(action); !truncated || info == nil {
+			t.Errorf("Step(%v) = (truncated=%v, info=%v), want truncated with an error", action, truncated, info)
+		}
+	}
+}
+
+func TestSpaces(t *testing.T) {
+	as, err := New(10).ActionSpace()
+	if err != nil || as.Type != "Box" || as.Low[0] != -2 || as.High[0] != 2 || as.Shape[0] != 1 {
+		t.Errorf("ActionSpace = %+v, %v", as, err)
+	}
+	os, err := New(10).ObservationSpace()
+	if err != nil || os.Type != "Box" || os.Low[2] != -8 || os.High[0] != 1 || os.Shape[0] != 3 {
+		t.Errorf("ObservationSpace = %+v, %v", os, err)
+	}
+
+	var action []float64
+	for i := 0; i < 100; i++ {
+		if err := New(10).SampleAction(&action); err != nil || len(action) != 1 || action[0] < -2 || action[0] > 2 {
+			t.Fatalf("SampleAction = %v, %v", action, err)
+		}
+	}
+}
@@ -97,6 +97,8 @@ import (
 	"fmt"
 	"math/rand"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/gmlewis/gep/v2/common"
 )
@@ -107,6 +109,10 @@ type Environment struct {
 	sab     bool
 	dealer  []int
 	player  []int
+	rng     *rand.Rand
+	// done is true once the episode has terminated,
+	// revealing the dealer's face down card.
+	done bool
 }
 
 // New returns a new Blackjack environment.
@@ -114,6 +120,7 @@ func New(natural, sab bool) *Environment {
 	return &Environment{
 		natural: natural,
 		sab:     sab,
+		rng:     rand.New(rand.NewSource(rand.Int63())),
 	}
 }
 
@@ -135,7 +142,7 @@ func (e *Environment) ObservationSpace() (*common.Space, error) {
 func (e *Environment) SampleAction(action any) error {
 	switch v := action.(type) {
 	case *int:
-		*v = rand.Intn(2)
+		*v = e.rng.Intn(2)
 	default:
 		return fmt.Errorf("unsupported SampleAction type %T", action)
 	}
@@ -160,18 +167,20 @@ func (e *Environment) Step(action any) (obs common.Obs, reward float64, terminat
 	}
 
 	if actionInt == 1 { // hit: add a card to players hand and return.
-		e.player = append(e.player, drawCard())
+		e.player = append(e.player, e.drawCard())
 		if isBust(e.player) {
 			terminated = true
 			reward = -1
+			e.done = true
 		}
 		return e.getObs(), reward, terminated, false, nil
 	}
 
 	// stay: play out the dealer's hand, and score.
 	terminated = true
+	e.done = true
 	for sumHand(e.dealer) < 17 {
-		e.dealer = append(e.dealer, drawCard())
+		e.dealer = append(e.dealer, e.drawCard())
 	}
 	reward = cmp(score(e.player), score(e.dealer))
 	if e.sab && isNatural(e.player) && !isNatural(e.dealer) {
@@ -186,12 +195,55 @@ func (e *Environment) Step(action any) (obs common.Obs, reward float64, terminat
 }
 
 // Reset resets to a brand new episode.
-func (e *Environment) Reset() (obs common.Obs, info any) {
-	e.dealer = drawHand()
-	e.player = drawHand()
+func (e *Environment) Reset(opts ...common.ResetOption) (obs common.Obs, info any) {
+	if o := common.NewResetOptions(opts...); o.Seed != nil {
+		e.rng.Seed(*o.Seed)
+	}
+	e.dealer = e.drawHand()
+	e.player = e.drawHand()
+	e.done = false
 	return e.getObs(), nil
 }
 
+// Render returns a text frame of the dealer's and the player's hands,
+// where "A" is an ace. The dealer's face down card is shown as "??"
+// until the end of the episode. It returns an empty frame before Reset.
+func (e *Environment) Render() string {
+	if len(e.dealer) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Dealer:")
+	if e.done {
+		writeHand(&sb, e.dealer)
+	} else {
+		fmt.Fprintf(&sb, " %v ??", cardName(e.dealer[0]))
+	}
+	sb.WriteString("\nPlayer:")
+	writeHand(&sb, e.player)
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func writeHand(sb *strings.Builder, hand []int) {
+	for _, card := range hand {
+		fmt.Fprintf(sb, " %v", cardName(card))
+	}
+	fmt.Fprintf(sb, " (%v", sumHand(hand))
+	if usableAce(hand) {
+		sb.WriteString(", usable ace")
+	}
+	sb.WriteString(")")
+}
+
+func cardName(card int) string {
+	if card == 1 {
+		return "A"
+	}
+	return strconv.Itoa(card)
+}
+
 type obsT [3]int
 
 var _ common.Obs = obsT{}
@@ -237,12 +289,12 @@ func cmp(a, b int) float64 {
 	return v1 - v2
 }
 
-func drawCard() int {
-	return deck[rand.Intn(len(deck))]
+func (e *Environment) drawCard() int {
+	return deck[e.rng.Intn(len(deck))]
 }
 
-func drawHand() []int {
-	return []int{drawCard(), drawCard()}
+func (e *Environment) drawHand() []int {
+	return []int{e.drawCard(), e.drawCard()}
 }
 
 func usableAce(hand []int) bool {
@@ -0,0 +1,216 @@
+// Package cliffwalking implements the CliffWalking environment in Go.
+// It is a port of: https://github.com/Farama-Foundation/Gymnasium/blob/main/gymnasium/envs/toy_text/cliffwalking.py
+//
+// Cliff walking involves crossing a gridworld from start to goal while
+// avoiding falling off a cliff.
+//
+// # Description
+// The game starts with the player at location [3, 0] of the 4x12 grid world
+// with the goal located at [3, 11]. If the player reaches the goal the
+// episode ends.
+//
+// A cliff runs along [3, 1..10]. If the player moves to a cliff location it
+// returns to the start location.
+//
+// The player makes moves until they reach the goal.
+//
+// Adapted from Example 6.6 (page 132) from Reinforcement Learning: An Introduction
+// by Sutton and Barto.
+//
+// # Action Space
+// The action shape is `(1,)` in the range `{0, 3}` indicating
+// which direction to move the player.
+//
+// - 0: Move up
+// - 1: Move right
+// - 2: Move down
+// - 3: Move left
+//
+// # Observation Space
+// There are 3 x 12 + 1 possible states. The player cannot be at the cliff,
+// nor at the goal as the latter results in the end of the episode. What
+// remains are all the positions of the first 3 rows plus the bottom-left cell.
+//
+// The observation is a value representing the player's current position as
+// current_row * ncols + current_col (where both the row and col start at 0).
+//
+// For example, the starting position can be calculated as follows: 3 * 12 + 0 = 36.
+//
+// The observation is returned as an `int`.
+//
+// # Starting State
+// The episode starts with the player in state `[36]` (location [3, 0]).
+//
+// # Reward
+// Each time step incurs -1 reward, unless the player stepped into the cliff,
+// which incurs -100 reward.
+//
+// # Episode End
+// The episode terminates when the player enters state `[47]` (location [3, 11]).
+//
+// # Information
+//
+// Step and Reset return an Info with the probability of the transition.
+//
+// # Arguments
+//
+// ```
+// gymnasium.Make("CliffWalking-v0")
+// ```
+package cliffwalking
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+const (
+	up    = 0
+	right = 1
+	down  = 2
+	left  = 3
+
+	nrow = 4
+	ncol = 12
+
+	startState    = 3 * ncol
+	terminalState = nrow*ncol - 1
+)
+
+// Info is the additional information returned by Step and Reset.
+type Info struct {
+	// Prob is the probability of the transition.
+	Prob float64
+}
+
+// Environment represents a CliffWalking environment.
+type Environment struct {
+	s   int
+	rng *rand.Rand
+}
+
+// New returns a new CliffWalking environment.
+func New() *Environment {
+	return &Environment{s: startState, rng: rand.New(rand.NewSource(rand.Int63()))}
+}
+
+func (e *Environment) ActionSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: 4}, nil
+}
+
+func (e *Environment) ObservationSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: nrow * ncol}, nil
+}
+
+func (e *Environment) SampleAction(action any) error {
+	switch v := action.(type) {
+	case *int:
+		*v = e.rng.Intn(4)
+	default:
+		return fmt.Errorf("unsupported SampleAction type %T", action)
+	}
+	return nil
+}
+
+func (e *Environment) Close() error { return nil }
+
+// Step performs the provided action and returns the next observation,
+// the reward for this action, and whether this episode is terminated.
+// If the action is invalid, truncated will be true and info is the error.
+// Otherwise info is an Info.
+func (e *Environment) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	actionInt, ok := action.(int)
+	if !ok {
+		info = fmt.Errorf("error: CliffWalking: invalid action type %T; must be int", action)
+		return obsT(e.s), reward, terminated, true, info
+	}
+
+	if actionInt < 0 || actionInt > 3 {
+		info = fmt.Errorf("error: CliffWalking: invalid action=%v; must be 0 (up), 1 (right), 2 (down) or 3 (left)", actionInt)
+		return obsT(e.s), reward, terminated, true, info
+	}
+
+	row, col := e.s/ncol, e.s%ncol
+	switch actionInt {
+	case up:
+		row = max(row-1, 0)
+	case right:
+		col = min(col+1, ncol-1)
+	case down:
+		row = min(row+1, nrow-1)
+	case left:
+		col = max(col-1, 0)
+	}
+
+	if isCliff(row, col) {
+		e.s = startState
+		return obsT(e.s), -100, false, false, Info{Prob: 1}
+	}
+
+	e.s = row*ncol + col
+	return obsT(e.s), -1, e.s == terminalState, false, Info{Prob: 1}
+}
+
+// Reset resets to a brand new episode.
+// The seed only affects SampleAction since the start state is fixed.
+func (e *Environment) Reset(opts ...common.ResetOption) (obs common.Obs, info any) {
+	if o := common.NewResetOptions(opts...); o.Seed != nil {
+		e.rng.Seed(*o.Seed)
+	}
+	e.s = startState
+	return obsT(e.s), Info{Prob: 1}
+}
+
+// Render returns an ANSI text frame of the grid, where "x" is the player,
+// "C" the cliff, "T" the goal and "o" the other tiles.
+func (e *Environment) Render() string {
+	var sb strings.Builder
+	for s := range nrow * ncol {
+		row, col := s/ncol, s%ncol
+		output := " o "
+		switch {
+		case s == e.s:
+			output = " x "
+		case s == terminalState:
+			output = " T "
+		case isCliff(row, col):
+			output = " C "
+		}
+		if col == 0 {
+			output = strings.TrimLeft(output, " ")
+		}
+		if col == ncol-1 {
+			output = strings.TrimRight(output, " ") + "\n"
+		}
+		sb.WriteString(output)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func isCliff(row, col int) bool {
+	return row == nrow-1 && col > 0 && col < ncol-1
+}
+
+type obsT int
+
+var _ common.Obs = obsT(0)
+
+func (o obsT) Unmarshal(dst any) error {
+	switch v := dst.(type) {
+	case *int:
+		*v = int(o)
+	case *[]int:
+		if len(*v) == 0 {
+			*v = append(*v, int(o))
+		} else {
+			(*v)[0] = int(o)
+		}
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cliffwalking
+
+import (
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+func getObs(t *testing.T, obs common.Obs) int {
+	t.Helper()
+	var got int
+	if err := obs.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestCliff(t *testing.T) {
+	e := New()
+	if obs, _ := e.Reset(); getObs(t, obs) != startState {
+		t.Fatalf("Reset = %v, want %v", getObs(t, obs), startState)
+	}
+
+	e.Step(up)
+	if obs, _, _, _, _ := e.Step(right); getObs(t, obs) != 25 {
+		t.Fatalf("Step(right) = %v, want 25", getObs(t, obs))
+	}
+	obs, reward, terminated, truncated, info := e.Step(down)
+	if got := getObs(t, obs); got != startState || reward != -100 || terminated || truncated || info != (Info{Prob: 1}) {
+		t.Errorf("Step into cliff = (%v, %v, %v, %v, %v), want (%v, -100, false, false, {Prob: 1})", got, reward, terminated, truncated, info, startState)
+	}
+}
+
+func TestGoal(t *testing.T) {
+	e := New()
+	e.Reset()
+
+	// The optimal path: up, 11 x right, down.
+	actions := []int{up}
+	for i := 0; i < ncol-1; i++ {
+		actions = append(actions, right)
+	}
+	actions = append(actions, down)
+
+	var total float64
+	for i, action := range actions {
+		_, reward, terminated, _, _ := e.Step(action)
+		total += reward
+		if terminated != (i == len(actions)-1) {
+			t.Fatalf("step %v: terminated = %v", i, terminated)
+		}
+	}
+	if e.s != terminalState || total != -13 {
+		t.Errorf("state = %v, total reward = %v, want (%v, -13)", e.s, total, terminalState)
+	}
+}
+
+func TestWalls(t *testing.T) {
+	e := New()
+	e.Reset()
+	for _, action := range []int{left, down} {
+		if obs, reward, _, _, _ := e.Step(action); getObs(t, obs) != startState || reward != -1 {
+			t.Errorf("Step(%v) = (%v, %v), want (%v, -1)", action, getObs(t, obs), reward, startState)
+		}
+	}
+}
+
+func TestInvalidAction(t *testing.T) {
+	e := New()
+	for _, action := range []any{4, -1, 1.0} {
+		if _, _, _, truncated, info := e.Step(action); !truncated || info == nil {
+			t.Errorf("Step(%v) = (truncated=%v, info=%v), want truncated with an error", action, truncated, info)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	e := New()
+	e.Reset()
+	e.Step(up)
+	want := "o  o  o  o  o  o  o  o  o  o  o  o\n" +
+		"o  o  o  o  o  o  o  o  o  o  o  o\n" +
+		"x  o  o  o  o  o  o  o  o  o  o  o\n" +
+		"o  C  C  C  C  C  C  C  C  C  C  T\n" +
+		"\n"
+	if got := e.Render(); got != want {
+		t.Errorf("Render =\n%v\nwant:\n%v", got, want)
+	}
+}
@@ -0,0 +1,383 @@
+// Package frozenlake implements the FrozenLake environment in Go.
+// It is a port of: https://github.com/Farama-Foundation/Gymnasium/blob/main/gymnasium/envs/toy_text/frozen_lake.py
+//
+// Frozen lake involves crossing a frozen lake from start to goal without
+// falling into any holes by walking over the frozen lake. The player may not
+// always move in the intended direction due to the slippery nature of the
+// frozen lake.
+//
+// # Description
+// The game starts with the player at location [0,0] of the frozen lake grid
+// world with the goal located at far extent of the world e.g. [3,3] for the
+// 4x4 environment.
+//
+// Holes in the ice are distributed in set locations when using a pre-determined
+// map or in random locations when a random map is generated.
+//
+// The player makes moves until they reach the goal or fall in a hole.
+//
+// The lake is slippery (unless disabled) so the player may move perpendicular
+// to the intended direction sometimes (see isSlippery).
+//
+// # Action Space
+// The action shape is `(1,)` in the range `{0, 3}` indicating
+// which direction to move the player.
+//
+// - 0: Move left
+// - 1: Move down
+// - 2: Move right
+// - 3: Move up
+//
+// # Observation Space
+// The observation is a value representing the player's current position as
+// current_row * ncols + current_col (where both the row and col start at 0).
+//
+// For example, the goal position in the 4x4 map can be calculated as follows: 3 * 4 + 3 = 15.
+// The number of possible observations is dependent on the size of the map.
+//
+// The observation is returned as an `int`.
+//
+// # Starting State
+// The episode starts with the player in state `[0]` (location [0, 0]).
+//
+// # Rewards
+// - Reach goal: +1
+// - Reach hole: 0
+// - Reach frozen: 0
+//
+// # Episode End
+// The episode ends if the following happens:
+//
+// - Termination:
+// 1. The player moves into a hole.
+// 2. The player reaches the goal at `max(nrow) * max(ncol) - 1` (location `[max(nrow)-1, max(ncol)-1]`).
+//
+// - Truncation:
+// 1. The length of the episode is 100.
+//
+// # Information
+//
+// Step and Reset return an Info with the probability of the transition.
+//
+// # Arguments
+//
+// ```
+// gymnasium.Make("FrozenLake-v1")
+// ```
+//
+// `desc=nil`: Used to specify maps non-preloaded maps, e.g.
+// `[]string{"SFFF", "FHFH", "FFFH", "HFFG"}`, where
+// "S" is the start tile, "G" is the goal tile, "F" is frozen and "H" is a hole.
+// A random generated map can be specified by calling GenerateRandomMap,
+// whose rng makes the map reproducible.
+//
+// `map_name="4x4"`: ID to use any of the preloaded maps ("4x4" or "8x8").
+// If desc is nil and mapName is empty, a random 8x8 map is generated
+// with the random number generator of the environment.
+//
+// `is_slippery=true`: If true the player will move in the intended direction
+// with probability of 1/3 else will move in either perpendicular direction
+// with equal probability of 1/3 in both directions.
+package frozenlake
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/gmlewis/gep/v2/common"
+	"github.com/gmlewis/gep/v2/gymnasium/envs/toy_text"
+)
+
+const (
+	left  = 0
+	down  = 1
+	right = 2
+	up    = 3
+
+	// MaxEpisodeSteps is the number of steps after which gymnasium.Make
+	// truncates an episode by default.
+	MaxEpisodeSteps = 100
+)
+
+// Maps are the preloaded maps, selected by name.
+var Maps = map[string][]string{
+	"4x4": {"SFFF", "FHFH", "FFFH", "HFFG"},
+	"8x8": {
+		"SFFFFFFF",
+		"FFFFFFFF",
+		"FFFHFFFF",
+		"FFFFFHFF",
+		"FFFHFFFF",
+		"FHHFFFHF",
+		"FHFFHFHF",
+		"FFFHFFFG",
+	},
+}
+
+// Info is the additional information returned by Step and Reset.
+type Info struct {
+	// Prob is the probability of the transition.
+	Prob float64
+}
+
+// Environment represents a FrozenLake environment.
+type Environment struct {
+	desc       [][]byte
+	nrow       int
+	ncol       int
+	isSlippery bool
+	starts     []int
+
+	s   int
+	rng *rand.Rand
+	// lastAction is the action of the last Step, or -1 after Reset.
+	lastAction int
+}
+
+// New returns a new FrozenLake environment. desc, if not nil, overrides
+// the preloaded map called mapName.
+func New(desc []string, mapName string, isSlippery bool) (*Environment, error) {
+	rng := rand.New(rand.NewSource(rand.Int63()))
+	switch {
+	case desc == nil && mapName == "":
+		var err error
+		if desc, err = GenerateRandomMap(rng, 8, 0.8); err != nil {
+			return nil, err
+		}
+	case desc == nil:
+		var ok bool
+		if desc, ok = Maps[mapName]; !ok {
+			return nil, fmt.Errorf("frozenlake: unknown map name %q", mapName)
+		}
+	}
+
+	if len(desc) == 0 || len(desc[0]) == 0 {
+		return nil, errors.New("frozenlake: empty map description")
+	}
+
+	e := &Environment{
+		nrow:       len(desc),
+		ncol:       len(desc[0]),
+		isSlippery: isSlippery,
+		rng:        rng,
+		lastAction: -1,
+	}
+	for row, line := range desc {
+		if len(line) != e.ncol {
+			return nil, fmt.Errorf("frozenlake: map row %v has length %v, want %v", row, len(line), e.ncol)
+		}
+		for col := range len(line) {
+			switch line[col] {
+			case 'S':
+				e.starts = append(e.starts, e.toS(row, col))
+			case 'F', 'H', 'G':
+			default:
+				return nil, fmt.Errorf("frozenlake: invalid tile %q at row %v, col %v", line[col], row, col)
+			}
+		}
+		e.desc = append(e.desc, []byte(line))
+	}
+	if len(e.starts) == 0 {
+		return nil, errors.New("frozenlake: map has no start tile 'S'")
+	}
+
+	return e, nil
+}
+
+func (e *Environment) ActionSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: 4}, nil
+}
+
+func (e *Environment) ObservationSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: e.nrow * e.ncol}, nil
+}
+
+func (e *Environment) SampleAction(action any) error {
+	switch v := action.(type) {
+	case *int:
+		*v = e.rng.Intn(4)
+	default:
+		return fmt.Errorf("unsupported SampleAction type %T", action)
+	}
+	return nil
+}
+
+func (e *Environment) Close() error { return nil }
+
+// Step performs the provided action and returns the next observation,
+// the reward for this action, and whether this episode is terminated.
+// If the action is invalid, truncated will be true and info is the error.
+// Otherwise info is an Info.
+func (e *Environment) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	actionInt, ok := action.(int)
+	if !ok {
+		info = fmt.Errorf("error: FrozenLake: invalid action type %T; must be int", action)
+		return obsT(e.s), reward, terminated, true, info
+	}
+
+	if actionInt < 0 || actionInt > 3 {
+		info = fmt.Errorf("error: FrozenLake: invalid action=%v; must be 0 (left), 1 (down), 2 (right) or 3 (up)", actionInt)
+		return obsT(e.s), reward, terminated, true, info
+	}
+	e.lastAction = actionInt
+
+	row, col := e.s/e.ncol, e.s%e.ncol
+	if letter := e.desc[row][col]; letter == 'G' || letter == 'H' {
+		return obsT(e.s), 0, true, false, Info{Prob: 1}
+	}
+
+	prob := 1.0
+	if e.isSlippery {
+		// Move in one of (a-1)%4, a, (a+1)%4 with equal probability.
+		actionInt = (actionInt + 3 + e.rng.Intn(3)) % 4
+		prob = 1.0 / 3.0
+	}
+
+	row, col = e.inc(row, col, actionInt)
+	e.s = e.toS(row, col)
+	letter := e.desc[row][col]
+	if letter == 'G' {
+		reward = 1
+	}
+	terminated = letter == 'G' || letter == 'H'
+	return obsT(e.s), reward, terminated, false, Info{Prob: prob}
+}
+
+// Reset resets to a brand new episode.
+func (e *Environment) Reset(opts ...common.ResetOption) (obs common.Obs, info any) {
+	if o := common.NewResetOptions(opts...); o.Seed != nil {
+		e.rng.Seed(*o.Seed)
+	}
+	e.s = e.starts[e.rng.Intn(len(e.starts))]
+	e.lastAction = -1
+	return obsT(e.s), Info{Prob: 1}
+}
+
+// Render returns an ANSI text frame of the map with the current tile
+// highlighted in red, preceded by the last action taken.
+func (e *Environment) Render() string {
+	var sb strings.Builder
+	if e.lastAction >= 0 {
+		fmt.Fprintf(&sb, "  (%v)", []string{"Left", "Down", "Right", "Up"}[e.lastAction])
+	}
+	sb.WriteString("\n")
+
+	row, col := e.s/e.ncol, e.s%e.ncol
+	for r, line := range e.desc {
+		for c, letter := range line {
+			if r == row && c == col {
+				sb.WriteString(toy_text.Colorize(string(letter), "red", false, true))
+			} else {
+				sb.WriteByte(letter)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func (e *Environment) toS(row, col int) int {
+	return row*e.ncol + col
+}
+
+func (e *Environment) inc(row, col, a int) (int, int) {
+	switch a {
+	case left:
+		col = max(col-1, 0)
+	case down:
+		row = min(row+1, e.nrow-1)
+	case right:
+		col = min(col+1, e.ncol-1)
+	case up:
+		row = max(row-1, 0)
+	}
+	return row, col
+}
+
+// GenerateRandomMap generates a random valid map (one that has a path
+// from start to goal) of size x size tiles using rng, where p is the
+// probability that a tile is frozen. The same seed gives the same map.
+func GenerateRandomMap(rng *rand.Rand, size int, p float64) ([]string, error) {
+	if size < 2 {
+		return nil, fmt.Errorf("frozenlake: random map size %v, want at least 2", size)
+	}
+	if !(p > 0) {
+		return nil, fmt.Errorf("frozenlake: random map frozen probability %v, want more than 0", p)
+	}
+	p = min(1, p)
+	board := make([][]byte, size)
+	for {
+		for r := range board {
+			board[r] = make([]byte, size)
+			for c := range board[r] {
+				if rng.Float64() < p {
+					board[r][c] = 'F'
+				} else {
+					board[r][c] = 'H'
+				}
+			}
+		}
+		board[0][0] = 'S'
+		board[size-1][size-1] = 'G'
+		if isValid(board) {
+			break
+		}
+	}
+
+	result := make([]string, size)
+	for r, line := range board {
+		result[r] = string(line)
+	}
+	return result, nil
+}
+
+// isValid performs a depth-first search to see if the goal is reachable from the start.
+func isValid(board [][]byte) bool {
+	size := len(board)
+	type pos struct{ r, c int }
+	frontier := []pos{{0, 0}}
+	discovered := map[pos]bool{}
+	for len(frontier) > 0 {
+		p := frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+		if discovered[p] {
+			continue
+		}
+		discovered[p] = true
+		for _, d := range []pos{{1, 0}, {0, 1}, {-1, 0}, {0, -1}} {
+			rNew, cNew := p.r+d.r, p.c+d.c
+			if rNew < 0 || rNew >= size || cNew < 0 || cNew >= size {
+				continue
+			}
+			if board[rNew][cNew] == 'G' {
+				return true
+			}
+			if board[rNew][cNew] != 'H' {
+				frontier = append(frontier, pos{rNew, cNew})
+			}
+		}
+	}
+	return false
+}
+
+type obsT int
+
+var _ common.Obs = obsT(0)
+
+func (o obsT) Unmarshal(dst any) error {
+	switch v := dst.(type) {
+	case *int:
+		*v = int(o)
+	case *[]int:
+		if len(*v) == 0 {
+			*v = append(*v, int(o))
+		} else {
+			(*v)[0] = int(o)
+		}
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
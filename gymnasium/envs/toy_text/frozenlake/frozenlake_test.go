@@ -0,0 +1,203 @@
+package frozenlake
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+func getObs(t *testing.T, obs common.Obs) int {
+	t.Helper()
+	var got int
+	if err := obs.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestNotSlippery(t *testing.T) {
+	e, err := New(nil, "4x4", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs, info := e.Reset(); getObs(t, obs) != 0 || info != (Info{Prob: 1}) {
+		t.Fatalf("Reset = (%v, %v), want (0, {Prob: 1})", getObs(t, obs), info)
+	}
+
+	// Shortest path to the goal: down, down, right, down, right, right.
+	actions := []int{down, down, right, down, right, right}
+	wantObs := []int{4, 8, 9, 13, 14, 15}
+	for i, action := range actions {
+		obs, reward, terminated, truncated, info := e.Step(action)
+		last := i == len(actions)-1
+		wantReward := 0.0
+		if last {
+			wantReward = 1
+		}
+		if got := getObs(t, obs); got != wantObs[i] || reward != wantReward || terminated != last || truncated || info != (Info{Prob: 1}) {
+			t.Errorf("step %v: Step(%v) = (%v, %v, %v, %v, %v), want (%v, %v, %v, false, {Prob: 1})", i, action, got, reward, terminated, truncated, info, wantObs[i], wantReward, last)
+		}
+	}
+
+	// Stepping from a terminal state stays there.
+	if obs, reward, terminated, _, _ := e.Step(left); getObs(t, obs) != 15 || reward != 0 || !terminated {
+		t.Errorf("Step from goal = (%v, %v, %v), want (15, 0, true)", getObs(t, obs), reward, terminated)
+	}
+}
+
+func TestHole(t *testing.T) {
+	e, err := New(nil, "4x4", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Reset()
+	e.Step(right)
+	obs, reward, terminated, _, _ := e.Step(down)
+	if got := getObs(t, obs); got != 5 || reward != 0 || !terminated {
+		t.Errorf("Step into hole = (%v, %v, %v), want (5, 0, true)", got, reward, terminated)
+	}
+}
+
+func TestWalls(t *testing.T) {
+	e, err := New(nil, "8x8", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Reset()
+	for _, action := range []int{left, up} {
+		if obs, _, _, _, _ := e.Step(action); getObs(t, obs) != 0 {
+			t.Errorf("Step(%v) = %v, want 0", action, getObs(t, obs))
+		}
+	}
+}
+
+func TestSlippery(t *testing.T) {
+	e, err := New(nil, "8x8", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// From state 9 (row 1, col 1), moving right goes up (1), right (10) or down (17).
+	seen := map[int]int{}
+	for i := 0; i < 300; i++ {
+		e.s = 9
+		obs, _, _, _, info := e.Step(right)
+		if info != (Info{Prob: 1.0 / 3.0}) {
+			t.Fatalf("info = %v, want {Prob: 1/3}", info)
+		}
+		seen[getObs(t, obs)]++
+	}
+	for _, s := range []int{1, 10, 17} {
+		if seen[s] == 0 {
+			t.Errorf("never slipped to state %v: %v", s, seen)
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("slipped to unexpected states: %v", seen)
+	}
+}
+
+func TestNew(t *testing.T) {
+	e, err := New([]string{"FFS", "HFG"}, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs, _ := e.Reset(); getObs(t, obs) != 2 {
+		t.Errorf("Reset = %v, want 2", getObs(t, obs))
+	}
+	if os, _ := e.ObservationSpace(); os.Type != "Discrete" || os.N != 6 {
+		t.Errorf("ObservationSpace = %+v, want Discrete(6)", os)
+	}
+
+	e, err = New(nil, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.nrow != 8 || e.ncol != 8 || e.desc[0][0] != 'S' || e.desc[7][7] != 'G' {
+		t.Errorf("random map = %q, want 8x8", e.desc)
+	}
+
+	for _, tt := range []struct {
+		desc    []string
+		mapName string
+	}{
+		{mapName: "5x5"},
+		{desc: []string{}},
+		{desc: []string{"SFF", "FG"}},
+		{desc: []string{"SFX", "FFG"}},
+		{desc: []string{"FFF", "FFG"}},
+	} {
+		if _, err := New(tt.desc, tt.mapName, false); err == nil {
+			t.Errorf("New(%q, %q) = nil error, want error", tt.desc, tt.mapName)
+		}
+	}
+}
+
+func TestGenerateRandomMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		board, err := GenerateRandomMap(rng, 6, 0.5)
+		if err != nil {
+			t.Fatalf("GenerateRandomMap: %v", err)
+		}
+		b := make([][]byte, len(board))
+		for r, line := range board {
+			b[r] = []byte(line)
+		}
+		if !isValid(b) {
+			t.Fatalf("GenerateRandomMap = %q has no path to the goal", board)
+		}
+	}
+
+	if isValid([][]byte{[]byte("SH"), []byte("HG")}) {
+		t.Error("isValid = true for a blocked map, want false")
+	}
+
+	a, _ := GenerateRandomMap(rand.New(rand.NewSource(42)), 8, 0.8)
+	b, _ := GenerateRandomMap(rand.New(rand.NewSource(42)), 8, 0.8)
+	if !slices.Equal(a, b) {
+		t.Errorf("GenerateRandomMap with the same seed = %q and %q, want the same map", a, b)
+	}
+
+	for _, tt := range []struct {
+		size int
+		p    float64
+	}{{1, 0.8}, {0, 0.8}, {-1, 0.8}, {4, 0}, {4, -0.5}, {4, math.NaN()}} {
+		if board, err := GenerateRandomMap(rng, tt.size, tt.p); err == nil {
+			t.Errorf("GenerateRandomMap(%v, %v) = %q, want error", tt.size, tt.p, board)
+		}
+	}
+}
+
+func TestInvalidAction(t *testing.T) {
+	e, err := New(nil, "4x4", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range []any{4, -1, 1.0} {
+		if _, _, _, truncated, info := e.Step(action); !truncated || info == nil {
+			t.Errorf("Step(%v) = (truncated=%v, info=%v), want truncated with an error", action, truncated, info)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	e, err := New(nil, "4x4", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Reset()
+	want := "\n\x1b[41mS\x1b[0mFFF\nFHFH\nFFFH\nHFFG\n"
+	if got := e.Render(); got != want {
+		t.Errorf("Render after Reset = %q, want %q", got, want)
+	}
+
+	e.Step(down)
+	want = "  (Down)\nSFFF\n\x1b[41mF\x1b[0mHFH\nFFFH\nHFFG\n"
+	if got := e.Render(); got != want {
+		t.Errorf("Render after Step = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,378 @@
+// Package taxi implements the Taxi environment in Go.
+// It is a port of: https://github.com/Farama-Foundation/Gymnasium/blob/main/gymnasium/envs/toy_text/taxi.py
+//
+// The Taxi Problem involves navigating to passengers in a grid world, picking
+// them up and dropping them off at one of four locations.
+//
+// # Description
+// There are four designated pick-up and drop-off locations (Red, Green,
+// Yellow and Blue) in the 5x5 grid world. The taxi starts off at a random
+// square and the passenger at one of the designated locations.
+//
+// The goal is move the taxi to the passenger's location, pick up the
+// passenger, move to the passenger's desired destination, and drop off the
+// passenger. Once the passenger is dropped off, the episode ends.
+//
+// The player receives positive rewards for successfully dropping-off the
+// passenger at the correct location. Negative rewards for incorrect attempts
+// to pick-up/drop-off passenger and for each step where another reward is
+// not received.
+//
+// Map:
+//
+//	+---------+
+//	|R: | : :G|
+//	| : | : : |
+//	| : : : : |
+//	| | : | : |
+//	|Y| : |B: |
+//	+---------+
+//
+// # Action Space
+// The action shape is `(1,)` in the range `{0, 5}` indicating
+// which direction to move the taxi or to pickup/drop off passengers.
+//
+// - 0: Move south (down)
+// - 1: Move north (up)
+// - 2: Move east (right)
+// - 3: Move west (left)
+// - 4: Pickup passenger
+// - 5: Drop off passenger
+//
+// # Observation Space
+// There are 500 discrete states since there are 25 taxi positions, 5 possible
+// locations of the passenger (including the case when the passenger is in the
+// taxi), and 4 destination locations.
+//
+// Destination on the map are represented with the first letter of the color.
+//
+// Passenger locations:
+// - 0: Red
+// - 1: Green
+// - 2: Yellow
+// - 3: Blue
+// - 4: In taxi
+//
+// Destinations:
+// - 0: Red
+// - 1: Green
+// - 2: Yellow
+// - 3: Blue
+//
+// An observation is returned as an `int` that encodes the corresponding
+// state, calculated by `((taxi_row * 5 + taxi_col) * 5 + passenger_location) * 4 + destination`.
+//
+// Note that there are 400 states that can actually be reached during an
+// episode. The missing states correspond to situations in which the passenger
+// is at the same location as their destination, as this typically signals
+// the end of an episode. Four additional states can be observed right after a
+// successful episodes, when both the passenger and the taxi are at the
+// destination. This gives a total of 404 reachable discrete states.
+//
+// # Starting State
+// The initial state is sampled uniformly from the possible states where the
+// passenger is neither at their destination nor inside the taxi. There are
+// 300 possible initial states: 25 taxi positions, 4 passenger locations
+// (excluding inside the taxi) and 3 destinations (excluding the passenger's
+// current location).
+//
+// # Rewards
+// - -1 per step unless other reward is triggered.
+// - +20 delivering passenger.
+// - -10 executing "pickup" and "drop-off" actions illegally.
+//
+// An action that results a noop, like moving into a wall, will incur the time step
+// penalty. Noops can be avoided by sampling the ActionMask returned in Info.
+//
+// # Episode End
+// The episode ends if the following happens:
+//
+// - Termination:
+// 1. The taxi drops off the passenger.
+//
+// - Truncation:
+// 1. The length of the episode is 200.
+//
+// # Information
+//
+// Step and Reset return an Info with the probability of the transition
+// and an ActionMask of the actions that will change the state.
+//
+// # Arguments
+//
+// ```
+// gymnasium.Make("Taxi-v3")
+// ```
+package taxi
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/gmlewis/gep/v2/common"
+	"github.com/gmlewis/gep/v2/gymnasium/envs/toy_text"
+)
+
+const (
+	south   = 0
+	north   = 1
+	east    = 2
+	west    = 3
+	pickup  = 4
+	dropoff = 5
+
+	numRows   = 5
+	numCols   = 5
+	inTaxi    = 4
+	numStates = 500
+
+	// NumActions is the number of actions in the action space.
+	NumActions = 6
+
+	// MaxEpisodeSteps is the number of steps after which gymnasium.Make
+	// truncates an episode by default.
+	MaxEpisodeSteps = 200
+)
+
+var taxiMap = []string{
+	"+---------+",
+	"|R: | : :G|",
+	"| : | : : |",
+	"| : : : : |",
+	"| | : | : |",
+	"|Y| : |B: |",
+	"+---------+",
+}
+
+type loc struct{ row, col int }
+
+// locs are the Red, Green, Yellow and Blue locations.
+var locs = []loc{{0, 0}, {0, 4}, {4, 0}, {4, 3}}
+
+// Info is the additional information returned by Step and Reset.
+type Info struct {
+	// Prob is the probability of the transition.
+	Prob float64
+	// ActionMask has a 1 for each action that will change the state
+	// and a 0 for each action that will not.
+	ActionMask [NumActions]int8
+}
+
+// Environment represents a Taxi environment.
+type Environment struct {
+	s   int
+	rng *rand.Rand
+	// lastAction is the action of the last Step, or -1 after Reset.
+	lastAction int
+}
+
+// New returns a new Taxi environment.
+func New() *Environment {
+	return &Environment{rng: rand.New(rand.NewSource(rand.Int63())), lastAction: -1}
+}
+
+func (e *Environment) ActionSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: NumActions}, nil
+}
+
+func (e *Environment) ObservationSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: numStates}, nil
+}
+
+func (e *Environment) SampleAction(action any) error {
+	switch v := action.(type) {
+	case *int:
+		*v = e.rng.Intn(NumActions)
+	default:
+		return fmt.Errorf("unsupported SampleAction type %T", action)
+	}
+	return nil
+}
+
+func (e *Environment) Close() error { return nil }
+
+// Step performs the provided action and returns the next observation,
+// the reward for this action, and whether this episode is terminated.
+// If the action is invalid, truncated will be true and info is the error.
+// Otherwise info is an Info.
+func (e *Environment) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	actionInt, ok := action.(int)
+	if !ok {
+		info = fmt.Errorf("error: Taxi: invalid action type %T; must be int", action)
+		return obsT(e.s), reward, terminated, true, info
+	}
+
+	if actionInt < 0 || actionInt >= NumActions {
+		info = fmt.Errorf("error: Taxi: invalid action=%v; must be 0 (south), 1 (north), 2 (east), 3 (west), 4 (pickup) or 5 (dropoff)", actionInt)
+		return obsT(e.s), reward, terminated, true, info
+	}
+	e.lastAction = actionInt
+
+	row, col, passIdx, destIdx := Decode(e.s)
+	newRow, newCol, newPassIdx := row, col, passIdx
+	taxiLoc := loc{row, col}
+	reward = -1
+
+	switch actionInt {
+	case south:
+		newRow = min(row+1, numRows-1)
+	case north:
+		newRow = max(row-1, 0)
+	case east:
+		if taxiMap[1+row][2*col+2] == ':' {
+			newCol = min(col+1, numCols-1)
+		}
+	case west:
+		if taxiMap[1+row][2*col] == ':' {
+			newCol = max(col-1, 0)
+		}
+	case pickup:
+		if passIdx < inTaxi && taxiLoc == locs[passIdx] {
+			newPassIdx = inTaxi
+		} else { // passenger not at location
+			reward = -10
+		}
+	case dropoff:
+		if i := locIndex(taxiLoc); taxiLoc == locs[destIdx] && passIdx == inTaxi {
+			newPassIdx = destIdx
+			terminated = true
+			reward = 20
+		} else if i >= 0 && passIdx == inTaxi {
+			newPassIdx = i
+		} else { // dropoff at wrong location
+			reward = -10
+		}
+	}
+
+	e.s = Encode(newRow, newCol, newPassIdx, destIdx)
+	return obsT(e.s), reward, terminated, false, Info{Prob: 1, ActionMask: ActionMask(e.s)}
+}
+
+// Reset resets to a brand new episode.
+func (e *Environment) Reset(opts ...common.ResetOption) (obs common.Obs, info any) {
+	if o := common.NewResetOptions(opts...); o.Seed != nil {
+		e.rng.Seed(*o.Seed)
+	}
+
+	// Choose uniformly among the 300 states where the passenger is
+	// neither in the taxi nor at their destination.
+	passIdx := e.rng.Intn(inTaxi)
+	destIdx := e.rng.Intn(len(locs) - 1)
+	if destIdx >= passIdx {
+		destIdx++
+	}
+	e.s = Encode(e.rng.Intn(numRows), e.rng.Intn(numCols), passIdx, destIdx)
+	e.lastAction = -1
+	return obsT(e.s), Info{Prob: 1, ActionMask: ActionMask(e.s)}
+}
+
+// Render returns an ANSI text frame of the map followed by the last action
+// taken. The taxi is highlighted in yellow when empty and in green with the
+// passenger, the passenger's location is in bold blue and the destination
+// is in magenta.
+func (e *Environment) Render() string {
+	out := make([][]string, len(taxiMap))
+	for i, line := range taxiMap {
+		for _, c := range line {
+			out[i] = append(out[i], string(c))
+		}
+	}
+
+	row, col, passIdx, destIdx := Decode(e.s)
+	taxi := &out[1+row][2*col+1]
+	if passIdx < inTaxi {
+		*taxi = toy_text.Colorize(*taxi, "yellow", false, true)
+		p := locs[passIdx]
+		out[1+p.row][2*p.col+1] = toy_text.Colorize(out[1+p.row][2*p.col+1], "blue", true, false)
+	} else { // passenger in taxi
+		if *taxi == " " {
+			*taxi = "_"
+		}
+		*taxi = toy_text.Colorize(*taxi, "green", false, true)
+	}
+	d := locs[destIdx]
+	out[1+d.row][2*d.col+1] = toy_text.Colorize(out[1+d.row][2*d.col+1], "magenta", false, false)
+
+	var sb strings.Builder
+	for _, line := range out {
+		sb.WriteString(strings.Join(line, "") + "\n")
+	}
+	if e.lastAction >= 0 {
+		fmt.Fprintf(&sb, "  (%v)", []string{"South", "North", "East", "West", "Pickup", "Dropoff"}[e.lastAction])
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// Encode returns the state for the given taxi position, passenger location
+// and destination.
+func Encode(taxiRow, taxiCol, passLoc, destIdx int) int {
+	return ((taxiRow*numCols+taxiCol)*5+passLoc)*4 + destIdx
+}
+
+// Decode returns the taxi position, passenger location and destination
+// for the given state.
+func Decode(s int) (taxiRow, taxiCol, passLoc, destIdx int) {
+	destIdx = s % 4
+	s /= 4
+	passLoc = s % 5
+	s /= 5
+	taxiCol = s % numCols
+	taxiRow = s / numCols
+	return taxiRow, taxiCol, passLoc, destIdx
+}
+
+// ActionMask returns a mask of the actions that will change the given state.
+func ActionMask(s int) (mask [NumActions]int8) {
+	row, col, passLoc, destIdx := Decode(s)
+	taxiLoc := loc{row, col}
+	if row < numRows-1 {
+		mask[south] = 1
+	}
+	if row > 0 {
+		mask[north] = 1
+	}
+	if col < numCols-1 && taxiMap[row+1][2*col+2] == ':' {
+		mask[east] = 1
+	}
+	if col > 0 && taxiMap[row+1][2*col] == ':' {
+		mask[west] = 1
+	}
+	if passLoc < inTaxi && taxiLoc == locs[passLoc] {
+		mask[pickup] = 1
+	}
+	if passLoc == inTaxi && (taxiLoc == locs[destIdx] || locIndex(taxiLoc) >= 0) {
+		mask[dropoff] = 1
+	}
+	return mask
+}
+
+func locIndex(l loc) int {
+	for i, v := range locs {
+		if v == l {
+			return i
+		}
+	}
+	return -1
+}
+
+type obsT int
+
+var _ common.Obs = obsT(0)
+
+func (o obsT) Unmarshal(dst any) error {
+	switch v := dst.(type) {
+	case *int:
+		*v = int(o)
+	case *[]int:
+		if len(*v) == 0 {
+			*v = append(*v, int(o))
+		} else {
+			(*v)[0] = int(o)
+		}
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package taxi
+
+import (
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+func getObs(t *testing.T, obs common.Obs) int {
+	t.Helper()
+	var got int
+	if err := obs.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for s := 0; s < numStates; s++ {
+		row, col, passLoc, destIdx := Decode(s)
+		if got := Encode(row, col, passLoc, destIdx); got != s {
+			t.Fatalf("Encode(Decode(%v)) = %v", s, got)
+		}
+	}
+	if got := Encode(3, 1, 2, 0); got != 328 {
+		t.Errorf("Encode(3, 1, 2, 0) = %v, want 328", got)
+	}
+}
+
+func TestEpisode(t *testing.T) {
+	e := New()
+	// Taxi at (2, 2), passenger at Yellow, destination Green.
+	e.s = Encode(2, 2, 2, 1)
+
+	steps := []struct {
+		action     int
+		wantState  int
+		wantReward float64
+	}{
+		{action: pickup, wantState: Encode(2, 2, 2, 1), wantReward: -10},
+		{action: west, wantState: Encode(2, 1, 2, 1), wantReward: -1},
+		{action: west, wantState: Encode(2, 0, 2, 1), wantReward: -1},
+		{action: south, wantState: Encode(3, 0, 2, 1), wantReward: -1},
+		{action: south, wantState: Encode(4, 0, 2, 1), wantReward: -1},
+		{action: east, wantState: Encode(4, 0, 2, 1), wantReward: -1}, // wall
+		{action: pickup, wantState: Encode(4, 0, inTaxi, 1), wantReward: -1},
+		{action: north, wantState: Encode(3, 0, inTaxi, 1), wantReward: -1},
+		{action: north, wantState: Encode(2, 0, inTaxi, 1), wantReward: -1},
+		{action: east, wantState: Encode(2, 1, inTaxi, 1), wantReward: -1},
+		{action: east, wantState: Encode(2, 2, inTaxi, 1), wantReward: -1},
+		{action: east, wantState: Encode(2, 3, inTaxi, 1), wantReward: -1},
+		{action: east, wantState: Encode(2, 4, inTaxi, 1), wantReward: -1},
+		{action: dropoff, wantState: Encode(2, 4, inTaxi, 1), wantReward: -10},
+		{action: north, wantState: Encode(1, 4, inTaxi, 1), wantReward: -1},
+		{action: north, wantState: Encode(0, 4, inTaxi, 1), wantReward: -1},
+		{action: dropoff, wantState: Encode(0, 4, 1, 1), wantReward: 20},
+	}
+	for i, step := range steps {
+		obs, reward, terminated, truncated, info := e.Step(step.action)
+		last := i == len(steps)-1
+		if got := getObs(t, obs); got != step.wantState || reward != step.wantReward || terminated != last || truncated {
+			t.Errorf("step %v: Step(%v) = (%v, %v, %v, %v), want (%v, %v, %v, false)", i, step.action, got, reward, terminated, truncated, step.wantState, step.wantReward, last)
+		}
+		if want := (Info{Prob: 1, ActionMask: ActionMask(step.wantState)}); info != want {
+			t.Errorf("step %v: info = %+v, want %+v", i, info, want)
+		}
+	}
+}
+
+func TestDropoffAtOtherLocation(t *testing.T) {
+	e := New()
+	// Taxi at Blue with the passenger, destination Red.
+	e.s = Encode(4, 3, inTaxi, 0)
+	obs, reward, terminated, _, _ := e.Step(dropoff)
+	if got, want := getObs(t, obs), Encode(4, 3, 3, 0); got != want || reward != -1 || terminated {
+		t.Errorf("Step(dropoff) = (%v, %v, %v), want (%v, -1, false)", got, reward, terminated, want)
+	}
+}
+
+func TestActionMask(t *testing.T) {
+	tests := []struct {
+		name string
+		s    int
+		want [NumActions]int8
+	}{
+		{name: "at Red with passenger waiting", s: Encode(0, 0, 0, 1), want: [NumActions]int8{1, 0, 1, 0, 1, 0}},
+		{name: "wall to the east", s: Encode(0, 1, 0, 1), want: [NumActions]int8{1, 0, 0, 1, 0, 0}},
+		{name: "at Blue with passenger in taxi", s: Encode(4, 3, inTaxi, 0), want: [NumActions]int8{0, 1, 1, 0, 0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ActionMask(tt.s); got != tt.want {
+				t.Errorf("ActionMask = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	e := New()
+	for i := 0; i < 100; i++ {
+		obs, info := e.Reset()
+		_, _, passLoc, destIdx := Decode(getObs(t, obs))
+		if passLoc == inTaxi || passLoc == destIdx {
+			t.Fatalf("Reset: passenger location %v, destination %v", passLoc, destIdx)
+		}
+		if want := (Info{Prob: 1, ActionMask: ActionMask(e.s)}); info != want {
+			t.Fatalf("Reset info = %+v, want %+v", info, want)
+		}
+	}
+}
+
+func TestInvalidAction(t *testing.T) {
+	e := New()
+	for _, action := range []any{6, -1, 1.0} {
+		if _, _, _, truncated, info := e.Step(action); !truncated || info == nil {
+			t.Errorf("Step(%v) = (truncated=%v, info=%v), want truncated with an error", action, truncated, info)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	e := New()
+	e.Reset()
+	e.s = Encode(2, 2, 2, 1)
+	want := "+---------+\n" +
+		"|R: | : :\x1b[35mG\x1b[0m|\n" +
+		"| : | : : |\n" +
+		"| : :\x1b[43m \x1b[0m: : |\n" +
+		"| | : | : |\n" +
+		"|\x1b[34;1mY\x1b[0m| : |B: |\n" +
+		"+---------+\n" +
+		"\n"
+	if got := e.Render(); got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+
+	// The passenger is in the taxi, which is blocked by a wall.
+	e.s = Encode(0, 2, inTaxi, 3)
+	e.Step(west)
+	want = "+---------+\n" +
+		"|R: |\x1b[42m_\x1b[0m: :G|\n" +
+		"| : | : : |\n" +
+		"| : : : : |\n" +
+		"| | : | : |\n" +
+		"|Y| : |\x1b[35mB\x1b[0m: |\n" +
+		"+---------+\n" +
+		"  (West)\n"
+	if got := e.Render(); got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
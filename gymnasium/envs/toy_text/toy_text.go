@@ -1,2 +1,37 @@
 // Package toy_test provides pure Go implementations of toy_text Gymnasium environments.
 package toy_text
+
+import (
+	"fmt"
+	"strings"
+)
+
+var colorCodes = map[string]int{
+	"gray":    30,
+	"red":     31,
+	"green":   32,
+	"yellow":  33,
+	"blue":    34,
+	"magenta": 35,
+	"cyan":    36,
+	"white":   37,
+	"crimson": 38,
+}
+
+// Colorize returns s wrapped in the ANSI escape codes for the named color,
+// like gymnasium.utils.colorize. highlight sets the background color
+// instead of the foreground color. Unknown colors are ignored.
+func Colorize(s, color string, bold, highlight bool) string {
+	num, ok := colorCodes[color]
+	if !ok {
+		return s
+	}
+	if highlight {
+		num += 10
+	}
+	attrs := []string{fmt.Sprint(num)}
+	if bold {
+		attrs = append(attrs, "1")
+	}
+	return fmt.Sprintf("\x1b[%vm%v\x1b[0m", strings.Join(attrs, ";"), s)
+}
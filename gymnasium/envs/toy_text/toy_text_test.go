@@ -0,0 +1,21 @@
+package toy_text
+
+import "testing"
+
+func TestColorize(t *testing.T) {
+	tests := []struct {
+		color           string
+		bold, highlight bool
+		want            string
+	}{
+		{color: "red", highlight: true, want: "\x1b[41mS\x1b[0m"},
+		{color: "blue", bold: true, want: "\x1b[34;1mS\x1b[0m"},
+		{color: "magenta", want: "\x1b[35mS\x1b[0m"},
+		{color: "unknown", want: "S"},
+	}
+	for _, tt := range tests {
+		if got := Colorize("S", tt.color, tt.bold, tt.highlight); got != tt.want {
+			t.Errorf("Colorize(%q, %v, %v) = %q, want %q", tt.color, tt.bold, tt.highlight, got, tt.want)
+		}
+	}
+}
@@ -2,35 +2,106 @@
 package gymnasium
 
 import (
-	"fmt"
-
 	"github.com/gmlewis/gep/v2/common"
+	"github.com/gmlewis/gep/v2/gymnasium/envs/classic_control/acrobot"
+	"github.com/gmlewis/gep/v2/gymnasium/envs/classic_control/cartpole"
+	"github.com/gmlewis/gep/v2/gymnasium/envs/classic_control/mountaincar"
+	"github.com/gmlewis/gep/v2/gymnasium/envs/classic_control/pendulum"
 	"github.com/gmlewis/gep/v2/gymnasium/envs/toy_text/blackjack"
+	"github.com/gmlewis/gep/v2/gymnasium/envs/toy_text/cliffwalking"
+	"github.com/gmlewis/gep/v2/gymnasium/envs/toy_text/frozenlake"
+	"github.com/gmlewis/gep/v2/gymnasium/envs/toy_text/taxi"
 )
 
 // Environment represents a pure Go training and execution environment.
+//
+// Each environment owns its random number generator, which Reset re-seeds
+// when passed common.WithSeed so that episodes can be replayed.
 type Environment interface {
 	ActionSpace() (*common.Space, error)
 	ObservationSpace() (*common.Space, error)
-	Reset() (common.Obs, any)
+	Reset(opts ...common.ResetOption) (common.Obs, any)
 	SampleAction(action any) error
 	Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any)
 	Close() error
 }
 
-// Make returns a specific gymnasium environment.
-func Make(environment string) (Environment, error) {
-	switch environment {
-	case "Blackjack-v1":
-		return blackjack.New(false, false), nil
-	default:
-		return nil, fmt.Errorf("unknown environment %q", environment)
+func init() {
+	Register("Acrobot-v1", func(kwargs *Kwargs) (Environment, error) {
+		return acrobot.New(), nil
+	}, WithMaxEpisodeSteps(acrobot.MaxEpisodeSteps))
+
+	Register("Blackjack-v1", func(kwargs *Kwargs) (Environment, error) {
+		natural, err := Kwarg(kwargs, "natural", false)
+		if err != nil {
+			return nil, err
+		}
+		sab, err := Kwarg(kwargs, "sab", false)
+		if err != nil {
+			return nil, err
+		}
+		return blackjack.New(natural, sab), nil
+	})
+
+	Register("CartPole-v1", func(kwargs *Kwargs) (Environment, error) {
+		return cartpole.New(), nil
+	}, WithMaxEpisodeSteps(cartpole.MaxEpisodeSteps))
+
+	Register("CliffWalking-v0", func(kwargs *Kwargs) (Environment, error) {
+		return cliffwalking.New(), nil
+	})
+
+	frozenLake := func(kwargs *Kwargs) (Environment, error) {
+		desc, err := Kwarg[[]string](kwargs, "desc", nil)
+		if err != nil {
+			return nil, err
+		}
+		mapName, err := Kwarg(kwargs, "map_name", "4x4")
+		if err != nil {
+			return nil, err
+		}
+		isSlippery, err := Kwarg(kwargs, "is_slippery", true)
+		if err != nil {
+			return nil, err
+		}
+		return frozenlake.New(desc, mapName, isSlippery)
 	}
+	Register("FrozenLake-v1", frozenLake, WithMaxEpisodeSteps(frozenlake.MaxEpisodeSteps))
+	Register("FrozenLake8x8-v1", frozenLake, WithMapName("8x8"), WithMaxEpisodeSteps(200))
+
+	Register("MountainCar-v0", func(kwargs *Kwargs) (Environment, error) {
+		goalVelocity, err := Kwarg(kwargs, "goal_velocity", 0.0)
+		if err != nil {
+			return nil, err
+		}
+		return mountaincar.New(goalVelocity), nil
+	}, WithMaxEpisodeSteps(mountaincar.MaxEpisodeSteps))
+
+	Register("MountainCarContinuous-v0", func(kwargs *Kwargs) (Environment, error) {
+		goalVelocity, err := Kwarg(kwargs, "goal_velocity", 0.0)
+		if err != nil {
+			return nil, err
+		}
+		return mountaincar.NewContinuous(goalVelocity), nil
+	}, WithMaxEpisodeSteps(mountaincar.MaxContinuousEpisodeSteps))
+
+	Register("Pendulum-v1", func(kwargs *Kwargs) (Environment, error) {
+		g, err := Kwarg(kwargs, "g", 10.0)
+		if err != nil {
+			return nil, err
+		}
+		return pendulum.New(g), nil
+	}, WithMaxEpisodeSteps(pendulum.MaxEpisodeSteps))
+
+	Register("Taxi-v3", func(kwargs *Kwargs) (Environment, error) {
+		return taxi.New(), nil
+	}, WithMaxEpisodeSteps(taxi.MaxEpisodeSteps))
 }
 
-// GetSpaces returns the ActionSpace and ObsSpace for an environment.
-func GetSpaces(environment string) (actionSpace, obsSpace *common.Space, err error) {
-	env, err := Make(environment)
+// GetSpaces returns the ActionSpace and ObsSpace for an environment
+// created with the provided options.
+func GetSpaces(environment string, opts ...Option) (actionSpace, obsSpace *common.Space, err error) {
+	env, err := Make(environment, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
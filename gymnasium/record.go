@@ -0,0 +1,101 @@
+package gymnasium
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+// Transition is one line of an episode recording in JSON Lines format.
+// Each episode starts with a Transition of Step 0 holding the observation
+// returned by Reset and no action.
+type Transition struct {
+	Episode    int             `json:"episode"` // starting at 1
+	Step       int             `json:"step"`
+	Obs        json.RawMessage `json:"obs"`
+	Action     json.RawMessage `json:"action,omitempty"`
+	Reward     float64         `json:"reward"`
+	Terminated bool            `json:"terminated"`
+	Truncated  bool            `json:"truncated"`
+	Frame      string          `json:"frame,omitempty"`
+}
+
+// RecordEpisodes writes a Transition for every Reset and Step to a writer
+// in JSON Lines format, for later replay and debugging of evolved policies.
+type RecordEpisodes struct {
+	Environment
+
+	// RecordFrames adds the Render frame of the environment to every
+	// Transition. The environment must implement Renderer.
+	RecordFrames bool
+
+	enc     *json.Encoder
+	episode int
+	step    int
+	err     error
+}
+
+// NewRecordEpisodes returns a wrapper that records the episodes of env to w.
+func NewRecordEpisodes(env Environment, w io.Writer) *RecordEpisodes {
+	return &RecordEpisodes{Environment: env, enc: json.NewEncoder(w)}
+}
+
+// Unwrap returns the wrapped environment.
+func (r *RecordEpisodes) Unwrap() Environment { return r.Environment }
+
+func (r *RecordEpisodes) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	obs, reward, terminated, truncated, info = r.Environment.Step(action)
+	r.step++
+	r.record(Transition{Reward: reward, Terminated: terminated, Truncated: truncated}, obs, action)
+	return obs, reward, terminated, truncated, info
+}
+
+func (r *RecordEpisodes) Reset(opts ...common.ResetOption) (common.Obs, any) {
+	obs, info := r.Environment.Reset(opts...)
+	r.episode++
+	r.step = 0
+	r.record(Transition{}, obs, nil)
+	return obs, info
+}
+
+// Err returns the first error that occurred while recording,
+// after which nothing more is recorded.
+func (r *RecordEpisodes) Err() error { return r.err }
+
+func (r *RecordEpisodes) record(t Transition, obs common.Obs, action any) {
+	if r.err != nil {
+		return
+	}
+	t.Episode, t.Step = r.episode, r.step
+	if t.Obs, r.err = json.Marshal(obs); r.err != nil {
+		return
+	}
+	if action != nil {
+		if t.Action, r.err = json.Marshal(action); r.err != nil {
+			return
+		}
+	}
+	if r.RecordFrames {
+		if t.Frame, r.err = Render(r.Environment); r.err != nil {
+			return
+		}
+	}
+	r.err = r.enc.Encode(t)
+}
+
+// ReadEpisodes reads all the transitions recorded by RecordEpisodes.
+func ReadEpisodes(rd io.Reader) ([]Transition, error) {
+	dec := json.NewDecoder(rd)
+	var result []Transition
+	for {
+		var t Transition
+		if err := dec.Decode(&t); errors.Is(err, io.EOF) {
+			return result, nil
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+}
@@ -0,0 +1,82 @@
+package gymnasium
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecordEpisodes(t *testing.T) {
+	frozenLake, err := Make("FrozenLake-v1", WithIsSlippery(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	env := NewRecordEpisodes(frozenLake, &buf)
+	env.RecordFrames = true
+
+	// The shortest path to the goal (1 = down, 2 = right),
+	// then an episode truncated by an invalid action.
+	env.Reset()
+	for _, action := range []int{2, 2, 1, 1, 1, 2} {
+		env.Step(action)
+	}
+	env.Reset()
+	env.Step(-1)
+	if err := env.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 9 {
+		t.Errorf("recorded %v lines, want 9:\n%v", lines, buf.String())
+	}
+	got, err := ReadEpisodes(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Transition{
+		{Episode: 1, Step: 0, Obs: json.RawMessage("0")},
+		{Episode: 1, Step: 1, Obs: json.RawMessage("1"), Action: json.RawMessage("2")},
+		{Episode: 1, Step: 2, Obs: json.RawMessage("2"), Action: json.RawMessage("2")},
+		{Episode: 1, Step: 3, Obs: json.RawMessage("6"), Action: json.RawMessage("1")},
+		{Episode: 1, Step: 4, Obs: json.RawMessage("10"), Action: json.RawMessage("1")},
+		{Episode: 1, Step: 5, Obs: json.RawMessage("14"), Action: json.RawMessage("1")},
+		{Episode: 1, Step: 6, Obs: json.RawMessage("15"), Action: json.RawMessage("2"), Reward: 1, Terminated: true},
+		{Episode: 2, Step: 0, Obs: json.RawMessage("0")},
+		{Episode: 2, Step: 1, Obs: json.RawMessage("0"), Action: json.RawMessage("-1"), Truncated: true},
+	}
+	if diff := cmp.Diff(want, got, cmp.FilterPath(func(p cmp.Path) bool {
+		return p.Last().String() == ".Frame"
+	}, cmp.Ignore())); diff != "" {
+		t.Errorf("transitions mismatch (-want +got):\n%v", diff)
+	}
+	if want := "  (Right)\nSFFF\nFHFH\nFFFH\nHFF\x1b[41mG\x1b[0m\n"; got[6].Frame != want {
+		t.Errorf("frame = %q, want %q", got[6].Frame, want)
+	}
+}
+
+func TestRecordEpisodesBox(t *testing.T) {
+	var buf bytes.Buffer
+	env := NewRecordEpisodes(&scriptEnv{obs: [][]float64{{0.5, 1}, {0.25, 2}}, rewards: []float64{3}}, &buf)
+	env.Reset()
+	env.Step([]float64{1, -1})
+	if err := env.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"episode":1,"step":0,"obs":[0.5,1],"reward":0,"terminated":false,"truncated":false}
+{"episode":1,"step":1,"obs":[0.25,2],"action":[1,-1],"reward":3,"terminated":true,"truncated":false}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("recording = %v, want %v", got, want)
+	}
+
+	// Frames can only be recorded for a Renderer.
+	env.RecordFrames = true
+	env.Reset()
+	if err := env.Err(); err == nil {
+		t.Error("RecordFrames of a non-Renderer: Err = nil, want error")
+	}
+}
@@ -0,0 +1,160 @@
+package gymnasium
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// Factory creates a new environment from its keyword arguments.
+// It reads its arguments with Kwarg.
+type Factory func(kwargs *Kwargs) (Environment, error)
+
+type spec struct {
+	factory Factory
+	opts    []Option
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*spec{}
+)
+
+// Register makes an environment available to Make by the provided id.
+// The optional defaults are applied before the options passed to Make,
+// e.g. to set the default keyword arguments or max episode steps.
+//
+// Register is meant to be called from the init function of a package
+// providing environments. It panics if it is called twice with the
+// same id or if factory is nil.
+func Register(id string, factory Factory, defaults ...Option) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		panic("gymnasium: Register factory is nil")
+	}
+	if _, dup := registry[id]; dup {
+		panic("gymnasium: Register called twice for environment " + id)
+	}
+	registry[id] = &spec{factory: factory, opts: defaults}
+}
+
+// Registered returns a sorted list of the ids of the registered environments.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	ids := make([]string, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Kwargs holds the keyword arguments passed to a Factory.
+type Kwargs struct {
+	values map[string]any
+	used   map[string]bool
+}
+
+// Kwarg returns the keyword argument called name, or defaultValue
+// if it was not provided. It returns an error if the argument
+// does not have type T.
+func Kwarg[T any](kwargs *Kwargs, name string, defaultValue T) (T, error) {
+	kwargs.used[name] = true
+	v, ok := kwargs.values[name]
+	if !ok {
+		return defaultValue, nil
+	}
+	t, ok := v.(T)
+	if !ok {
+		return defaultValue, fmt.Errorf("keyword argument %q has type %T, want %T", name, v, defaultValue)
+	}
+	return t, nil
+}
+
+// Option represents a keyword argument that can modify the environment
+// created by Make.
+type Option func(o *makeOptions)
+
+type makeOptions struct {
+	kwargs          map[string]any
+	maxEpisodeSteps int
+}
+
+// WithKwarg sets the keyword argument called name to value.
+// It can be used to pass arguments to third-party environments.
+func WithKwarg(name string, value any) Option {
+	return func(o *makeOptions) {
+		o.kwargs[name] = value
+	}
+}
+
+// WithMaxEpisodeSteps truncates each episode after maxEpisodeSteps steps.
+// A value <= 0 disables truncation.
+func WithMaxEpisodeSteps(maxEpisodeSteps int) Option {
+	return func(o *makeOptions) {
+		o.maxEpisodeSteps = maxEpisodeSteps
+	}
+}
+
+// WithNatural sets the Blackjack `natural` keyword argument: whether to give
+// an additional reward for starting with a natural blackjack.
+func WithNatural(natural bool) Option { return WithKwarg("natural", natural) }
+
+// WithSAB sets the Blackjack `sab` keyword argument: whether to follow the
+// exact rules outlined in the book by Sutton and Barto.
+func WithSAB(sab bool) Option { return WithKwarg("sab", sab) }
+
+// WithMapName sets the FrozenLake `map_name` keyword argument ("4x4" or "8x8").
+// An empty name generates a random 8x8 map.
+func WithMapName(mapName string) Option { return WithKwarg("map_name", mapName) }
+
+// WithDesc sets the FrozenLake `desc` keyword argument: a custom map
+// description that overrides the map name.
+func WithDesc(desc []string) Option { return WithKwarg("desc", desc) }
+
+// WithIsSlippery sets the FrozenLake `is_slippery` keyword argument.
+func WithIsSlippery(isSlippery bool) Option { return WithKwarg("is_slippery", isSlippery) }
+
+// WithGoalVelocity sets the MountainCar `goal_velocity` keyword argument.
+func WithGoalVelocity(goalVelocity float64) Option {
+	return WithKwarg("goal_velocity", goalVelocity)
+}
+
+// WithGravity sets the Pendulum `g` keyword argument: the acceleration of gravity.
+func WithGravity(g float64) Option { return WithKwarg("g", g) }
+
+// Make returns a new instance of the registered environment id,
+// modified by the provided options.
+func Make(id string, opts ...Option) (Environment, error) {
+	registryMu.RLock()
+	s, ok := registry[id]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown environment %q", id)
+	}
+
+	o := &makeOptions{kwargs: map[string]any{}}
+	for _, f := range slices.Concat(s.opts, opts) {
+		f(o)
+	}
+
+	kwargs := &Kwargs{values: o.kwargs, used: map[string]bool{}}
+	env, err := s.factory(kwargs)
+	if err != nil {
+		return nil, fmt.Errorf("Make(%q): %w", id, err)
+	}
+	for name := range kwargs.values {
+		if !kwargs.used[name] {
+			env.Close()
+			return nil, fmt.Errorf("Make(%q): unexpected keyword argument %q", id, name)
+		}
+	}
+
+	if o.maxEpisodeSteps > 0 {
+		env = NewTimeLimit(env, o.maxEpisodeSteps)
+	}
+	return env, nil
+}
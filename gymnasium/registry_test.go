@@ -0,0 +1,263 @@
+package gymnasium
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+func TestRegistered(t *testing.T) {
+	got := Registered()
+	for _, id := range []string{
+		"Acrobot-v1",
+		"Blackjack-v1",
+		"CartPole-v1",
+		"CliffWalking-v0",
+		"FrozenLake-v1",
+		"FrozenLake8x8-v1",
+		"MountainCar-v0",
+		"MountainCarContinuous-v0",
+		"Pendulum-v1",
+		"Taxi-v3",
+	} {
+		if !slices.Contains(got, id) {
+			t.Errorf("Registered() = %v, missing %q", got, id)
+		}
+		if _, _, err := GetSpaces(id); err != nil {
+			t.Errorf("GetSpaces(%q): %v", id, err)
+		}
+	}
+	if !slices.IsSorted(got) {
+		t.Errorf("Registered() = %v, want sorted", got)
+	}
+}
+
+func TestMakeKwargs(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		opts  []Option
+		wantN int
+	}{
+		{name: "default map", id: "FrozenLake-v1", wantN: 16},
+		{name: "map name", id: "FrozenLake-v1", opts: []Option{WithMapName("8x8")}, wantN: 64},
+		{name: "registered map name", id: "FrozenLake8x8-v1", wantN: 64},
+		{name: "custom map", id: "FrozenLake-v1", opts: []Option{WithDesc([]string{"SFF", "FHG"}), WithIsSlippery(false)}, wantN: 6},
+		{name: "Blackjack", id: "Blackjack-v1", opts: []Option{WithNatural(true), WithSAB(true)}},
+		{name: "MountainCar", id: "MountainCar-v0", opts: []Option{WithGoalVelocity(0.01)}},
+		{name: "Pendulum", id: "Pendulum-v1", opts: []Option{WithGravity(9.81)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, obsSpace, err := GetSpaces(tt.id, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantN != 0 && obsSpace.N != tt.wantN {
+				t.Errorf("ObservationSpace.N = %v, want %v", obsSpace.N, tt.wantN)
+			}
+		})
+	}
+}
+
+func TestMakeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		opts []Option
+		want string
+	}{
+		{name: "unknown environment", id: "NoSuchEnv-v0", want: `unknown environment "NoSuchEnv-v0"`},
+		{name: "unexpected kwarg", id: "CartPole-v1", opts: []Option{WithNatural(true)}, want: `unexpected keyword argument "natural"`},
+		{name: "wrong kwarg type", id: "Pendulum-v1", opts: []Option{WithKwarg("g", 9)}, want: `keyword argument "g" has type int, want float64`},
+		{name: "factory error", id: "FrozenLake-v1", opts: []Option{WithMapName("5x5")}, want: `unknown map name "5x5"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Make(tt.id, tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Make = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// nopEnv is a third-party environment whose episodes never end.
+type nopEnv struct {
+	label string
+}
+
+func (e *nopEnv) ActionSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: 1}, nil
+}
+
+func (e *nopEnv) ObservationSpace() (*common.Space, error) {
+	return &common.Space{Type: "Discrete", N: 1}, nil
+}
+
+func (e *nopEnv) Reset(opts ...common.ResetOption) (common.Obs, any) { return nil, nil }
+func (e *nopEnv) SampleAction(action any) error                      { return nil }
+func (e *nopEnv) Close() error                                       { return nil }
+
+func (e *nopEnv) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	return nil, 0, false, false, nil
+}
+
+func init() {
+	Register("Nop-v0", func(kwargs *Kwargs) (Environment, error) {
+		label, err := Kwarg(kwargs, "label", "default")
+		if err != nil {
+			return nil, err
+		}
+		return &nopEnv{label: label}, nil
+	}, WithMaxEpisodeSteps(10))
+}
+
+func TestRegister(t *testing.T) {
+	env, err := Make("Nop-v0", WithKwarg("label", "custom"), WithMaxEpisodeSteps(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := env.(*nopEnv).label; got != "custom" {
+		t.Errorf("label = %q, want %q", got, "custom")
+	}
+
+	for _, tt := range []struct {
+		name    string
+		id      string
+		factory Factory
+	}{
+		{name: "duplicate id", id: "Nop-v0", factory: func(*Kwargs) (Environment, error) { return &nopEnv{}, nil }},
+		{name: "nil factory", id: "Nil-v0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic", tt.id)
+				}
+			}()
+			Register(tt.id, tt.factory)
+		})
+	}
+}
+
+func TestMaxEpisodeSteps(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		opts []Option
+		want int // 0 means never truncated
+	}{
+		{name: "registered default", id: "Nop-v0", want: 10},
+		{name: "override", id: "Nop-v0", opts: []Option{WithMaxEpisodeSteps(5)}, want: 5},
+		{name: "disabled", id: "Nop-v0", opts: []Option{WithMaxEpisodeSteps(0)}},
+		{name: "FrozenLake8x8", id: "FrozenLake8x8-v1", opts: []Option{WithIsSlippery(false)}, want: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := Make(tt.id, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer env.Close()
+
+			// Run two episodes to check that Reset restarts the count.
+			for episode := 0; episode < 2; episode++ {
+				env.Reset()
+				var steps int
+				for steps < 300 {
+					steps++
+					// Action 0 is "left" in FrozenLake which stays at the start.
+					_, _, terminated, truncated, _ := env.Step(0)
+					if terminated {
+						t.Fatalf("step %v: terminated", steps)
+					}
+					if truncated {
+						break
+					}
+				}
+				if tt.want == 0 {
+					tt.want = 300
+				}
+				if steps != tt.want {
+					t.Errorf("episode %v: truncated after %v steps, want %v", episode, steps, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// trajectory plays numSteps steps of env with actions sampled from
+// the environment, and returns the observations and rewards.
+func trajectory(t *testing.T, env Environment, numSteps int, opts ...common.ResetOption) []string {
+	t.Helper()
+	space, err := env.ActionSpace()
+	if err != nil {
+		t.Fatal(err)
+	}
+	obs, _ := env.Reset(opts...)
+	result := []string{fmt.Sprint(obs)}
+	for range numSteps {
+		var action any
+		switch space.Type {
+		case "Box":
+			var a []float64
+			if err := env.SampleAction(&a); err != nil {
+				t.Fatal(err)
+			}
+			action = a
+		default:
+			var a int
+			if err := env.SampleAction(&a); err != nil {
+				t.Fatal(err)
+			}
+			action = a
+		}
+		obs, reward, terminated, truncated, _ := env.Step(action)
+		result = append(result, fmt.Sprint(obs, reward))
+		if terminated || truncated {
+			obs, _ = env.Reset()
+			result = append(result, fmt.Sprint(obs))
+		}
+	}
+	return result
+}
+
+func TestSeed(t *testing.T) {
+	for _, id := range Registered() {
+		t.Run(id, func(t *testing.T) {
+			env1, err := Make(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			env2, err := Make(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Identical seeds give identical trajectories, including
+			// the episodes after a Reset without a seed.
+			want := trajectory(t, env1, 300, common.WithSeed(42))
+			trajectory(t, env2, 10) // advance the random number generator
+			got := trajectory(t, env2, 300, common.WithSeed(42))
+			if !slices.Equal(got, want) {
+				t.Errorf("trajectories with the same seed differ:\n%v\n%v", got, want)
+			}
+		})
+	}
+
+	// Blackjack deals different hands for different seeds.
+	env, err := Make("Blackjack-v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := trajectory(t, env, 100, common.WithSeed(1)), trajectory(t, env, 100, common.WithSeed(2)); slices.Equal(a, b) {
+		t.Errorf("trajectories with different seeds are identical: %v", a)
+	}
+}
@@ -0,0 +1,26 @@
+package gymnasium
+
+import "fmt"
+
+// Renderer is implemented by environments that can render their
+// current state as an ANSI text frame, like Gymnasium's "ansi"
+// render mode. The toy_text environments implement Renderer.
+type Renderer interface {
+	Render() string
+}
+
+// Render returns an ANSI text frame of the current state of env,
+// unwrapping any wrappers that do not implement Renderer themselves.
+// It returns an error if the environment does not implement Renderer.
+func Render(env Environment) (string, error) {
+	for {
+		if r, ok := env.(Renderer); ok {
+			return r.Render(), nil
+		}
+		w, ok := env.(interface{ Unwrap() Environment })
+		if !ok {
+			return "", fmt.Errorf("environment %T does not support rendering", env)
+		}
+		env = w.Unwrap()
+	}
+}
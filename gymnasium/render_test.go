@@ -0,0 +1,48 @@
+package gymnasium
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	// Wrappers are unwrapped.
+	frozenLake, err := Make("FrozenLake-v1", WithIsSlippery(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frozenLake.Reset()
+	got, err := Render(NewScaleReward(frozenLake, 2))
+	if want := "\n\x1b[41mS\x1b[0mFFF\nFHFH\nFFFH\nHFFG\n"; err != nil || got != want {
+		t.Errorf("Render(FrozenLake-v1) = %q, %v, want %q", got, err, want)
+	}
+
+	// The dealer's face down card is revealed at the end of the episode.
+	blackjack, err := Make("Blackjack-v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	blackjack.Reset()
+	if got, err := Render(blackjack); err != nil || !strings.HasPrefix(got, "Dealer: ") || !strings.Contains(got, " ??\nPlayer: ") {
+		t.Errorf("Render(Blackjack-v1) = %q, %v, want a hidden dealer card", got, err)
+	}
+	blackjack.Step(0) // stick
+	if got, err := Render(blackjack); err != nil || strings.Contains(got, "??") {
+		t.Errorf("Render(Blackjack-v1) = %q, %v, want the dealer's hand revealed", got, err)
+	}
+
+	for _, id := range []string{"CliffWalking-v0", "Taxi-v3"} {
+		env, err := Make(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env.Reset()
+		if got, err := Render(env); err != nil || got == "" {
+			t.Errorf("Render(%v) = %q, %v, want a frame", id, got, err)
+		}
+	}
+
+	if _, err := Render(&nopEnv{}); err == nil {
+		t.Error("Render(nopEnv) = nil error, want error")
+	}
+}
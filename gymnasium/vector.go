@@ -0,0 +1,230 @@
+package gymnasium
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+// VectorEnv runs a batch of environment copies in lockstep.
+//
+// Finished sub-environments are automatically reset: the Step after a
+// sub-environment returns terminated or truncated ignores its action, resets
+// it, and returns its initial observation and info with a reward of 0 and
+// terminated and truncated set to false. This matches the default "next
+// step" autoreset mode of Gymnasium vector environments.
+type VectorEnv interface {
+	// NumEnvs returns the number of sub-environments.
+	NumEnvs() int
+	// SingleActionSpace returns the action space of each sub-environment.
+	SingleActionSpace() (*common.Space, error)
+	// SingleObservationSpace returns the observation space of each sub-environment.
+	SingleObservationSpace() (*common.Space, error)
+	// Reset resets all sub-environments with the provided options, except
+	// that a seed s re-seeds sub-environment #i with s+i like Gymnasium.
+	Reset(opts ...common.ResetOption) (obs []common.Obs, infos []any)
+	// Step steps each sub-environment with its own action. A missing action is
+	// passed as nil and so reported as invalid by the sub-environment.
+	Step(actions []any) (obs []common.Obs, rewards []float64, terminated, truncated []bool, infos []any)
+	// Close closes all sub-environments.
+	Close() error
+}
+
+// MakeEnvs returns numEnvs new instances of the registered environment id,
+// e.g. to create a VectorEnv.
+func MakeEnvs(id string, numEnvs int, opts ...Option) ([]Environment, error) {
+	envs := make([]Environment, 0, numEnvs)
+	for range numEnvs {
+		env, err := Make(id, opts...)
+		if err != nil {
+			for _, env := range envs {
+				env.Close()
+			}
+			return nil, err
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+// vectorEnvs holds the state shared by the VectorEnv implementations.
+type vectorEnvs struct {
+	envs      []Environment
+	autoreset []bool
+
+	obs        []common.Obs
+	rewards    []float64
+	terminated []bool
+	truncated  []bool
+	infos      []any
+}
+
+func newVectorEnvs(envs []Environment) vectorEnvs {
+	if len(envs) == 0 {
+		panic("gymnasium: VectorEnv requires at least one environment")
+	}
+	return vectorEnvs{envs: envs, autoreset: make([]bool, len(envs))}
+}
+
+func (v *vectorEnvs) NumEnvs() int { return len(v.envs) }
+
+func (v *vectorEnvs) SingleActionSpace() (*common.Space, error) {
+	return v.envs[0].ActionSpace()
+}
+
+func (v *vectorEnvs) SingleObservationSpace() (*common.Space, error) {
+	return v.envs[0].ObservationSpace()
+}
+
+func (v *vectorEnvs) Close() error {
+	var errs []error
+	for i, env := range v.envs {
+		if err := env.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("env[%v]: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// envResetOptions returns the reset options of each sub-environment.
+func (v *vectorEnvs) envResetOptions(opts []common.ResetOption) [][]common.ResetOption {
+	result := make([][]common.ResetOption, len(v.envs))
+	seed := common.NewResetOptions(opts...).Seed
+	for i := range result {
+		result[i] = opts
+		if seed != nil {
+			result[i] = append(slices.Clip(opts), common.WithSeed(*seed+int64(i)))
+		}
+	}
+	return result
+}
+
+// newBatch allocates the results of a Reset or Step, which are returned
+// to the caller and so must not be reused.
+func (v *vectorEnvs) newBatch() {
+	n := len(v.envs)
+	v.obs = make([]common.Obs, n)
+	v.rewards = make([]float64, n)
+	v.terminated = make([]bool, n)
+	v.truncated = make([]bool, n)
+	v.infos = make([]any, n)
+}
+
+func (v *vectorEnvs) resetEnv(i int, opts ...common.ResetOption) {
+	v.obs[i], v.infos[i] = v.envs[i].Reset(opts...)
+	v.autoreset[i] = false
+}
+
+func (v *vectorEnvs) stepEnv(i int, actions []any) {
+	if v.autoreset[i] {
+		v.resetEnv(i)
+		return
+	}
+	var action any
+	if i < len(actions) {
+		action = actions[i]
+	}
+	v.obs[i], v.rewards[i], v.terminated[i], v.truncated[i], v.infos[i] = v.envs[i].Step(action)
+	v.autoreset[i] = v.terminated[i] || v.truncated[i]
+}
+
+// SyncVectorEnv steps its sub-environments serially.
+type SyncVectorEnv struct {
+	vectorEnvs
+}
+
+var _ VectorEnv = &SyncVectorEnv{}
+
+// NewSyncVectorEnv returns a VectorEnv that steps envs one after another.
+// It panics if envs is empty.
+func NewSyncVectorEnv(envs []Environment) *SyncVectorEnv {
+	return &SyncVectorEnv{vectorEnvs: newVectorEnvs(envs)}
+}
+
+func (s *SyncVectorEnv) Reset(opts ...common.ResetOption) (obs []common.Obs, infos []any) {
+	s.newBatch()
+	envOpts := s.envResetOptions(opts)
+	for i := range s.envs {
+		s.resetEnv(i, envOpts[i]...)
+	}
+	return s.obs, s.infos
+}
+
+func (s *SyncVectorEnv) Step(actions []any) (obs []common.Obs, rewards []float64, terminated, truncated []bool, infos []any) {
+	s.newBatch()
+	for i := range s.envs {
+		s.stepEnv(i, actions)
+	}
+	return s.obs, s.rewards, s.terminated, s.truncated, s.infos
+}
+
+// AsyncVectorEnv steps its sub-environments concurrently
+// with a pool of worker goroutines.
+type AsyncVectorEnv struct {
+	vectorEnvs
+	jobs    chan func()
+	workers sync.WaitGroup
+}
+
+var _ VectorEnv = &AsyncVectorEnv{}
+
+// NewAsyncVectorEnv returns a VectorEnv that steps envs concurrently using
+// numWorkers goroutines, or runtime.GOMAXPROCS(0) goroutines if numWorkers <= 0.
+// Each sub-environment is only used by one goroutine at a time.
+// Close must be called to stop the workers. It panics if envs is empty.
+func NewAsyncVectorEnv(envs []Environment, numWorkers int) *AsyncVectorEnv {
+	if numWorkers <= 0 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+	a := &AsyncVectorEnv{
+		vectorEnvs: newVectorEnvs(envs),
+		jobs:       make(chan func()),
+	}
+	a.workers.Add(numWorkers)
+	for range numWorkers {
+		go func() {
+			defer a.workers.Done()
+			for job := range a.jobs {
+				job()
+			}
+		}()
+	}
+	return a
+}
+
+// run calls f for every sub-environment index and waits for all calls to finish.
+func (a *AsyncVectorEnv) run(f func(i int)) {
+	var wg sync.WaitGroup
+	wg.Add(len(a.envs))
+	for i := range a.envs {
+		a.jobs <- func() {
+			defer wg.Done()
+			f(i)
+		}
+	}
+	wg.Wait()
+}
+
+func (a *AsyncVectorEnv) Reset(opts ...common.ResetOption) (obs []common.Obs, infos []any) {
+	a.newBatch()
+	envOpts := a.envResetOptions(opts)
+	a.run(func(i int) { a.resetEnv(i, envOpts[i]...) })
+	return a.obs, a.infos
+}
+
+func (a *AsyncVectorEnv) Step(actions []any) (obs []common.Obs, rewards []float64, terminated, truncated []bool, infos []any) {
+	a.newBatch()
+	a.run(func(i int) { a.stepEnv(i, actions) })
+	return a.obs, a.rewards, a.terminated, a.truncated, a.infos
+}
+
+// Close stops the workers and closes all sub-environments.
+func (a *AsyncVectorEnv) Close() error {
+	close(a.jobs)
+	a.workers.Wait()
+	return a.vectorEnvs.Close()
+}
@@ -0,0 +1,139 @@
+package gymnasium
+
+import (
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVectorEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		make func(envs []Environment) VectorEnv
+	}{
+		{name: "sync", make: func(envs []Environment) VectorEnv { return NewSyncVectorEnv(envs) }},
+		{name: "async", make: func(envs []Environment) VectorEnv { return NewAsyncVectorEnv(envs, 2) }},
+		{name: "async default workers", make: func(envs []Environment) VectorEnv { return NewAsyncVectorEnv(envs, 0) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Episodes of 1, 2 and 3 steps.
+			script := []*scriptEnv{
+				{obs: [][]float64{{0}, {1}}, rewards: []float64{1}},
+				{obs: [][]float64{{0}, {1}, {2}}, rewards: []float64{1, 2}},
+				{obs: [][]float64{{0}, {1}, {2}, {3}}, rewards: []float64{1, 2, 3}},
+			}
+			envs := make([]Environment, len(script))
+			for i, env := range script {
+				envs[i] = env
+			}
+			venv := tt.make(envs)
+			defer venv.Close()
+
+			if got := venv.NumEnvs(); got != 3 {
+				t.Errorf("NumEnvs = %v, want 3", got)
+			}
+			if space, err := venv.SingleActionSpace(); err != nil || space.Type != "Box" {
+				t.Errorf("SingleActionSpace = %+v, %v, want Box", space, err)
+			}
+			if space, err := venv.SingleObservationSpace(); err != nil || space.Type != "Box" {
+				t.Errorf("SingleObservationSpace = %+v, %v, want Box", space, err)
+			}
+
+			obs, infos := venv.Reset()
+			if len(obs) != 3 || len(infos) != 3 {
+				t.Fatalf("Reset returned %v observations and %v infos, want 3", len(obs), len(infos))
+			}
+
+			type stepT struct {
+				Obs        []float64
+				Rewards    []float64
+				Terminated []bool
+			}
+			var got []stepT
+			for step := 0; step < 4; step++ {
+				obs, rewards, terminated, truncated, infos := venv.Step([]any{step, step})
+				if len(infos) != 3 || truncated[0] || truncated[1] || truncated[2] {
+					t.Fatalf("step %v: infos = %v, truncated = %v", step, infos, truncated)
+				}
+				s := stepT{Rewards: rewards, Terminated: terminated}
+				for _, o := range obs {
+					s.Obs = append(s.Obs, unmarshalFloats(t, o)...)
+				}
+				got = append(got, s)
+			}
+
+			// The step after a termination resets the sub-environment.
+			want := []stepT{
+				{Obs: []float64{1, 1, 1}, Rewards: []float64{1, 1, 1}, Terminated: []bool{true, false, false}},
+				{Obs: []float64{0, 2, 2}, Rewards: []float64{0, 2, 2}, Terminated: []bool{false, true, false}},
+				{Obs: []float64{1, 0, 3}, Rewards: []float64{1, 0, 3}, Terminated: []bool{true, false, true}},
+				{Obs: []float64{0, 1, 0}, Rewards: []float64{0, 1, 0}, Terminated: []bool{false, false, false}},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("steps mismatch (-want +got):\n%v", diff)
+			}
+
+			// Actions are not passed on autoreset and missing actions are nil.
+			wantActions := [][]any{{0, 2}, {0, 1, 3}, {nil, nil, nil}}
+			for i, env := range script {
+				if diff := cmp.Diff(wantActions[i], env.actions); diff != "" {
+					t.Errorf("env[%v] actions mismatch (-want +got):\n%v", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestVectorEnvSeed(t *testing.T) {
+	envs, err := MakeEnvs("Taxi-v3", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	venv := NewSyncVectorEnv(envs)
+	defer venv.Close()
+
+	// Sub-environment #i is seeded with 10+i.
+	obs, _ := venv.Reset(common.WithSeed(10))
+	env, err := Make("Taxi-v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, o := range obs {
+		want, _ := env.Reset(common.WithSeed(10 + int64(i)))
+		if o != want {
+			t.Errorf("obs[%v] = %v, want %v", i, o, want)
+		}
+	}
+}
+
+func TestMakeEnvs(t *testing.T) {
+	envs, err := MakeEnvs("FrozenLake-v1", 4, WithIsSlippery(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	venv := NewAsyncVectorEnv(envs, 0)
+	defer venv.Close()
+
+	venv.Reset()
+	// Action 0 is "left" in FrozenLake which stays at the start
+	// until the registered limit of 100 steps.
+	for step := 1; step <= 100; step++ {
+		obs, _, _, truncated, _ := venv.Step([]any{0, 0, 0, 0})
+		for i, o := range obs {
+			var state int
+			if err := o.Unmarshal(&state); err != nil || state != 0 {
+				t.Fatalf("step %v: env[%v] state = %v, %v, want 0", step, i, state, err)
+			}
+			if truncated[i] != (step == 100) {
+				t.Fatalf("step %v: env[%v] truncated = %v", step, i, truncated[i])
+			}
+		}
+	}
+
+	if _, err := MakeEnvs("NoSuchEnv-v0", 2); err == nil {
+		t.Error("MakeEnvs(unknown) = nil error, want error")
+	}
+}
@@ -0,0 +1,428 @@
+package gymnasium
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gmlewis/gep/v2/common"
+)
+
+// Each wrapper embeds the Environment it wraps, so the wrapped
+// environment is always available from its Environment field
+// and from its Unwrap method.
+
+// TimeLimit truncates episodes after a maximum number of steps.
+type TimeLimit struct {
+	Environment
+	maxEpisodeSteps int
+	elapsedSteps    int
+}
+
+// NewTimeLimit returns a wrapper that truncates each episode
+// after maxEpisodeSteps steps.
+func NewTimeLimit(env Environment, maxEpisodeSteps int) *TimeLimit {
+	return &TimeLimit{Environment: env, maxEpisodeSteps: maxEpisodeSteps}
+}
+
+// Unwrap returns the wrapped environment.
+func (t *TimeLimit) Unwrap() Environment { return t.Environment }
+
+func (t *TimeLimit) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	obs, reward, terminated, truncated, info = t.Environment.Step(action)
+	t.elapsedSteps++
+	return obs, reward, terminated, truncated || t.elapsedSteps >= t.maxEpisodeSteps, info
+}
+
+func (t *TimeLimit) Reset(opts ...common.ResetOption) (common.Obs, any) {
+	t.elapsedSteps = 0
+	return t.Environment.Reset(opts...)
+}
+
+// EpisodeStatistics are the statistics of a single finished episode.
+type EpisodeStatistics struct {
+	// Return is the cumulative reward of the episode.
+	Return float64
+	// Length is the number of steps in the episode.
+	Length int
+	// Duration is the elapsed time since the episode was reset.
+	Duration time.Duration
+}
+
+// RecordEpisodeStatistics keeps track of the cumulative rewards
+// and lengths of the finished episodes.
+type RecordEpisodeStatistics struct {
+	Environment
+
+	// EpisodeCount is the total number of finished episodes.
+	EpisodeCount int
+	// Episodes holds the statistics of the most recent finished episodes,
+	// oldest first.
+	Episodes []EpisodeStatistics
+
+	bufferLength  int
+	episodeReturn float64
+	episodeLength int
+	start         time.Time
+}
+
+// NewRecordEpisodeStatistics returns a wrapper that records the statistics
+// of the last bufferLength finished episodes.
+func NewRecordEpisodeStatistics(env Environment, bufferLength int) *RecordEpisodeStatistics {
+	return &RecordEpisodeStatistics{Environment: env, bufferLength: bufferLength, start: time.Now()}
+}
+
+// Unwrap returns the wrapped environment.
+func (r *RecordEpisodeStatistics) Unwrap() Environment { return r.Environment }
+
+func (r *RecordEpisodeStatistics) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	obs, reward, terminated, truncated, info = r.Environment.Step(action)
+	r.episodeReturn += reward
+	r.episodeLength++
+	if terminated || truncated {
+		r.Episodes = append(r.Episodes, EpisodeStatistics{
+			Return:   r.episodeReturn,
+			Length:   r.episodeLength,
+			Duration: time.Since(r.start),
+		})
+		if len(r.Episodes) > r.bufferLength {
+			r.Episodes = r.Episodes[len(r.Episodes)-r.bufferLength:]
+		}
+		r.EpisodeCount++
+		r.start = time.Now()
+	}
+	return obs, reward, terminated, truncated, info
+}
+
+func (r *RecordEpisodeStatistics) Reset(opts ...common.ResetOption) (common.Obs, any) {
+	r.episodeReturn = 0
+	r.episodeLength = 0
+	r.start = time.Now()
+	return r.Environment.Reset(opts...)
+}
+
+// vecObs is an observation vector. err, if set, is the error
+// that occurred while computing the vector.
+type vecObs struct {
+	values []float64
+	err    error
+}
+
+var _ common.Obs = vecObs{}
+
+func (o vecObs) Unmarshal(dst any) error {
+	if o.err != nil {
+		return o.err
+	}
+	switch v := dst.(type) {
+	case *[]float64:
+		*v = append((*v)[:0], o.values...)
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the observation as a JSON array, e.g. for RecordEpisodes.
+func (o vecObs) MarshalJSON() ([]byte, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	return json.Marshal(o.values)
+}
+
+func toFloats(obs common.Obs) ([]float64, error) {
+	var values []float64
+	if err := obs.Unmarshal(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// FlattenObservation flattens Discrete and Tuple observations into
+// one-hot encoded Box observations, and Box observations into vectors,
+// so that all observations can be unmarshaled into a `*[]float64`.
+type FlattenObservation struct {
+	Environment
+	space *common.Space
+	inner *common.Space // the wrapped observation space
+}
+
+// NewFlattenObservation returns a wrapper that flattens the observations
+// of env. Tuple observation spaces must only contain Discrete subspaces.
+func NewFlattenObservation(env Environment) (*FlattenObservation, error) {
+	obs, err := env.ObservationSpace()
+	if err != nil {
+		return nil, err
+	}
+
+	var low, high []float64
+	switch obs.Type {
+	case "Box":
+		low, high = obs.Low, obs.High
+	case "Discrete":
+		low, high = make([]float64, obs.N), ones(obs.N)
+	case "Tuple":
+		for i, sub := range obs.Subspaces {
+			if sub.Type != "Discrete" {
+				return nil, fmt.Errorf("FlattenObservation: Tuple subspace[%v] type %v not supported", i, sub.Type)
+			}
+			low = append(low, make([]float64, sub.N)...)
+			high = append(high, ones(sub.N)...)
+		}
+	default:
+		return nil, fmt.Errorf("FlattenObservation: ObservationSpace type %v not supported", obs.Type)
+	}
+
+	return &FlattenObservation{
+		Environment: env,
+		space:       &common.Space{Type: "Box", Low: low, High: high, Shape: []int{len(low)}},
+		inner:       obs,
+	}, nil
+}
+
+func ones(n int) []float64 {
+	result := make([]float64, n)
+	for i := range result {
+		result[i] = 1
+	}
+	return result
+}
+
+// Unwrap returns the wrapped environment.
+func (f *FlattenObservation) Unwrap() Environment { return f.Environment }
+
+func (f *FlattenObservation) ObservationSpace() (*common.Space, error) {
+	return f.space, nil
+}
+
+func (f *FlattenObservation) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	obs, reward, terminated, truncated, info = f.Environment.Step(action)
+	return f.flatten(obs), reward, terminated, truncated, info
+}
+
+func (f *FlattenObservation) Reset(opts ...common.ResetOption) (common.Obs, any) {
+	obs, info := f.Environment.Reset(opts...)
+	return f.flatten(obs), info
+}
+
+// flatten one-hot encodes Discrete values; values out of range
+// leave their encoding all zeros.
+func (f *FlattenObservation) flatten(obs common.Obs) common.Obs {
+	if obs == nil {
+		return nil
+	}
+
+	switch f.inner.Type {
+	case "Box":
+		values, err := toFloats(obs)
+		return vecObs{values: values, err: err}
+	case "Discrete":
+		ints := make([]int, 1)
+		if err := obs.Unmarshal(&ints); err != nil {
+			return vecObs{err: err}
+		}
+		return vecObs{values: oneHot(nil, ints[0], f.inner.N)}
+	default: // "Tuple"
+		ints := make([]int, len(f.inner.Subspaces))
+		if err := obs.Unmarshal(&ints); err != nil {
+			return vecObs{err: err}
+		}
+		values := make([]float64, 0, len(f.space.Low))
+		for i, sub := range f.inner.Subspaces {
+			values = oneHot(values, ints[i], sub.N)
+		}
+		return vecObs{values: values}
+	}
+}
+
+func oneHot(dst []float64, v, n int) []float64 {
+	start := len(dst)
+	dst = append(dst, make([]float64, n)...)
+	if v >= 0 && v < n {
+		dst[start+v] = 1
+	}
+	return dst
+}
+
+// TransformReward applies a function to every reward.
+type TransformReward struct {
+	Environment
+	f func(reward float64) float64
+}
+
+// NewTransformReward returns a wrapper that applies f to every reward.
+func NewTransformReward(env Environment, f func(reward float64) float64) *TransformReward {
+	return &TransformReward{Environment: env, f: f}
+}
+
+// NewScaleReward returns a wrapper that multiplies every reward by scale.
+func NewScaleReward(env Environment, scale float64) *TransformReward {
+	return NewTransformReward(env, func(reward float64) float64 { return reward * scale })
+}
+
+// NewClipReward returns a wrapper that clips every reward to [minReward, maxReward].
+func NewClipReward(env Environment, minReward, maxReward float64) *TransformReward {
+	return NewTransformReward(env, func(reward float64) float64 {
+		return math.Min(math.Max(reward, minReward), maxReward)
+	})
+}
+
+// Unwrap returns the wrapped environment.
+func (t *TransformReward) Unwrap() Environment { return t.Environment }
+
+func (t *TransformReward) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	obs, reward, terminated, truncated, info = t.Environment.Step(action)
+	return obs, t.f(reward), terminated, truncated, info
+}
+
+// ClipAction clips continuous `[]float64` actions to the bounds of
+// a Box action space, so the agent may return any values.
+type ClipAction struct {
+	Environment
+	low, high []float64
+}
+
+// NewClipAction returns a wrapper that clips the actions of env,
+// which must have a Box action space.
+func NewClipAction(env Environment) (*ClipAction, error) {
+	space, err := env.ActionSpace()
+	if err != nil {
+		return nil, err
+	}
+	if space.Type != "Box" {
+		return nil, fmt.Errorf("ClipAction: ActionSpace type %v not supported", space.Type)
+	}
+	return &ClipAction{Environment: env, low: space.Low, high: space.High}, nil
+}
+
+// Unwrap returns the wrapped environment.
+func (c *ClipAction) Unwrap() Environment { return c.Environment }
+
+// ActionSpace returns an unbounded Box space with the same shape
+// as the wrapped action space.
+func (c *ClipAction) ActionSpace() (*common.Space, error) {
+	space, err := c.Environment.ActionSpace()
+	if err != nil {
+		return nil, err
+	}
+	low, high := make([]float64, len(c.low)), make([]float64, len(c.high))
+	for i := range low {
+		low[i], high[i] = math.Inf(-1), math.Inf(1)
+	}
+	return &common.Space{Type: "Box", Low: low, High: high, Shape: space.Shape}, nil
+}
+
+// Step clips the action if it is a `[]float64` of the right length,
+// without modifying it, and passes any other action through unchanged.
+func (c *ClipAction) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	if values, ok := action.([]float64); ok && len(values) == len(c.low) {
+		clipped := make([]float64, len(values))
+		for i, v := range values {
+			clipped[i] = math.Min(math.Max(v, c.low[i]), c.high[i])
+		}
+		action = clipped
+	}
+	return c.Environment.Step(action)
+}
+
+const normalizeEpsilon = 1e-8
+
+// NormalizeObservation normalizes `[]float64` observations to approximately
+// zero mean and unit variance using a running mean and variance.
+// Wrap Discrete or Tuple environments with FlattenObservation first.
+type NormalizeObservation struct {
+	Environment
+
+	// UpdateRunningMean, if false, freezes the running mean and variance,
+	// e.g. to evaluate an agent. It defaults to true.
+	UpdateRunningMean bool
+
+	rms *runningMeanStd
+}
+
+// NewNormalizeObservation returns a wrapper that normalizes the observations of env.
+func NewNormalizeObservation(env Environment) *NormalizeObservation {
+	return &NormalizeObservation{Environment: env, UpdateRunningMean: true}
+}
+
+// Unwrap returns the wrapped environment.
+func (n *NormalizeObservation) Unwrap() Environment { return n.Environment }
+
+func (n *NormalizeObservation) ObservationSpace() (*common.Space, error) {
+	space, err := n.Environment.ObservationSpace()
+	if err != nil {
+		return nil, err
+	}
+	if space.Type != "Box" {
+		return nil, fmt.Errorf("NormalizeObservation: ObservationSpace type %v not supported; use FlattenObservation", space.Type)
+	}
+	low, high := make([]float64, len(space.Low)), make([]float64, len(space.High))
+	for i := range low {
+		low[i], high[i] = math.Inf(-1), math.Inf(1)
+	}
+	return &common.Space{Type: "Box", Low: low, High: high, Shape: space.Shape}, nil
+}
+
+func (n *NormalizeObservation) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	obs, reward, terminated, truncated, info = n.Environment.Step(action)
+	return n.normalize(obs), reward, terminated, truncated, info
+}
+
+func (n *NormalizeObservation) Reset(opts ...common.ResetOption) (common.Obs, any) {
+	obs, info := n.Environment.Reset(opts...)
+	return n.normalize(obs), info
+}
+
+func (n *NormalizeObservation) normalize(obs common.Obs) common.Obs {
+	if obs == nil {
+		return nil
+	}
+	values, err := toFloats(obs)
+	if err != nil {
+		return vecObs{err: err}
+	}
+
+	if n.rms == nil {
+		n.rms = newRunningMeanStd(len(values))
+	}
+	if len(values) != len(n.rms.mean) {
+		return vecObs{err: fmt.Errorf("NormalizeObservation: got observation of length %v, want %v", len(values), len(n.rms.mean))}
+	}
+	if n.UpdateRunningMean {
+		n.rms.update(values)
+	}
+
+	for i, v := range values {
+		values[i] = (v - n.rms.mean[i]) / math.Sqrt(n.rms.variance[i]+normalizeEpsilon)
+	}
+	return vecObs{values: values}
+}
+
+// runningMeanStd tracks the mean and variance of a stream of vectors.
+// See: https://en.wikipedia.org/wiki/Algorithms_for_calculating_variance#Parallel_algorithm
+type runningMeanStd struct {
+	mean     []float64
+	variance []float64
+	count    float64
+}
+
+func newRunningMeanStd(size int) *runningMeanStd {
+	return &runningMeanStd{
+		mean:     make([]float64, size),
+		variance: ones(size),
+		count:    1e-4,
+	}
+}
+
+func (r *runningMeanStd) update(x []float64) {
+	totCount := r.count + 1
+	for i, v := range x {
+		delta := v - r.mean[i]
+		r.mean[i] += delta / totCount
+		m2 := r.variance[i]*r.count + delta*delta*r.count/totCount
+		r.variance[i] = m2 / totCount
+	}
+	r.count = totCount
+}
@@ -0,0 +1,235 @@
+package gymnasium
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/common"
+	"github.com/google/go-cmp/cmp"
+)
+
+// scriptEnv is a Box environment that replays a fixed list of
+// observations and rewards, and terminates at the end of the list.
+type scriptEnv struct {
+	obs     [][]float64
+	rewards []float64
+	step    int
+	actions []any
+}
+
+func (e *scriptEnv) ActionSpace() (*common.Space, error) {
+	return &common.Space{Type: "Box", Low: []float64{-1, 0}, High: []float64{1, 2}, Shape: []int{2}}, nil
+}
+
+func (e *scriptEnv) ObservationSpace() (*common.Space, error) {
+	n := len(e.obs[0])
+	return &common.Space{Type: "Box", Low: make([]float64, n), High: ones(n), Shape: []int{n}}, nil
+}
+
+func (e *scriptEnv) Reset(opts ...common.ResetOption) (common.Obs, any) {
+	e.step = 0
+	return vecObs{values: e.obs[0]}, nil
+}
+
+func (e *scriptEnv) SampleAction(action any) error { return nil }
+func (e *scriptEnv) Close() error                  { return nil }
+
+func (e *scriptEnv) Step(action any) (obs common.Obs, reward float64, terminated bool, truncated bool, info any) {
+	e.actions = append(e.actions, action)
+	reward = e.rewards[e.step]
+	e.step++
+	return vecObs{values: e.obs[e.step]}, reward, e.step == len(e.rewards), false, nil
+}
+
+func unmarshalFloats(t *testing.T, obs common.Obs) []float64 {
+	t.Helper()
+	var got []float64
+	if err := obs.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestTimeLimit(t *testing.T) {
+	env := NewTimeLimit(&scriptEnv{obs: make([][]float64, 11), rewards: make([]float64, 10)}, 3)
+	for episode := 0; episode < 2; episode++ {
+		env.Reset()
+		for i := 1; i <= 3; i++ {
+			if _, _, _, truncated, _ := env.Step(nil); truncated != (i == 3) {
+				t.Fatalf("episode %v, step %v: truncated = %v", episode, i, truncated)
+			}
+		}
+	}
+}
+
+func TestRecordEpisodeStatistics(t *testing.T) {
+	env := NewRecordEpisodeStatistics(&scriptEnv{obs: make([][]float64, 4), rewards: []float64{1, 2, 3}}, 2)
+	for episode := 0; episode < 3; episode++ {
+		env.Reset()
+		for {
+			if _, _, terminated, _, _ := env.Step(nil); terminated {
+				break
+			}
+		}
+	}
+
+	if env.EpisodeCount != 3 || len(env.Episodes) != 2 {
+		t.Fatalf("EpisodeCount = %v, len(Episodes) = %v, want 3 and 2", env.EpisodeCount, len(env.Episodes))
+	}
+	for _, ep := range env.Episodes {
+		if ep.Return != 6 || ep.Length != 3 || ep.Duration < 0 {
+			t.Errorf("episode = %+v, want Return 6 and Length 3", ep)
+		}
+	}
+}
+
+func TestFlattenObservation(t *testing.T) {
+	// Blackjack: Tuple(Discrete(32), Discrete(11), Discrete(2)).
+	blackjack, err := Make("Blackjack-v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := NewFlattenObservation(blackjack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	space, _ := env.ObservationSpace()
+	if space.Type != "Box" || len(space.Low) != 45 || space.Shape[0] != 45 {
+		t.Errorf("ObservationSpace = %+v, want Box of shape (45,)", space)
+	}
+
+	obs, _ := env.Reset()
+	got := unmarshalFloats(t, obs)
+	var orig []int
+	for i, v := range got {
+		if v == 1 {
+			orig = append(orig, i)
+		}
+	}
+	if len(got) != 45 || len(orig) != 3 || orig[0] >= 32 || orig[1] < 32 || orig[1] >= 43 || orig[2] < 43 {
+		t.Errorf("flattened obs = %v, want three one-hot encodings", got)
+	}
+
+	// FrozenLake: Discrete(16).
+	frozenLake, err := Make("FrozenLake-v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = NewFlattenObservation(frozenLake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obs, _ = env.Reset()
+	want := make([]float64, 16)
+	want[0] = 1
+	if diff := cmp.Diff(want, unmarshalFloats(t, obs)); diff != "" {
+		t.Errorf("flattened obs mismatch (-want +got):\n%v", diff)
+	}
+
+	// Box observations are unchanged.
+	env, err = NewFlattenObservation(&scriptEnv{obs: [][]float64{{0.5, 0.25}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obs, _ = env.Reset()
+	if diff := cmp.Diff([]float64{0.5, 0.25}, unmarshalFloats(t, obs)); diff != "" {
+		t.Errorf("flattened obs mismatch (-want +got):\n%v", diff)
+	}
+
+	if _, err := NewFlattenObservation(&nopEnv{}); err != nil {
+		t.Errorf("NewFlattenObservation(Discrete) = %v", err)
+	}
+}
+
+func TestRewardWrappers(t *testing.T) {
+	rewards := []float64{-3, 0.5, 10}
+	tests := []struct {
+		name string
+		wrap func(env Environment) Environment
+		want []float64
+	}{
+		{name: "scale", wrap: func(env Environment) Environment { return NewScaleReward(env, 0.1) }, want: []float64{-0.30000000000000004, 0.05, 1}},
+		{name: "clip", wrap: func(env Environment) Environment { return NewClipReward(env, -1, 1) }, want: []float64{-1, 0.5, 1}},
+		{name: "transform", wrap: func(env Environment) Environment {
+			return NewTransformReward(env, math.Abs)
+		}, want: []float64{3, 0.5, 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := tt.wrap(&scriptEnv{obs: make([][]float64, 4), rewards: rewards})
+			env.Reset()
+			var got []float64
+			for range rewards {
+				_, reward, _, _, _ := env.Step(nil)
+				got = append(got, reward)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("rewards mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestClipAction(t *testing.T) {
+	inner := &scriptEnv{obs: make([][]float64, 4), rewards: make([]float64, 3)}
+	env, err := NewClipAction(inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	space, _ := env.ActionSpace()
+	if !math.IsInf(space.Low[0], -1) || !math.IsInf(space.High[1], 1) || space.Shape[0] != 2 {
+		t.Errorf("ActionSpace = %+v, want unbounded Box of shape (2,)", space)
+	}
+
+	action := []float64{-5, 5}
+	env.Reset()
+	env.Step(action)
+	env.Step([]float64{0.5, 1})
+	env.Step(1)
+	want := []any{[]float64{-1, 2}, []float64{0.5, 1}, 1}
+	if diff := cmp.Diff(want, inner.actions); diff != "" {
+		t.Errorf("actions mismatch (-want +got):\n%v", diff)
+	}
+	if action[0] != -5 || action[1] != 5 {
+		t.Errorf("ClipAction modified the action: %v", action)
+	}
+
+	if _, err := NewClipAction(&nopEnv{}); err == nil {
+		t.Error("NewClipAction(Discrete) = nil error, want error")
+	}
+}
+
+func TestNormalizeObservation(t *testing.T) {
+	env := NewNormalizeObservation(&scriptEnv{obs: [][]float64{{1}, {3}, {-2}, {0}}, rewards: make([]float64, 3)})
+	space, err := env.ObservationSpace()
+	if err != nil || !math.IsInf(space.Low[0], -1) || !math.IsInf(space.High[0], 1) {
+		t.Errorf("ObservationSpace = %+v, %v, want unbounded Box", space, err)
+	}
+
+	// Reference values from Gymnasium's RunningMeanStd.
+	obs, _ := env.Reset()
+	got := unmarshalFloats(t, obs)
+	obs, _, _, _, _ = env.Step(nil)
+	got = append(got, unmarshalFloats(t, obs)...)
+	obs, _, _, _, _ = env.Step(nil)
+	got = append(got, unmarshalFloats(t, obs)...)
+	want := []float64{0.007070714249638547, 1.0000000049980002, -1.2977747827745523}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("normalized obs = %v, want %v", got, want)
+		}
+	}
+
+	// Freezing the statistics.
+	env.UpdateRunningMean = false
+	mean, variance := env.rms.mean[0], env.rms.variance[0]
+	env.Step(nil)
+	if env.rms.mean[0] != mean || env.rms.variance[0] != variance {
+		t.Errorf("running mean updated while frozen")
+	}
+
+	if _, err := NewNormalizeObservation(&nopEnv{}).ObservationSpace(); err == nil {
+		t.Error("NormalizeObservation(Discrete).ObservationSpace = nil error, want error")
+	}
+}
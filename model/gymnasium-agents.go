@@ -8,6 +8,7 @@ import (
 	"cmp"
 	"fmt"
 	"log"
+	"math"
 	"sort"
 
 	"github.com/gmlewis/gep/v2/common"
@@ -36,6 +37,7 @@ type GymnasiumAgents struct {
 	// options
 	appendEpisodeSteps bool
 	debug              bool
+	funcType           functions.FuncType
 	headSize           int
 	numConstants       int
 	numIndividuals     int
@@ -105,78 +107,87 @@ func NewGymnasiumAgents(actionSpace, obsSpace *common.Space, opts ...GymnasiumAg
 }
 
 func (ga *GymnasiumAgents) newIndividuals() ([]*genome.Genome, error) {
-	numGenes := 1
-	switch ga.ActionSpace.Type {
-	case "Discrete":
-		// N=2 means that the output can have two values: 0, 1
-	case "Tuple":
-		numGenes = len(ga.ActionSpace.Subspaces)
-	// case "MultiBinary":
-	// case "MultiDiscrete":
-	// case "Box":
-	default:
-		return nil, fmt.Errorf("ActionSpace type %v not yet implemented", ga.ActionSpace.Type)
+	// One gene per action element, e.g. N=2 for a Discrete action
+	// space means that the output can have two values: 0, 1
+	numGenes, err := spaceLen(ga.ActionSpace)
+	if err != nil {
+		return nil, fmt.Errorf("ActionSpace %w", err)
+	}
+	numTerminals, err := spaceLen(ga.ObsSpace)
+	if err != nil {
+		return nil, fmt.Errorf("ObservationSpace %w", err)
+	}
+	if ga.appendEpisodeSteps {
+		numTerminals++
 	}
 
-	// var genes []*gene.Gene
-	switch ga.ObsSpace.Type {
-	case "Discrete":
-		funcWeights := gene.AllSymbolsEqualWeights(functions.Int)
-		// for i := 0; i < numGenes; i++ {
-		// 	genes = append(genes, gene.RandomNew(headSize, tailSize, 1, numConstants, funcs, functions.Int))
-		// }
-		// o.Individuals = append(o.Individuals, genome.New(genes, "tuple"))
-		numTerminals := 1
-		if ga.appendEpisodeSteps {
-			numTerminals++
-		}
-		gen := New(
-			funcWeights,
-			functions.Int,
-			ga.numIndividuals,
-			ga.headSize,
-			numGenes,
-			numTerminals,
-			ga.numConstants,
-			"tuple",
-			nil,
-			ga.debug)
-		return gen.Individuals, nil
+	ga.funcType = functions.Int
+	if ga.ActionSpace.Type == "Box" || ga.ObsSpace.Type == "Box" {
+		ga.funcType = functions.Float64
+	}
 
+	gen := New(
+		gene.AllSymbolsEqualWeights(ga.funcType),
+		ga.funcType,
+		ga.numIndividuals,
+		ga.headSize,
+		numGenes,
+		numTerminals,
+		ga.numConstants,
+		"tuple",
+		nil,
+		ga.debug)
+	return gen.Individuals, nil
+}
+
+// spaceLen returns the number of elements of a space, which is
+// the number of genes for an action space and the number of
+// terminals for an observation space.
+func spaceLen(space *common.Space) (int, error) {
+	switch space.Type {
+	case "Discrete":
+		return 1, nil
 	case "Tuple":
-		funcType := functions.Int
-		funcWeights := gene.AllSymbolsEqualWeights(functions.Int)
-		numTerminals := len(ga.ObsSpace.Subspaces)
-		if ga.appendEpisodeSteps {
-			numTerminals++
+		for i, subspace := range space.Subspaces {
+			if subspace.Type != "Discrete" {
+				return 0, fmt.Errorf("type Tuple with subspace[%v] type %v not yet implemented", i, subspace.Type)
+			}
 		}
-		gen := New(
-			funcWeights,
-			funcType,
-			ga.numIndividuals,
-			ga.headSize,
-			numGenes,
-			numTerminals,
-			ga.numConstants,
-			"tuple",
-			nil,
-			ga.debug)
-		return gen.Individuals, nil
-
-	// case "MultiBinary":
-	// case "MultiDiscrete":
-	// case "Box":
+		return len(space.Subspaces), nil
+	case "MultiBinary":
+		return space.N, nil
+	case "MultiDiscrete":
+		return len(space.Low), nil
+	case "Box":
+		if len(space.Low) > 0 {
+			return len(space.Low), nil
+		}
+		n := 1
+		for _, v := range space.Shape {
+			n *= v
+		}
+		return n, nil
 	default:
-		return nil, fmt.Errorf("ObservationSpace type %v not yet implemented", ga.ObsSpace.Type)
+		return 0, fmt.Errorf("type %v not yet implemented", space.Type)
 	}
 }
 
 // EvaluateAgent runs the GEP model for a single individual
 // and returns an action from an observation
-// by populating the passed-in reference.
+// by populating the passed-in reference, which must be an `*int` for
+// Discrete action spaces, an `*[]int` for Tuple, MultiDiscrete and
+// MultiBinary action spaces, and an `*[]float64` for Box action spaces.
 //
 // During an episode, only Individual #agentIdx is evaluated.
 func (ga *GymnasiumAgents) EvaluateAgent(agentIdx, episodeSteps int, obs common.Obs, action any) error {
+	if ga.funcType == functions.Float64 {
+		observations, err := ga.processFloatObservations(episodeSteps, obs)
+		if err != nil {
+			return err
+		}
+		return ga.setFloatAction(ga.Individuals[agentIdx].EvalMathTuple(observations), action)
+	}
+
 	observations, err := ga.processObservations(episodeSteps, obs)
 	if err != nil {
 		return err
@@ -189,13 +200,15 @@ func (ga *GymnasiumAgents) EvaluateAgent(agentIdx, episodeSteps int, obs common.
 	switch v := action.(type) {
 	case *[]int:
 		for i, val := range *v {
-			(*v)[i] = clamp(val, 0, ga.ActionSpace.Subspaces[i].N-1)
+			low, high := ga.actionBounds(i)
+			(*v)[i] = clamp(val, low, high)
 		}
 	case *int:
 		before := *v
-		*v = clamp(*v, 0, ga.ActionSpace.N-1)
+		low, high := ga.actionBounds(0)
+		*v = clamp(*v, low, high)
 		if ga.debug {
-			log.Printf("EvaluateAgent(agentIdx=%v, obs=%+v)=%v => clamp(0,%v) => %v", agentIdx, observations, before, ga.ActionSpace.N-1, *v)
+			log.Printf("EvaluateAgent(agentIdx=%v, obs=%+v)=%v => clamp(%v,%v) => %v", agentIdx, observations, before, low, high, *v)
 		}
 	default:
 		return fmt.Errorf("agent.Evaluate: action type '%T' not yet supported", v)
@@ -203,6 +216,124 @@ func (ga *GymnasiumAgents) EvaluateAgent(agentIdx, episodeSteps int, obs common.
 	return nil
 }
 
+// setFloatAction populates the action from the outputs of a functions.Float64
+// genome. Box actions are scaled from [-1, 1] to [Low, High] and clipped
+// (or passed through when the Box has no bounds), and integer actions are
+// rounded and clamped.
+func (ga *GymnasiumAgents) setFloatAction(outputs []float64, action any) error {
+	if ga.ActionSpace.Type == "Box" {
+		v, ok := action.(*[]float64)
+		if !ok {
+			return fmt.Errorf("agent.Evaluate: action type '%T' not supported for Box action spaces", action)
+		}
+		*v = (*v)[:0]
+		for i, x := range outputs {
+			low, high := ga.boxBounds(i)
+			*v = append(*v, scaleAction(x, low, high))
+		}
+		return nil
+	}
+
+	switch v := action.(type) {
+	case *[]int:
+		*v = (*v)[:0]
+		for i, x := range outputs {
+			low, high := ga.actionBounds(i)
+			*v = append(*v, roundClamp(x, low, high))
+		}
+	case *int:
+		low, high := ga.actionBounds(0)
+		*v = roundClamp(outputs[0], low, high)
+	default:
+		return fmt.Errorf("agent.Evaluate: action type '%T' not yet supported", v)
+	}
+	return nil
+}
+
+// boxBounds returns the bounds of element i of a Box action, which are
+// unbounded when the space is only described by its Shape.
+func (ga *GymnasiumAgents) boxBounds(i int) (low, high float64) {
+	low, high = math.Inf(-1), math.Inf(1)
+	if i < len(ga.ActionSpace.Low) {
+		low = ga.ActionSpace.Low[i]
+	}
+	if i < len(ga.ActionSpace.High) {
+		high = ga.ActionSpace.High[i]
+	}
+	return low, high
+}
+
+// actionBounds returns the bounds of element i of an integer action.
+func (ga *GymnasiumAgents) actionBounds(i int) (low, high int) {
+	switch ga.ActionSpace.Type {
+	case "Tuple":
+		return 0, ga.ActionSpace.Subspaces[i].N - 1
+	case "MultiDiscrete":
+		return int(ga.ActionSpace.Low[i]), int(ga.ActionSpace.High[i])
+	case "MultiBinary":
+		return 0, 1
+	default:
+		return 0, ga.ActionSpace.N - 1
+	}
+}
+
+// scaleAction scales x from [-1, 1] to [low, high], clipping x to [-1, 1].
+// If the bounds are unbounded (infinite or math.MaxFloat32), x is only
+// clipped to them.
+func scaleAction(x, low, high float64) float64 {
+	if math.IsNaN(x) {
+		x = 0
+	}
+	if math.Abs(low) >= math.MaxFloat32 || math.Abs(high) >= math.MaxFloat32 {
+		return clamp(x, low, high)
+	}
+	return low + (clamp(x, -1, 1)+1)/2*(high-low)
+}
+
+// roundClamp rounds x to the nearest integer in [low, high].
+func roundClamp(x float64, low, high int) int {
+	if math.IsNaN(x) {
+		return low
+	}
+	return int(clamp(math.Round(x), float64(low), float64(high)))
+}
+
+// EvaluateAgents runs the GEP model for a whole batch of observations,
+// such as those of a gymnasium.VectorEnv, and returns the actions
+// ready to be passed to its Step method.
+//
+// Individual #i is evaluated with obs[i] after episodeSteps[i] steps.
+func (ga *GymnasiumAgents) EvaluateAgents(episodeSteps []int, obs []common.Obs) ([]any, error) {
+	if len(episodeSteps) != len(obs) || len(obs) > len(ga.Individuals) {
+		return nil, fmt.Errorf("EvaluateAgents: got %v episodeSteps and %v observations for %v individuals", len(episodeSteps), len(obs), len(ga.Individuals))
+	}
+
+	actions := make([]any, len(obs))
+	for agentIdx := range obs {
+		switch ga.ActionSpace.Type {
+		case "Box":
+			var action []float64
+			if err := ga.EvaluateAgent(agentIdx, episodeSteps[agentIdx], obs[agentIdx], &action); err != nil {
+				return nil, err
+			}
+			actions[agentIdx] = action
+		case "Tuple", "MultiDiscrete", "MultiBinary":
+			var action []int
+			if err := ga.EvaluateAgent(agentIdx, episodeSteps[agentIdx], obs[agentIdx], &action); err != nil {
+				return nil, err
+			}
+			actions[agentIdx] = action
+		default:
+			var action int
+			if err := ga.EvaluateAgent(agentIdx, episodeSteps[agentIdx], obs[agentIdx], &action); err != nil {
+				return nil, err
+			}
+			actions[agentIdx] = action
+		}
+	}
+	return actions, nil
+}
+
 // RewardAgent rewards a single agent after evaluation.
 // reward can be any float64 but the range -1000 <= reward <= 1000 works nicely.
 func (ga *GymnasiumAgents) RewardAgent(agentIdx int, reward float64) {
@@ -244,8 +375,13 @@ func (ga *GymnasiumAgents) Evolve() error {
 	return nil
 }
 
+// processObservations returns the observations of an integer
+// observation space as the inputs of a functions.Int genome.
 func (ga *GymnasiumAgents) processObservations(episodeSteps int, obs common.Obs) ([]int, error) {
-	resultLen := len(ga.ObsSpace.Subspaces)
+	resultLen, err := spaceLen(ga.ObsSpace)
+	if err != nil {
+		return nil, err
+	}
 	if ga.appendEpisodeSteps {
 		resultLen++
 	}
@@ -262,6 +398,36 @@ func (ga *GymnasiumAgents) processObservations(episodeSteps int, obs common.Obs)
 	return result, nil
 }
 
+// processFloatObservations returns the observations as the inputs
+// of a functions.Float64 genome.
+func (ga *GymnasiumAgents) processFloatObservations(episodeSteps int, obs common.Obs) ([]float64, error) {
+	if ga.ObsSpace.Type != "Box" {
+		observations, err := ga.processObservations(episodeSteps, obs)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]float64, len(observations))
+		for i, v := range observations {
+			result[i] = float64(v)
+		}
+		return result, nil
+	}
+
+	var result []float64
+	if err := obs.Unmarshal(&result); err != nil {
+		return nil, err
+	}
+	if n, _ := spaceLen(ga.ObsSpace); len(result) != n {
+		return nil, fmt.Errorf("got %v observations, want %v", len(result), n)
+	}
+
+	if ga.appendEpisodeSteps {
+		result = append(result, float64(episodeSteps))
+	}
+
+	return result, nil
+}
+
 func clamp[T cmp.Ordered](v, min, max T) T {
 	if v < min {
 		return min
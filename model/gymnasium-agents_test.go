@@ -2,10 +2,12 @@ package model
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"testing"
 
 	"github.com/gmlewis/gep/v2/common"
+	gym "github.com/gmlewis/gep/v2/gymnasium"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -23,6 +25,18 @@ func (o obsT) Unmarshal(dst any) error {
 	return nil
 }
 
+type discreteObsT int
+
+func (o discreteObsT) Unmarshal(dst any) error {
+	switch v := dst.(type) {
+	case *[]int:
+		(*v)[0] = int(o)
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
+
 func TestNewGymnasiumAgents(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -84,6 +98,110 @@ func TestNewGymnasiumAgents(t *testing.T) {
 	}
 }
 
+func TestGymnasiumAgentsDiscreteObs(t *testing.T) {
+	for _, environment := range []string{"CliffWalking-v0", "FrozenLake-v1", "Taxi-v3"} {
+		t.Run(environment, func(t *testing.T) {
+			actionSpace, obsSpace, err := gym.GetSpaces(environment)
+			if err != nil {
+				t.Fatal(err)
+			}
+			agents, err := NewGymnasiumAgents(actionSpace, obsSpace, WithAppendEpisodeSteps())
+			if err != nil {
+				t.Fatal(err)
+			}
+			env, err := gym.Make(environment)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for agentNum := 0; agentNum < defaultNumIndividuals; agentNum++ {
+				obs, _ := env.Reset()
+				var totalReward float64
+				for episodeStep := 0; episodeStep < 20; episodeStep++ {
+					var action int
+					if err := agents.EvaluateAgent(agentNum, episodeStep, obs, &action); err != nil {
+						t.Fatal(err)
+					}
+					if action < 0 || action >= actionSpace.N {
+						t.Fatalf("agent.Evaluate returned bad action: %v, want 0<=#<%v", action, actionSpace.N)
+					}
+
+					var reward float64
+					var terminated, truncated bool
+					var info any
+					obs, reward, terminated, truncated, info = env.Step(action)
+					if truncated {
+						t.Fatalf("unexpected truncation: info=%v", info)
+					}
+					totalReward += reward
+					if terminated {
+						break
+					}
+				}
+				agents.RewardAgent(agentNum, totalReward)
+			}
+
+			if err := agents.Evolve(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestGymnasiumAgentsVectorEnv(t *testing.T) {
+	const environment = "Blackjack-v1"
+	actionSpace, obsSpace, err := gym.GetSpaces(environment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agents, err := NewGymnasiumAgents(actionSpace, obsSpace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envs, err := gym.MakeEnvs(environment, defaultNumIndividuals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	venv := gym.NewAsyncVectorEnv(envs, 0)
+	defer venv.Close()
+
+	obs, _ := venv.Reset()
+	episodeSteps := make([]int, defaultNumIndividuals)
+	totalRewards := make([]float64, defaultNumIndividuals)
+	for step := 0; step < 20; step++ {
+		actions, err := agents.EvaluateAgents(episodeSteps, obs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, action := range actions {
+			if a, ok := action.(int); !ok || a < 0 || a >= actionSpace.N {
+				t.Fatalf("actions[%v] = %#v, want 0<=#<%v", i, action, actionSpace.N)
+			}
+		}
+
+		var rewards []float64
+		var terminated []bool
+		obs, rewards, terminated, _, _ = venv.Step(actions)
+		for i := range obs {
+			totalRewards[i] += rewards[i]
+			episodeSteps[i]++
+			if terminated[i] {
+				episodeSteps[i] = 0
+			}
+		}
+	}
+	for agentNum, reward := range totalRewards {
+		agents.RewardAgent(agentNum, reward)
+	}
+	if err := agents.Evolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := agents.EvaluateAgents([]int{0}, obs); err == nil {
+		t.Error("EvaluateAgents(mismatched lengths) = nil error, want error")
+	}
+}
+
 func TestProcessObservations(t *testing.T) {
 	const episodeStep = 4
 
@@ -121,6 +239,19 @@ func TestProcessObservations(t *testing.T) {
 			},
 			want: []int{0, 1, 2, episodeStep},
 		},
+		{
+			name:        "FrozenLake",
+			actionSpace: &common.Space{Type: "Discrete", N: 4},
+			obsSpace:    &common.Space{Type: "Discrete", N: 16},
+			want:        []int{0},
+		},
+		{
+			name:               "FrozenLake with episode step",
+			appendEpisodeSteps: true,
+			actionSpace:        &common.Space{Type: "Discrete", N: 4},
+			obsSpace:           &common.Space{Type: "Discrete", N: 16},
+			want:               []int{0, episodeStep},
+		},
 	}
 
 	for _, tt := range tests {
@@ -135,6 +266,9 @@ func TestProcessObservations(t *testing.T) {
 			}
 
 			step := func() common.Obs {
+				if tt.obsSpace.Type == "Discrete" {
+					return discreteObsT(0)
+				}
 				n := len(tt.obsSpace.Subspaces)
 				lastObs := make(obsT, n)
 				for i := 0; i < n; i++ {
@@ -155,3 +289,216 @@ func TestProcessObservations(t *testing.T) {
 		})
 	}
 }
+
+type boxObsT []float64
+
+func (o boxObsT) Unmarshal(dst any) error {
+	switch v := dst.(type) {
+	case *[]float64:
+		*v = append((*v)[:0], o...)
+	default:
+		return fmt.Errorf("unsupported obs type %T", dst)
+	}
+	return nil
+}
+
+// checkAction verifies that the action returned by EvaluateAgents
+// is within the action space.
+func checkAction(t *testing.T, space *common.Space, action any) {
+	t.Helper()
+	switch space.Type {
+	case "Box":
+		a, ok := action.([]float64)
+		if n, _ := spaceLen(space); !ok || len(a) != n {
+			t.Fatalf("action = %#v, want []float64 of length %v", action, n)
+		}
+		if len(space.Low) == 0 {
+			return
+		}
+		for i, v := range a {
+			if v < space.Low[i] || v > space.High[i] {
+				t.Fatalf("action[%v] = %v, want %v<=#<=%v", i, v, space.Low[i], space.High[i])
+			}
+		}
+	case "MultiDiscrete", "MultiBinary":
+		a, ok := action.([]int)
+		if n, _ := spaceLen(space); !ok || len(a) != n {
+			t.Fatalf("action = %#v, want []int of length %v", action, n)
+		}
+		for i, v := range a {
+			low, high := 0, 1
+			if space.Type == "MultiDiscrete" {
+				low, high = int(space.Low[i]), int(space.High[i])
+			}
+			if v < low || v > high {
+				t.Fatalf("action[%v] = %v, want %v<=#<=%v", i, v, low, high)
+			}
+		}
+	default:
+		if a, ok := action.(int); !ok || a < 0 || a >= space.N {
+			t.Fatalf("action = %#v, want 0<=#<%v", action, space.N)
+		}
+	}
+}
+
+func TestGymnasiumAgentsBoxSpaces(t *testing.T) {
+	for _, environment := range []string{"CartPole-v1", "MountainCarContinuous-v0", "Pendulum-v1"} {
+		t.Run(environment, func(t *testing.T) {
+			actionSpace, obsSpace, err := gym.GetSpaces(environment)
+			if err != nil {
+				t.Fatal(err)
+			}
+			agents, err := NewGymnasiumAgents(actionSpace, obsSpace, WithAppendEpisodeSteps())
+			if err != nil {
+				t.Fatal(err)
+			}
+			envs, err := gym.MakeEnvs(environment, defaultNumIndividuals)
+			if err != nil {
+				t.Fatal(err)
+			}
+			venv := gym.NewSyncVectorEnv(envs)
+			defer venv.Close()
+
+			obs, _ := venv.Reset()
+			episodeSteps := make([]int, defaultNumIndividuals)
+			totalRewards := make([]float64, defaultNumIndividuals)
+			for step := 0; step < 20; step++ {
+				actions, err := agents.EvaluateAgents(episodeSteps, obs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, action := range actions {
+					checkAction(t, actionSpace, action)
+				}
+
+				var rewards []float64
+				var terminated, truncated []bool
+				var infos []any
+				obs, rewards, terminated, truncated, infos = venv.Step(actions)
+				for i := range obs {
+					if truncated[i] {
+						t.Fatalf("unexpected truncation: info=%v", infos[i])
+					}
+					totalRewards[i] += rewards[i]
+					episodeSteps[i]++
+					if terminated[i] {
+						episodeSteps[i] = 0
+					}
+				}
+			}
+			for agentNum, reward := range totalRewards {
+				agents.RewardAgent(agentNum, reward)
+			}
+			if err := agents.Evolve(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestGymnasiumAgentsMultiSpaces(t *testing.T) {
+	multiDiscrete := &common.Space{Type: "MultiDiscrete", Low: []float64{0, 2, -1}, High: []float64{4, 3, 1}, Shape: []int{3}}
+	multiBinary := &common.Space{Type: "MultiBinary", N: 4, Shape: []int{4}}
+	box := &common.Space{Type: "Box", Low: []float64{-1, -1}, High: []float64{1, 1}, Shape: []int{2}}
+	unboundedBox := &common.Space{Type: "Box", Shape: []int{2}}
+
+	tests := []struct {
+		name        string
+		actionSpace *common.Space
+		obsSpace    *common.Space
+		obs         common.Obs
+	}{
+		{name: "Box action without bounds, Box obs without bounds", actionSpace: unboundedBox, obsSpace: unboundedBox, obs: boxObsT{0.5, -0.25}},
+		{name: "MultiDiscrete action, Discrete obs", actionSpace: multiDiscrete, obsSpace: &common.Space{Type: "Discrete", N: 16}, obs: discreteObsT(3)},
+		{name: "MultiBinary action, Discrete obs", actionSpace: multiBinary, obsSpace: &common.Space{Type: "Discrete", N: 16}, obs: discreteObsT(3)},
+		{name: "MultiDiscrete action, Box obs", actionSpace: multiDiscrete, obsSpace: box, obs: boxObsT{0.5, -0.25}},
+		{name: "MultiBinary action, Box obs", actionSpace: multiBinary, obsSpace: box, obs: boxObsT{0.5, -0.25}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agents, err := NewGymnasiumAgents(tt.actionSpace, tt.obsSpace, WithNumIndividuals(50))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(agents.Individuals[0].Genes), len(tt.actionSpace.Shape); want != 1 || got != tt.actionSpace.Shape[0] {
+				t.Errorf("got %v genes, want %v", got, tt.actionSpace.Shape[0])
+			}
+
+			obs := make([]common.Obs, len(agents.Individuals))
+			for i := range obs {
+				obs[i] = tt.obs
+			}
+			actions, err := agents.EvaluateAgents(make([]int, len(obs)), obs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, action := range actions {
+				checkAction(t, tt.actionSpace, action)
+			}
+		})
+	}
+}
+
+func TestNewGymnasiumAgentsErrors(t *testing.T) {
+	discrete := &common.Space{Type: "Discrete", N: 2}
+	tests := []struct {
+		name        string
+		actionSpace *common.Space
+		obsSpace    *common.Space
+	}{
+		{name: "Dict action", actionSpace: &common.Space{Type: "Dict"}, obsSpace: discrete},
+		{name: "Dict obs", actionSpace: discrete, obsSpace: &common.Space{Type: "Dict"}},
+		{name: "Tuple of Box obs", actionSpace: discrete, obsSpace: &common.Space{Type: "Tuple", Subspaces: []*common.Space{{Type: "Box"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGymnasiumAgents(tt.actionSpace, tt.obsSpace); err == nil {
+				t.Error("NewGymnasiumAgents = nil error, want error")
+			}
+		})
+	}
+}
+
+func TestProcessFloatObservations(t *testing.T) {
+	box := &common.Space{Type: "Box", Low: []float64{-1, -1}, High: []float64{1, 1}, Shape: []int{2}}
+	agents, err := NewGymnasiumAgents(&common.Space{Type: "Discrete", N: 2}, box, WithAppendEpisodeSteps())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := agents.processFloatObservations(4, boxObsT{0.5, -0.25})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]float64{0.5, -0.25, 4}, got); diff != "" {
+		t.Errorf("processFloatObservations mismatch (-want +got):\n%v", diff)
+	}
+
+	if _, err := agents.processFloatObservations(4, boxObsT{0.5}); err == nil {
+		t.Error("processFloatObservations(short obs) = nil error, want error")
+	}
+}
+
+func TestScaleAction(t *testing.T) {
+	tests := []struct {
+		x, low, high float64
+		want         float64
+	}{
+		{x: -1, low: -2, high: 2, want: -2},
+		{x: 0, low: -2, high: 2, want: 0},
+		{x: 0.5, low: -2, high: 2, want: 1},
+		{x: 10, low: -2, high: 2, want: 2},
+		{x: 0, low: 0, high: 10, want: 5},
+		{x: math.NaN(), low: 0, high: 10, want: 5},
+		{x: 10, low: -math.MaxFloat32, high: math.MaxFloat32, want: 10},
+		{x: -10, low: 0, high: math.Inf(1), want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := scaleAction(tt.x, tt.low, tt.high); got != tt.want {
+			t.Errorf("scaleAction(%v, %v, %v) = %v, want %v", tt.x, tt.low, tt.high, got, tt.want)
+		}
+	}
+}
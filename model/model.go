@@ -8,6 +8,7 @@ package model
 import (
 	"fmt"
 	"log"
+	"math"
 	"math/rand"
 	"strings"
 
@@ -17,6 +18,7 @@ import (
 	mn "github.com/gmlewis/gep/v2/functions/math_nodes"
 	"github.com/gmlewis/gep/v2/gene"
 	"github.com/gmlewis/gep/v2/genome"
+	"github.com/gmlewis/gep/v2/transform"
 )
 
 // Generation represents one complete generation of the model.
@@ -24,8 +26,30 @@ type Generation struct {
 	Individuals []*genome.Genome
 	Funcs       []gene.FuncWeight
 	ScoringFunc genome.ScoringFunc
+	// MultiScoringFunc is only used in multi-objective mode (see WithMultiObjective).
+	MultiScoringFunc genome.MultiScoringFunc
+	// ParetoFront holds the final Pareto front found by Evolve
+	// in multi-objective mode.
+	ParetoFront []*genome.Genome
+	// History holds the training and validation scores of every
+	// generation when WithValidation is used.
+	History []Report
 
 	debug bool
+
+	// parsimony options
+	parsimonyBonus bool
+	sizeTieBreak   bool
+	sizePenalty    float64
+
+	// validation options
+	validationFunc      genome.ScoringFunc
+	selectByValidation  bool
+	bestValidated       *genome.Genome
+	bestValidationScore float64
+
+	// preprocessing pipeline
+	pipeline *transform.Pipeline
 }
 
 // New creates a new random generation of the model.
@@ -38,6 +62,7 @@ type Generation struct {
 // numConstants is the number of constants (inputs) to use within each gene.
 // linkFunc is the linking function used to combine the genes within a genome.
 // sf is the scoring (or fitness) function.
+// opts are optional settings such as parsimony pressure.
 func New(
 	fs []gene.FuncWeight,
 	funcType functions.FuncType,
@@ -48,13 +73,17 @@ func New(
 	numConstants int,
 	linkFunc string,
 	sf genome.ScoringFunc,
-	debug bool) *Generation {
+	debug bool,
+	opts ...GenerationOption) *Generation {
 	r := &Generation{
 		Individuals: make([]*genome.Genome, numIndividuals),
 		Funcs:       fs,
 		ScoringFunc: sf,
 		debug:       debug,
 	}
+	for _, f := range opts {
+		f(r)
+	}
 	n := maxArity(fs, funcType)
 	tailSize := headSize*(n-1) + 1
 	for i := range r.Individuals {
@@ -63,19 +92,42 @@ func New(
 			genes[j] = gene.RandomNew(headSize, tailSize, numTerminals, numConstants, fs, funcType)
 		}
 		r.Individuals[i] = genome.New(genes, linkFunc)
+		r.Individuals[i].Pipeline = r.pipeline
 	}
 	return r
 }
 
 // Evolve runs the GEP algorithm for the given number of iterations, or until a score of 1000 (or more) is reached.
+// With WithSelectByValidation, the genome with the highest validation score is returned instead of the best one.
+//
+// In multi-objective mode (see WithMultiObjective), there is no single best
+// genome: the result is the whole Pareto front, so callers should use
+// EvolvePareto, which returns it along with any error. For compatibility,
+// Evolve runs EvolvePareto, stores the front in ParetoFront, and returns only
+// the genome of the front that is best for the first objective, or nil
+// (after logging the error) if EvolvePareto fails.
 func (g *Generation) Evolve(iterations int) *genome.Genome {
+	if g.MultiScoringFunc != nil {
+		front, err := g.EvolvePareto(iterations)
+		g.ParetoFront = front
+		if err != nil {
+			log.Printf("Evolve: %v", err)
+			return nil
+		}
+		if len(front) == 0 {
+			return nil
+		}
+		return front[0]
+	}
+
 	// Algorithm flow diagram, figure 3.1, book page 56
 	for i := 0; i < iterations; i++ {
 		// fmt.Printf("Iteration #%v...\n", i)
 		bestGenome := g.getBest() // Preserve the best genome
-		if bestGenome.Score >= 1000.0 {
+		g.record(i, bestGenome)
+		if bestGenome.Score >= perfectScore {
 			fmt.Printf("Stopping after generation #%v\n", i)
-			return bestGenome
+			return g.final(bestGenome)
 		}
 		// fmt.Printf("Best genome (score %v): %v\n", bestGenome.Score, *bestGenome)
 		saveCopy := bestGenome.Dup()
@@ -91,7 +143,9 @@ func (g *Generation) Evolve(iterations int) *genome.Genome {
 		g.Individuals[0] = saveCopy
 	}
 	fmt.Printf("Stopping after generation #%v\n", iterations)
-	return g.getBest()
+	bestGenome := g.getBest()
+	g.record(iterations, bestGenome)
+	return g.final(bestGenome)
 }
 
 // replication replaces all individuals in the population by
@@ -220,18 +274,37 @@ func (g *Generation) crossover() {
 }
 
 // getBest evaluates all individuals and returns a pointer to the best one.
+// Scores may be negative (such as with WithSizePenalty), and a NaN score
+// ranks below all others.
 func (g *Generation) getBest() *genome.Genome {
-	bestScore := 0.0
-	bestGenome := g.Individuals[0]
+	bestScore := math.Inf(-1)
+	var bestGenome *genome.Genome
+	bestSize := -1
 	c := make(chan *genome.Genome)
 	for i := 0; i < len(g.Individuals); i++ { // Evaluate individuals concurrently
 		go g.Individuals[i].EvaluateWithScore(g.ScoringFunc, c)
 	}
 	for i := 0; i < len(g.Individuals); i++ { // Collect and return the highest scoring Genome
 		gn := <-c
-		if gn.Score > bestScore {
+		g.applyParsimony(gn)
+		score := gn.Score
+		if math.IsNaN(score) {
+			score = math.Inf(-1)
+		}
+		if bestGenome == nil || score > bestScore {
 			bestGenome = gn
-			bestScore = gn.Score
+			bestScore = score
+			bestSize = -1
+			continue
+		}
+		if g.sizeTieBreak && score == bestScore && gn != bestGenome {
+			if bestSize < 0 {
+				bestSize = bestGenome.Complexity().Size
+			}
+			if size := gn.Complexity().Size; size < bestSize {
+				bestGenome = gn
+				bestSize = size
+			}
 		}
 	}
 	return bestGenome
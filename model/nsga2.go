@@ -0,0 +1,254 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gmlewis/gep/v2/genome"
+)
+
+// WithMultiObjective runs the model in multi-objective mode using the
+// provided scoring function, which returns one value per objective.
+// All objectives are maximized. Use EvolvePareto to run the NSGA-II
+// algorithm and get the final Pareto front; Evolve also runs it, but
+// only returns one genome of the front (see Evolve).
+func WithMultiObjective(msf genome.MultiScoringFunc) GenerationOption {
+	return func(g *Generation) {
+		g.MultiScoringFunc = msf
+	}
+}
+
+// nsgaRank holds the non-domination rank and crowding distance
+// of a genome within a population.
+type nsgaRank struct {
+	rank     int
+	crowding float64
+}
+
+// EvolvePareto runs the NSGA-II multi-objective algorithm for the given number
+// of iterations and returns the final Pareto front (the distinct non-dominated
+// genomes), sorted by their first objective (best first).
+//
+// See: K. Deb, A. Pratap, S. Agarwal and T. Meyarivan, "A fast and elitist
+// multiobjective genetic algorithm: NSGA-II," IEEE Transactions on
+// Evolutionary Computation, vol. 6, no. 2, pp. 182-197, 2002.
+func (g *Generation) EvolvePareto(iterations int) ([]*genome.Genome, error) {
+	n := len(g.Individuals)
+	if n == 0 {
+		return nil, errors.New("EvolvePareto: the population is empty")
+	}
+	if err := g.evaluateObjectives(g.Individuals); err != nil {
+		return nil, err
+	}
+	fronts := nonDominatedSort(g.Individuals)
+	ranks := rankFronts(g.Individuals, fronts)
+
+	for i := 0; i < iterations; i++ {
+		// Create the offspring by binary tournament selection using the
+		// crowded-comparison operator, followed by mutation.
+		parents := g.Individuals
+		offspring := make([]*genome.Genome, 0, n)
+		for j := 0; j < n; j++ {
+			a, b := rand.Intn(n), rand.Intn(n)
+			if crowdedLess(ranks[b], ranks[a]) {
+				a = b
+			}
+			offspring = append(offspring, parents[a].Dup())
+		}
+		g.Individuals = offspring
+		g.mutation()
+		if err := g.evaluateObjectives(offspring); err != nil {
+			return nil, err
+		}
+
+		// Elitism: select the best n genomes from parents and offspring.
+		combined := append(append([]*genome.Genome{}, parents...), offspring...)
+		fronts = nonDominatedSort(combined)
+		combinedRanks := rankFronts(combined, fronts)
+		next := make([]*genome.Genome, 0, n)
+		ranks = make([]nsgaRank, 0, n)
+		for _, front := range fronts {
+			if len(next)+len(front) > n {
+				sort.SliceStable(front, func(a, b int) bool {
+					return combinedRanks[front[a]].crowding > combinedRanks[front[b]].crowding
+				})
+				front = front[:n-len(next)]
+			}
+			for _, idx := range front {
+				next = append(next, combined[idx])
+				ranks = append(ranks, combinedRanks[idx])
+			}
+			if len(next) == n {
+				break
+			}
+		}
+		g.Individuals = next
+	}
+
+	// Selection duplicates genomes, so only keep one copy of each.
+	var result []*genome.Genome
+	seen := map[string]bool{}
+	for i, gn := range g.Individuals {
+		if key := gn.String(); ranks[i].rank == 0 && !seen[key] {
+			seen[key] = true
+			result = append(result, gn)
+		}
+	}
+	sort.SliceStable(result, func(a, b int) bool {
+		return result[a].Objectives[0] > result[b].Objectives[0]
+	})
+	return result, nil
+}
+
+// evaluateObjectives concurrently evaluates all objectives for the genomes.
+func (g *Generation) evaluateObjectives(individuals []*genome.Genome) error {
+	c := make(chan error)
+	for _, gn := range individuals {
+		go func() {
+			err := gn.EvaluateWithObjectives(g.MultiScoringFunc)
+			finiteObjectives(gn)
+			c <- err
+		}()
+	}
+	var result error
+	for range individuals {
+		if err := <-c; err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// finiteObjectives replaces the non-finite objectives of the genome (such as
+// the NaN resulting from a division by zero) with -Inf, the worst possible
+// value, because NaN compares false with everything and would never be dominated.
+// The objectives are copied first, since they may be shared with the caller.
+func finiteObjectives(gn *genome.Genome) {
+	copied := false
+	for i, v := range gn.Objectives {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			continue
+		}
+		if !copied {
+			gn.Objectives = append([]float64(nil), gn.Objectives...)
+			copied = true
+		}
+		gn.Objectives[i] = math.Inf(-1)
+	}
+}
+
+// dominates reports whether genome a Pareto-dominates genome b, that is,
+// a is no worse than b in all objectives and strictly better in at least one.
+func dominates(a, b *genome.Genome) bool {
+	better := false
+	for i, v := range a.Objectives {
+		switch {
+		case i >= len(b.Objectives):
+			return better
+		case v < b.Objectives[i]:
+			return false
+		case v > b.Objectives[i]:
+			better = true
+		}
+	}
+	return better
+}
+
+// nonDominatedSort sorts the population into fronts of indices, where the
+// first front contains the non-dominated genomes, the second front contains
+// the genomes dominated only by the first front, and so on.
+func nonDominatedSort(pop []*genome.Genome) [][]int {
+	dominatedBy := make([][]int, len(pop))
+	counts := make([]int, len(pop))
+	var front []int
+	for p := range pop {
+		for q := range pop {
+			switch {
+			case dominates(pop[p], pop[q]):
+				dominatedBy[p] = append(dominatedBy[p], q)
+			case dominates(pop[q], pop[p]):
+				counts[p]++
+			}
+		}
+		if counts[p] == 0 {
+			front = append(front, p)
+		}
+	}
+
+	var fronts [][]int
+	for len(front) > 0 {
+		fronts = append(fronts, front)
+		var next []int
+		for _, p := range front {
+			for _, q := range dominatedBy[p] {
+				counts[q]--
+				if counts[q] == 0 {
+					next = append(next, q)
+				}
+			}
+		}
+		front = next
+	}
+	return fronts
+}
+
+// rankFronts returns the rank and crowding distance of every genome.
+func rankFronts(pop []*genome.Genome, fronts [][]int) []nsgaRank {
+	ranks := make([]nsgaRank, len(pop))
+	for r, front := range fronts {
+		for _, idx := range front {
+			ranks[idx].rank = r
+		}
+		crowdingDistance(pop, front, ranks)
+	}
+	return ranks
+}
+
+// crowdingDistance assigns the crowding distance to every genome in the front.
+// Boundary genomes are given an infinite distance so that they are always preferred.
+func crowdingDistance(pop []*genome.Genome, front []int, ranks []nsgaRank) {
+	if len(front) == 0 {
+		return
+	}
+	sorted := append([]int{}, front...)
+	numObjectives := len(pop[front[0]].Objectives)
+	for m := 0; m < numObjectives; m++ {
+		sort.SliceStable(sorted, func(a, b int) bool {
+			return pop[sorted[a]].Objectives[m] < pop[sorted[b]].Objectives[m]
+		})
+		ranks[sorted[0]].crowding = math.Inf(1)
+		ranks[sorted[len(sorted)-1]].crowding = math.Inf(1)
+		// Genomes with -Inf objectives (see finiteObjectives) sort first, so
+		// measure the span of the others to keep the distances finite.
+		lo := 0
+		for lo < len(sorted)-1 && math.IsInf(pop[sorted[lo]].Objectives[m], -1) {
+			lo++
+		}
+		first, last := sorted[lo], sorted[len(sorted)-1]
+		ranks[first].crowding = math.Inf(1)
+		span := pop[last].Objectives[m] - pop[first].Objectives[m]
+		if !(span > 0) {
+			continue
+		}
+		for i := lo + 1; i < len(sorted)-1; i++ {
+			d := pop[sorted[i+1]].Objectives[m] - pop[sorted[i-1]].Objectives[m]
+			ranks[sorted[i]].crowding += d / span
+		}
+	}
+}
+
+// crowdedLess is the crowded-comparison operator, which reports whether
+// a is preferred over b: a lower rank wins and, within the same rank,
+// a larger crowding distance wins.
+func crowdedLess(a, b nsgaRank) bool {
+	if a.rank != b.rank {
+		return a.rank < b.rank
+	}
+	return a.crowding > b.crowding
+}
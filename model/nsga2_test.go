@@ -0,0 +1,176 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+	"github.com/gmlewis/gep/v2/gene"
+	"github.com/gmlewis/gep/v2/genome"
+	"github.com/google/go-cmp/cmp"
+)
+
+func objectives(values ...[]float64) []*genome.Genome {
+	result := make([]*genome.Genome, 0, len(values))
+	for _, v := range values {
+		result = append(result, &genome.Genome{Objectives: v})
+	}
+	return result
+}
+
+func TestNonDominatedSort(t *testing.T) {
+	pop := objectives(
+		[]float64{1, 5}, // front 0
+		[]float64{5, 1}, // front 0
+		[]float64{3, 3}, // front 0
+		[]float64{2, 2}, // front 1
+		[]float64{1, 1}, // front 2
+		[]float64{3, 1}, // front 1
+	)
+	got := nonDominatedSort(pop)
+	want := [][]int{{0, 1, 2}, {3, 5}, {4}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("nonDominatedSort mismatch (-want +got):\n%v", diff)
+	}
+}
+
+func TestCrowdingDistance(t *testing.T) {
+	pop := objectives(
+		[]float64{0, 4},
+		[]float64{1, 3},
+		[]float64{3, 1},
+		[]float64{4, 0},
+	)
+	ranks := rankFronts(pop, [][]int{{0, 1, 2, 3}})
+	want := []float64{math.Inf(1), 1.5, 1.5, math.Inf(1)}
+	for i, r := range ranks {
+		if r.crowding != want[i] {
+			t.Errorf("crowding[%v] = %v, want %v", i, r.crowding, want[i])
+		}
+	}
+}
+
+func TestNonFiniteObjectives(t *testing.T) {
+	pop := objectives(
+		[]float64{10, 10},
+		[]float64{math.NaN(), math.NaN()},
+		[]float64{1, 1},
+		[]float64{math.Inf(1), 0},
+	)
+	nan := pop[1]
+	g := &Generation{MultiScoringFunc: func(gn *genome.Genome) []float64 { return gn.Objectives }}
+	if err := g.evaluateObjectives(pop); err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(nan.Objectives[0], -1) || !math.IsInf(nan.Objectives[1], -1) {
+		t.Errorf("NaN objectives = %v, want -Inf", nan.Objectives)
+	}
+
+	got := nonDominatedSort(pop)
+	want := [][]int{{0}, {2}, {3}, {1}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("nonDominatedSort mismatch (-want +got):\n%v", diff)
+	}
+
+	pop = objectives(
+		[]float64{math.Inf(-1), 4},
+		[]float64{0, 3},
+		[]float64{1, 2},
+		[]float64{3, 1},
+		[]float64{4, 0},
+	)
+	ranks := rankFronts(pop, [][]int{{0, 1, 2, 3, 4}})
+	for i, r := range ranks {
+		if math.IsNaN(r.crowding) {
+			t.Errorf("crowding[%v] = NaN, want a number", i)
+		}
+	}
+	if got, want := ranks[2].crowding, 0.75+0.5; math.Abs(got-want) > 1e-12 {
+		t.Errorf("crowding[2] = %v, want %v", got, want)
+	}
+}
+
+func TestEvolvePareto(t *testing.T) {
+	funcs := []gene.FuncWeight{
+		{Symbol: "+", Weight: 1},
+		{Symbol: "-", Weight: 1},
+		{Symbol: "*", Weight: 1},
+	}
+	msf := func(g *genome.Genome) []float64 {
+		var e float64
+		for x := -2.0; x <= 2; x += 0.5 {
+			v := g.EvalMath([]float64{x}) - (x*x + x)
+			e += v * v
+		}
+		return []float64{1000 / (1 + e), -float64(g.Complexity().Size)}
+	}
+	gen := New(funcs, functions.Float64, 20, 6, 1, 1, 0, "+", nil, false, WithMultiObjective(msf))
+	front, err := gen.EvolvePareto(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(front) == 0 {
+		t.Fatal("EvolvePareto returned an empty Pareto front")
+	}
+	if got, want := len(gen.Individuals), 20; got != want {
+		t.Errorf("EvolvePareto population = %v individuals, want %v", got, want)
+	}
+	for i, a := range front {
+		for j, b := range front {
+			if i != j && a.String() == b.String() {
+				t.Errorf("front[%v] and front[%v] are identical: %v", i, j, a)
+			}
+			if dominates(a, b) {
+				t.Errorf("front[%v]=%v dominates front[%v]=%v", i, a.Objectives, j, b.Objectives)
+			}
+		}
+		if i > 0 && a.Objectives[0] > front[i-1].Objectives[0] {
+			t.Errorf("front[%v]=%v not sorted after front[%v]=%v", i, a.Objectives, i-1, front[i-1].Objectives)
+		}
+	}
+}
+
+func TestEvolveMultiObjective(t *testing.T) {
+	funcs := []gene.FuncWeight{
+		{Symbol: "+", Weight: 1},
+		{Symbol: "*", Weight: 1},
+	}
+	// Both objectives are constant, so every genome is on the front
+	// and only the distinct genomes are kept.
+	msf := func(g *genome.Genome) []float64 { return []float64{1, 1} }
+	gen := New(funcs, functions.Float64, 20, 2, 1, 1, 0, "+", nil, false, WithMultiObjective(msf))
+	best := gen.Evolve(5)
+	if len(gen.ParetoFront) == 0 || best != gen.ParetoFront[0] {
+		t.Fatalf("Evolve = %v, want the first genome of ParetoFront %v", best, gen.ParetoFront)
+	}
+	distinct := map[string]bool{}
+	for _, gn := range gen.Individuals {
+		distinct[gn.String()] = true
+	}
+	if got, want := len(gen.ParetoFront), len(distinct); got != want {
+		t.Errorf("ParetoFront has %v genomes, want %v distinct genomes", got, want)
+	}
+}
+
+func TestEvolveMultiObjectiveError(t *testing.T) {
+	msf := func(g *genome.Genome) []float64 { return []float64{1} }
+	gen := &Generation{MultiScoringFunc: msf}
+	if _, err := gen.EvolvePareto(2); err == nil {
+		t.Error("EvolvePareto with an empty population = nil error, want error")
+	}
+	if got := gen.Evolve(2); got != nil {
+		t.Errorf("Evolve with an empty population = %v, want nil", got)
+	}
+}
+
+func TestEvolveParetoNilMultiScoringFunc(t *testing.T) {
+	funcs := []gene.FuncWeight{{Symbol: "+", Weight: 1}}
+	gen := New(funcs, functions.Float64, 4, 2, 1, 1, 0, "+", nil, false)
+	if _, err := gen.EvolvePareto(1); err == nil {
+		t.Error("EvolvePareto without a MultiScoringFunc = nil error, want error")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"github.com/gmlewis/gep/v2/genome"
+)
+
+// perfectScore is the score at (or above) which a genome is considered
+// to be a perfect solution.
+const perfectScore = 1000.0
+
+// GenerationOption represents an option that can modify a Generation.
+type GenerationOption func(g *Generation)
+
+// WithParsimonyBonus adds Ferreira's parsimony pressure to perfect solutions
+// (those scoring 1000 or more), so that smaller perfect solutions win:
+//
+//	score = score * (1 + (1/5000) * (Smax - S) / (Smax - Smin))
+//
+// where S is the expressed size of the genome (as reported by
+// Genome.Complexity, including the linking function nodes), Smax is the
+// size of the genome if every symbol were expressed, and Smin is the size
+// of the genome if every gene were a single terminal.
+func WithParsimonyBonus() GenerationOption {
+	return func(g *Generation) {
+		g.parsimonyBonus = true
+	}
+}
+
+// WithSizeTieBreak breaks ties between equally-scored genomes by
+// preferring the one with the smallest expressed size.
+func WithSizeTieBreak() GenerationOption {
+	return func(g *Generation) {
+		g.sizeTieBreak = true
+	}
+}
+
+// WithSizePenalty subtracts coefficient*S from the score of every
+// imperfect genome, where S is the expressed size of the genome.
+// Perfect solutions (scoring 1000 or more) are not penalized so that
+// evolution still terminates; use WithParsimonyBonus to favor smaller
+// perfect solutions.
+func WithSizePenalty(coefficient float64) GenerationOption {
+	return func(g *Generation) {
+		g.sizePenalty = coefficient
+	}
+}
+
+// applyParsimony adjusts the score of the genome according to the
+// parsimony options of the generation.
+func (g *Generation) applyParsimony(gn *genome.Genome) {
+	if !g.parsimonyBonus && g.sizePenalty == 0 {
+		return
+	}
+
+	// The linking function nodes are always expressed.
+	var links int
+	if gn.LinkFunc != "tuple" && len(gn.Genes) > 1 {
+		links = len(gn.Genes) - 1
+	}
+	size := gn.Complexity().Size
+	maxSize := links
+	for _, v := range gn.Genes {
+		maxSize += len(v.Symbols)
+	}
+	minSize := len(gn.Genes) + links
+
+	switch {
+	case gn.Score >= perfectScore && g.parsimonyBonus && maxSize > minSize:
+		gn.Score *= 1 + float64(maxSize-size)/float64(maxSize-minSize)/5000
+	case gn.Score < perfectScore:
+		gn.Score -= g.sizePenalty * float64(size)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+	"github.com/gmlewis/gep/v2/gene"
+	"github.com/gmlewis/gep/v2/genome"
+)
+
+func newParsimonyGenome(karva string, score float64) *genome.Genome {
+	gn := genome.New([]*gene.Gene{gene.New(karva, functions.Float64)}, "+")
+	gn.Score = score
+	return gn
+}
+
+func TestApplyParsimony(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []GenerationOption
+		karva string
+		// numGenes is the number of copies of the gene, defaulting to 1.
+		numGenes int
+		score    float64
+		want     float64
+	}{
+		{
+			name:  "no options",
+			karva: "+.d0.d0.d0.d0",
+			score: 1000,
+			want:  1000,
+		},
+		{
+			name:  "bonus for perfect solution",
+			opts:  []GenerationOption{WithParsimonyBonus()},
+			karva: "+.d0.d0.d0.d0",
+			score: 1000,
+			want:  1000 * (1 + 2.0/4.0/5000),
+		},
+		{
+			name:  "no bonus for imperfect solution",
+			opts:  []GenerationOption{WithParsimonyBonus()},
+			karva: "+.d0.d0.d0.d0",
+			score: 500,
+			want:  500,
+		},
+		{
+			name:  "penalty for imperfect solution",
+			opts:  []GenerationOption{WithSizePenalty(2)},
+			karva: "+.d0.d0.d0.d0",
+			score: 500,
+			want:  494,
+		},
+		{
+			name:     "bonus counts the linking function",
+			opts:     []GenerationOption{WithParsimonyBonus()},
+			karva:    "+.d0.d0.d0.d0",
+			numGenes: 2,
+			score:    1000,
+			want:     1000 * (1 + 4.0/8.0/5000),
+		},
+		{
+			name:     "penalty counts the linking function",
+			opts:     []GenerationOption{WithSizePenalty(2)},
+			karva:    "+.d0.d0.d0.d0",
+			numGenes: 2,
+			score:    500,
+			want:     486,
+		},
+		{
+			name:  "no penalty for perfect solution",
+			opts:  []GenerationOption{WithSizePenalty(2)},
+			karva: "+.d0.d0.d0.d0",
+			score: 1000,
+			want:  1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generation{}
+			for _, f := range tt.opts {
+				f(g)
+			}
+			gn := newParsimonyGenome(tt.karva, tt.score)
+			for i := 1; i < tt.numGenes; i++ {
+				gn.Genes = append(gn.Genes, gene.New(tt.karva, functions.Float64))
+			}
+			g.applyParsimony(gn)
+			if math.Abs(gn.Score-tt.want) > 1e-9 {
+				t.Errorf("applyParsimony score = %v, want %v", gn.Score, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBestSizeTieBreak(t *testing.T) {
+	large := newParsimonyGenome("+.+.d0.d0.d0.d0.d0", 0)
+	small := newParsimonyGenome("+.d0.d0.d0.d0.d0.d0", 0)
+	sf := func(g *genome.Genome) float64 { return 100 }
+
+	g := &Generation{
+		Individuals: []*genome.Genome{large, small},
+		ScoringFunc: sf,
+	}
+	WithSizeTieBreak()(g)
+	for i := 0; i < 10; i++ {
+		if got := g.getBest(); got != small {
+			t.Fatalf("getBest = %v, want %v", got, small)
+		}
+	}
+}
+
+func TestGetBestNegativeScores(t *testing.T) {
+	large := newParsimonyGenome("+.+.d0.d0.d0.d0.d0", 0)
+	small := newParsimonyGenome("d0.d0.d0", 0)
+	nan := newParsimonyGenome("d0.d0.d0", 0)
+	sf := func(gn *genome.Genome) float64 {
+		if gn == nan {
+			return math.NaN()
+		}
+		return 0
+	}
+
+	// With the size penalty, every score is at most 0: large scores -5 and
+	// small scores -1, so small must win even though large comes first.
+	g := &Generation{
+		Individuals: []*genome.Genome{large, nan, small},
+		ScoringFunc: sf,
+	}
+	WithSizePenalty(1)(g)
+	for i := 0; i < 10; i++ {
+		if got := g.getBest(); got != small {
+			t.Fatalf("getBest = %v (score %v), want %v", got, got.Score, small)
+		}
+	}
+}
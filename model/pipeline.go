@@ -0,0 +1,21 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"github.com/gmlewis/gep/v2/transform"
+)
+
+// WithPipeline attaches a preprocessing pipeline (see transform.Fit) to every
+// genome in the population. Genomes evaluated with EvalMath then see the
+// transformed inputs and are evolved to predict the transformed target, while
+// the scoring function keeps working with the raw fitness cases.
+// The pipeline is also written into the code generated by genome.Write so
+// that the generated model works on raw inputs.
+func WithPipeline(p *transform.Pipeline) GenerationOption {
+	return func(g *Generation) {
+		g.pipeline = p
+	}
+}
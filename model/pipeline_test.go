@@ -0,0 +1,48 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/functions"
+	"github.com/gmlewis/gep/v2/gene"
+	"github.com/gmlewis/gep/v2/genome"
+	"github.com/gmlewis/gep/v2/transform"
+)
+
+func TestWithPipeline(t *testing.T) {
+	in := [][]float64{{1}, {2}, {3}}
+	target := []float64{100, 200, 300}
+	p, err := transform.Fit(transform.Standardization, transform.Standardization, in, target)
+	if err != nil {
+		t.Fatalf("transform.Fit: %v", err)
+	}
+
+	fs := []gene.FuncWeight{{Symbol: "+", Weight: 1}}
+	sf := func(g *genome.Genome) float64 { return 0 }
+	g := New(fs, functions.Float64, 10, 3, 1, 1, 0, "+", sf, false, WithPipeline(p))
+	for i, gn := range g.Individuals {
+		if gn.Pipeline != p {
+			t.Fatalf("Individuals[%v].Pipeline = %v, want %v", i, gn.Pipeline, p)
+		}
+	}
+	g.mutation()
+	for i, gn := range g.Individuals {
+		if gn.Dup().Pipeline != p {
+			t.Errorf("Individuals[%v].Dup().Pipeline = %v, want %v", i, gn.Pipeline, p)
+		}
+	}
+
+	// The identity model d0 in standardized units reproduces the raw target.
+	gn := genome.New([]*gene.Gene{gene.New("d0", functions.Float64)}, "+")
+	gn.Pipeline = p
+	for i, v := range in {
+		if got := gn.EvalMath(v); math.Abs(got-target[i]) > 1e-9 {
+			t.Errorf("EvalMath(%v) = %v, want %v", v, got, target[i])
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gmlewis/gep/v2/dataset"
+	"github.com/gmlewis/gep/v2/genome"
+)
+
+// Report records the training and validation scores of the best
+// (by training score) genome of a single generation.
+type Report struct {
+	Generation      int
+	TrainingScore   float64
+	ValidationScore float64
+}
+
+// Gap returns the difference between the training and validation scores.
+// A large positive gap is a sign of overfitting.
+func (r Report) Gap() float64 {
+	return r.TrainingScore - r.ValidationScore
+}
+
+// WithValidation scores the best genome of every generation against held-out
+// fitness cases using the provided scoring function, recording the training
+// and validation scores in the History of the Generation.
+func WithValidation(vsf genome.ScoringFunc) GenerationOption {
+	return func(g *Generation) {
+		g.validationFunc = vsf
+	}
+}
+
+// WithSelectByValidation makes Evolve return the genome with the highest
+// validation score seen during evolution, rather than the genome with the
+// highest training score. It requires WithValidation.
+func WithSelectByValidation() GenerationOption {
+	return func(g *Generation) {
+		g.selectByValidation = true
+	}
+}
+
+// record adds the scores of the best genome of generation i to the history.
+func (g *Generation) record(i int, best *genome.Genome) {
+	if g.validationFunc == nil {
+		return
+	}
+	v := g.validationFunc(best)
+	g.History = append(g.History, Report{
+		Generation:      i,
+		TrainingScore:   best.Score,
+		ValidationScore: v,
+	})
+	if g.selectByValidation && (g.bestValidated == nil || v > g.bestValidationScore) {
+		g.bestValidated = best.Dup()
+		g.bestValidationScore = v
+	}
+}
+
+// final returns the genome that Evolve should return.
+func (g *Generation) final(best *genome.Genome) *genome.Genome {
+	if g.selectByValidation && g.bestValidated != nil {
+		return g.bestValidated
+	}
+	return best
+}
+
+// FoldReport is the result of evolving a model on a single fold.
+type FoldReport struct {
+	// Best is the genome returned by Evolve for this fold.
+	Best            *genome.Genome
+	TrainingScore   float64
+	ValidationScore float64
+	// History holds the training and validation scores of every generation.
+	History []Report
+}
+
+// CrossValidationReport is the result of a k-fold cross-validation.
+type CrossValidationReport struct {
+	Folds []FoldReport
+}
+
+// MeanTrainingScore returns the mean training score over all folds.
+func (r *CrossValidationReport) MeanTrainingScore() float64 {
+	var sum float64
+	for _, f := range r.Folds {
+		sum += f.TrainingScore
+	}
+	return sum / float64(len(r.Folds))
+}
+
+// MeanValidationScore returns the mean validation score over all folds.
+func (r *CrossValidationReport) MeanValidationScore() float64 {
+	var sum float64
+	for _, f := range r.Folds {
+		sum += f.ValidationScore
+	}
+	return sum / float64(len(r.Folds))
+}
+
+// StdDevValidationScore returns the standard deviation of the validation score over all folds.
+func (r *CrossValidationReport) StdDevValidationScore() float64 {
+	mean := r.MeanValidationScore()
+	var sum float64
+	for _, f := range r.Folds {
+		sum += (f.ValidationScore - mean) * (f.ValidationScore - mean)
+	}
+	return math.Sqrt(sum / float64(len(r.Folds)))
+}
+
+// Gap returns the difference between the mean training and validation scores.
+// A large positive gap is a sign of overfitting.
+func (r *CrossValidationReport) Gap() float64 {
+	return r.MeanTrainingScore() - r.MeanValidationScore()
+}
+
+// String returns a table of the training and validation scores of every fold.
+func (r *CrossValidationReport) String() string {
+	lines := []string{"fold\ttraining\tvalidation\tgap"}
+	for i, f := range r.Folds {
+		lines = append(lines, fmt.Sprintf("%v\t%.4f\t%.4f\t%.4f", i, f.TrainingScore, f.ValidationScore, f.TrainingScore-f.ValidationScore))
+	}
+	lines = append(lines, fmt.Sprintf("mean\t%.4f\t%.4f\t%.4f", r.MeanTrainingScore(), r.MeanValidationScore(), r.Gap()))
+	return strings.Join(lines, "\n")
+}
+
+// CrossValidate runs a k-fold cross-validation. For every fold, newScoringFunc
+// creates the scoring functions for the training and validation datasets,
+// newGeneration creates a new population using the training scoring function,
+// and the population is evolved for the given number of iterations while
+// recording the validation score of every generation.
+func CrossValidate(
+	folds []dataset.Fold,
+	newScoringFunc func(d *dataset.Dataset) genome.ScoringFunc,
+	newGeneration func(sf genome.ScoringFunc) *Generation,
+	iterations int) (*CrossValidationReport, error) {
+	if len(folds) == 0 {
+		return nil, errors.New("no folds to cross-validate")
+	}
+
+	r := &CrossValidationReport{}
+	for _, fold := range folds {
+		vsf := newScoringFunc(fold.Validation)
+		g := newGeneration(newScoringFunc(fold.Train))
+		WithValidation(vsf)(g)
+		best := g.Evolve(iterations)
+		r.Folds = append(r.Folds, FoldReport{
+			Best:            best,
+			TrainingScore:   best.Score,
+			ValidationScore: vsf(best),
+			History:         g.History,
+		})
+	}
+	return r, nil
+}
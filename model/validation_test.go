@@ -0,0 +1,106 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/gep/v2/dataset"
+	"github.com/gmlewis/gep/v2/functions"
+	"github.com/gmlewis/gep/v2/gene"
+	"github.com/gmlewis/gep/v2/genome"
+)
+
+func newMathScoringFunc(d *dataset.Dataset) genome.ScoringFunc {
+	in, target := d.MathCases()
+	return func(g *genome.Genome) float64 {
+		var result float64
+		for i, v := range in {
+			e := g.EvalMath(v) - target[i]
+			result += e * e
+		}
+		return 1000 / (1 + result/float64(len(in)))
+	}
+}
+
+func newValidationDataset(t *testing.T) *dataset.Dataset {
+	t.Helper()
+	lines := []string{"x,y"}
+	for i := 0; i < 20; i++ {
+		x := float64(i) / 4
+		lines = append(lines, fmt.Sprintf("%v,%v", x, x*x+x))
+	}
+	d, err := dataset.Load(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+var validationFuncs = []gene.FuncWeight{
+	{Symbol: "+", Weight: 1},
+	{Symbol: "-", Weight: 1},
+	{Symbol: "*", Weight: 1},
+}
+
+func TestEvolveWithValidation(t *testing.T) {
+	train, valid, _, err := newValidationDataset(t).Split(dataset.Random, 0.5, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vsf := newMathScoringFunc(valid)
+	g := New(validationFuncs, functions.Float64, 10, 6, 1, 1, 0, "+", newMathScoringFunc(train), false,
+		WithValidation(vsf), WithSelectByValidation())
+	best := g.Evolve(10)
+
+	if len(g.History) == 0 {
+		t.Fatal("Evolve recorded no history")
+	}
+	var bestValidation float64
+	for i, r := range g.History {
+		if i > 0 && r.Generation <= g.History[i-1].Generation {
+			t.Errorf("History[%v].Generation = %v, want > %v", i, r.Generation, g.History[i-1].Generation)
+		}
+		if r.ValidationScore > bestValidation {
+			bestValidation = r.ValidationScore
+		}
+	}
+	if got := vsf(best); got != bestValidation {
+		t.Errorf("Evolve returned genome with validation score %v, want %v", got, bestValidation)
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	folds, err := newValidationDataset(t).KFold(4, dataset.Random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newGeneration := func(sf genome.ScoringFunc) *Generation {
+		return New(validationFuncs, functions.Float64, 10, 6, 1, 1, 0, "+", sf, false)
+	}
+	r, err := CrossValidate(folds, newMathScoringFunc, newGeneration, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Folds) != 4 {
+		t.Fatalf("CrossValidate = %v folds, want 4", len(r.Folds))
+	}
+	for i, f := range r.Folds {
+		if len(f.History) == 0 {
+			t.Errorf("fold %v: no history", i)
+		}
+		if f.Best == nil {
+			t.Errorf("fold %v: no best genome", i)
+		}
+	}
+	if got, want := r.Gap(), r.MeanTrainingScore()-r.MeanValidationScore(); got != want {
+		t.Errorf("Gap = %v, want %v", got, want)
+	}
+	if lines := strings.Split(r.String(), "\n"); len(lines) != 6 {
+		t.Errorf("String() = %v lines, want 6:\n%v", len(lines), r)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package transform
+
+// Encoding determines how the nominal values of a categorical input are encoded as numbers.
+type Encoding int
+
+const (
+	// Ordinal encodes each nominal value by its index in order of first appearance.
+	Ordinal Encoding = iota
+	// TargetMean encodes each nominal value by the mean target value of
+	// the training rows having that value.
+	TargetMean
+)
+
+// Categories maps the nominal values of a categorical input to numbers.
+// Values that are not listed encode to 0, which matches the default case
+// of the TransformCategoricalInputs function of the generated code.
+type Categories struct {
+	Names  []string
+	Values []float64
+}
+
+// NewOrdinal returns the ordinal encoding of the nominal values.
+func NewOrdinal(names []string) *Categories {
+	c := &Categories{Names: names, Values: make([]float64, len(names))}
+	for i := range names {
+		c.Values[i] = float64(i)
+	}
+	return c
+}
+
+// NewTargetMean learns the target mean encoding of the nominal values
+// from the training rows, where values[i] has the target target[i].
+func NewTargetMean(names []string, values []string, target []float64) *Categories {
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		index[name] = i
+	}
+	c := &Categories{Names: names, Values: make([]float64, len(names))}
+	counts := make([]int, len(names))
+	for i, v := range values {
+		if j, ok := index[v]; ok {
+			c.Values[j] += target[i]
+			counts[j]++
+		}
+	}
+	for j, n := range counts {
+		if n > 0 {
+			c.Values[j] /= float64(n)
+		}
+	}
+	return c
+}
+
+// Encode returns the number encoding the nominal value.
+func (c *Categories) Encode(name string) float64 {
+	for i, v := range c.Names {
+		if v == name {
+			return c.Values[i]
+		}
+	}
+	return 0
+}
@@ -0,0 +1,226 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+// Package transform provides preprocessing pipelines that standardize or
+// normalize the inputs and target of a model. The statistics are learned
+// from the training data and the method names match the <transformations>
+// of the grammars so that the generated code can apply the same pipeline
+// to raw inputs. Categorical (nominal) inputs are encoded as numbers by
+// Categories.
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Method is the name of a data transformation in the grammar.
+type Method string
+
+const (
+	// None leaves the values unchanged.
+	None Method = ""
+	// Standardization transforms values to zero mean and unit standard deviation.
+	Standardization Method = "standardization"
+	// Normalization transforms values to the range 0 to 1.
+	Normalization Method = "normalization"
+	// MinMaxNormalization transforms values to the range NewMin to NewMax.
+	MinMaxNormalization Method = "minMaxNormalization"
+)
+
+// Stats holds the statistics of a single input or target learned from the training data.
+type Stats struct {
+	Average float64
+	Stdev   float64
+	Min     float64
+	Max     float64
+}
+
+// Pipeline transforms the inputs before they are fed to a model and
+// reverses the transformation of the target on the output of the model.
+//
+// The model is evolved to predict the transformed target, so the output
+// of the model is already in transformed units and ModelStats is the
+// identity for the target method (average 0 and standard deviation 1 for
+// Standardization, minimum 0 and maximum 1 for Normalization, and
+// minimum NewMin and maximum NewMax for MinMaxNormalization).
+type Pipeline struct {
+	// InputMethod is the transformation applied to all the inputs.
+	InputMethod Method
+	// Inputs holds the statistics of every input, indexed by terminal number.
+	Inputs []Stats
+	// TargetMethod is the transformation applied to the target.
+	TargetMethod Method
+	// Target holds the statistics of the target.
+	Target Stats
+	// NewMin and NewMax define the range used by MinMaxNormalization.
+	NewMin float64
+	NewMax float64
+	// Categorical holds the encoding of every categorical input, indexed
+	// by terminal number, and nil for numerical inputs. When any input is
+	// categorical, the generated code takes the raw inputs as strings.
+	Categorical []*Categories
+}
+
+// Option represents an option that can modify a Pipeline.
+type Option func(p *Pipeline)
+
+// WithRange sets the range used by MinMaxNormalization (by default -1 to 1).
+func WithRange(newMin, newMax float64) Option {
+	return func(p *Pipeline) {
+		p.NewMin = newMin
+		p.NewMax = newMax
+	}
+}
+
+// WithCategorical sets the encoding of the categorical inputs,
+// such as those returned by dataset.Categorical.
+func WithCategorical(encodings []*Categories) Option {
+	return func(p *Pipeline) {
+		p.Categorical = encodings
+	}
+}
+
+// HasCategorical reports whether any input is categorical.
+func (p *Pipeline) HasCategorical() bool {
+	for _, c := range p.Categorical {
+		if c != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Fit learns the statistics of every input and of the target from the
+// training fitness cases, such as those returned by dataset.MathCases.
+// A constant input is given a standard deviation of 1 and a range of 1
+// so that transforming it never divides by zero.
+func Fit(inputMethod, targetMethod Method, in [][]float64, target []float64, opts ...Option) (*Pipeline, error) {
+	if len(in) == 0 || len(in) != len(target) {
+		return nil, errors.New("transform.Fit: inputs and target must be non-empty and the same length")
+	}
+	for _, m := range []Method{inputMethod, targetMethod} {
+		if err := m.check(); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &Pipeline{
+		InputMethod:  inputMethod,
+		Inputs:       make([]Stats, len(in[0])),
+		TargetMethod: targetMethod,
+		NewMin:       -1,
+		NewMax:       1,
+	}
+	for _, f := range opts {
+		f(p)
+	}
+	if p.NewMin >= p.NewMax {
+		return nil, fmt.Errorf("transform.Fit: invalid range %v to %v", p.NewMin, p.NewMax)
+	}
+
+	column := make([]float64, len(in))
+	for j := range p.Inputs {
+		for i, row := range in {
+			if len(row) != len(p.Inputs) {
+				return nil, fmt.Errorf("transform.Fit: row %v has %v inputs, want %v", i, len(row), len(p.Inputs))
+			}
+			column[i] = row[j]
+		}
+		p.Inputs[j] = newStats(column)
+	}
+	p.Target = newStats(target)
+	return p, nil
+}
+
+func (m Method) check() error {
+	switch m {
+	case None, Standardization, Normalization, MinMaxNormalization:
+		return nil
+	default:
+		return fmt.Errorf("unknown transformation method %q", m)
+	}
+}
+
+func newStats(values []float64) Stats {
+	s := Stats{Min: values[0], Max: values[0]}
+	for _, v := range values {
+		s.Average += v
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+	}
+	s.Average /= float64(len(values))
+	for _, v := range values {
+		s.Stdev += (v - s.Average) * (v - s.Average)
+	}
+	s.Stdev = math.Sqrt(s.Stdev / float64(len(values)))
+	if s.Stdev == 0 {
+		s.Stdev = 1
+	}
+	if s.Max == s.Min {
+		s.Max = s.Min + 1
+	}
+	return s
+}
+
+// ModelStats returns the statistics of the model output that are used by
+// the reverse transformation of the target.
+func (p *Pipeline) ModelStats() Stats {
+	switch p.TargetMethod {
+	case MinMaxNormalization:
+		return Stats{Average: 0, Stdev: 1, Min: p.NewMin, Max: p.NewMax}
+	default:
+		return Stats{Average: 0, Stdev: 1, Min: 0, Max: 1}
+	}
+}
+
+func (p *Pipeline) apply(m Method, s Stats, v float64) float64 {
+	switch m {
+	case Standardization:
+		return (v - s.Average) / s.Stdev
+	case Normalization:
+		return (v - s.Min) / (s.Max - s.Min)
+	case MinMaxNormalization:
+		return (v-s.Min)/(s.Max-s.Min)*(p.NewMax-p.NewMin) + p.NewMin
+	default:
+		return v
+	}
+}
+
+// Transform returns a transformed copy of the raw inputs.
+func (p *Pipeline) Transform(in []float64) []float64 {
+	result := make([]float64, len(in))
+	for i, v := range in {
+		if i < len(p.Inputs) {
+			v = p.apply(p.InputMethod, p.Inputs[i], v)
+		}
+		result[i] = v
+	}
+	return result
+}
+
+// TransformTarget transforms a raw target value.
+func (p *Pipeline) TransformTarget(v float64) float64 {
+	return p.apply(p.TargetMethod, p.Target, v)
+}
+
+// ReverseTarget transforms the output of the model back to raw target units.
+func (p *Pipeline) ReverseTarget(v float64) float64 {
+	m := p.ModelStats()
+	t := p.Target
+	switch p.TargetMethod {
+	case Standardization:
+		v = (v - m.Average) / m.Stdev
+		return v*t.Stdev + t.Average
+	case Normalization:
+		v = (v - m.Min) / (m.Max - m.Min)
+		return v*(t.Max-t.Min) + t.Min
+	case MinMaxNormalization:
+		v = ((v-m.Min)/(m.Max-m.Min))*(p.NewMax-p.NewMin) + p.NewMin
+		return ((v-p.NewMin)/(p.NewMax-p.NewMin))*(t.Max-t.Min) + t.Min
+	default:
+		return v
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2014 Google Inc. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	trainIn     = [][]float64{{1, 5}, {2, 5}, {3, 5}, {6, 5}}
+	trainTarget = []float64{10, 20, 30, 60}
+)
+
+func TestFit(t *testing.T) {
+	p, err := Fit(Standardization, Normalization, trainIn, trainTarget)
+	if err != nil {
+		t.Fatalf("Fit: %v", err)
+	}
+	want := &Pipeline{
+		InputMethod: Standardization,
+		Inputs: []Stats{
+			{Average: 3, Stdev: math.Sqrt(3.5), Min: 1, Max: 6},
+			{Average: 5, Stdev: 1, Min: 5, Max: 6}, // constant input
+		},
+		TargetMethod: Normalization,
+		Target:       Stats{Average: 30, Stdev: math.Sqrt(350), Min: 10, Max: 60},
+		NewMin:       -1,
+		NewMax:       1,
+	}
+	if diff := cmp.Diff(want, p); diff != "" {
+		t.Errorf("Fit mismatch (-want +got):\n%v", diff)
+	}
+}
+
+func TestFitErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     [][]float64
+		target []float64
+		method Method
+		opts   []Option
+	}{
+		{name: "empty", method: Standardization},
+		{name: "length mismatch", in: trainIn, target: trainTarget[1:], method: Standardization},
+		{name: "ragged rows", in: [][]float64{{1, 2}, {3}}, target: []float64{1, 2}, method: Standardization},
+		{name: "unknown method", in: trainIn, target: trainTarget, method: "bogus"},
+		{name: "invalid range", in: trainIn, target: trainTarget, method: MinMaxNormalization, opts: []Option{WithRange(1, -1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Fit(tt.method, None, tt.in, tt.target, tt.opts...); err == nil {
+				t.Error("Fit: expected error")
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, m := range []Method{None, Standardization, Normalization, MinMaxNormalization} {
+		t.Run(string(m), func(t *testing.T) {
+			p, err := Fit(m, m, trainIn, trainTarget, WithRange(-2, 2))
+			if err != nil {
+				t.Fatalf("Fit: %v", err)
+			}
+			for _, v := range trainTarget {
+				if got := p.ReverseTarget(p.TransformTarget(v)); math.Abs(got-v) > 1e-9 {
+					t.Errorf("ReverseTarget(TransformTarget(%v)) = %v", v, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		method Method
+		want   []float64
+	}{
+		{method: None, want: []float64{6, 5}},
+		{method: Standardization, want: []float64{3 / math.Sqrt(3.5), 0}},
+		{method: Normalization, want: []float64{1, 0}},
+		{method: MinMaxNormalization, want: []float64{1, -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			p, err := Fit(tt.method, None, trainIn, trainTarget)
+			if err != nil {
+				t.Fatalf("Fit: %v", err)
+			}
+			in := []float64{6, 5}
+			if diff := cmp.Diff(tt.want, p.Transform(in)); diff != "" {
+				t.Errorf("Transform mismatch (-want +got):\n%v", diff)
+			}
+			if in[0] != 6 {
+				t.Errorf("Transform modified its input: %v", in)
+			}
+		})
+	}
+}
+
+func TestCategories(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	ordinal := NewOrdinal(names)
+	targetMean := NewTargetMean(names, []string{"a", "b", "a", "x"}, []float64{1, 5, 3, 100})
+
+	tests := []struct {
+		name string
+		c    *Categories
+		want []float64
+	}{
+		{name: "ordinal", c: ordinal, want: []float64{0, 1, 2, 0}},
+		{name: "target mean", c: targetMean, want: []float64{2, 5, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []float64
+			for _, v := range []string{"a", "b", "c", "unknown"} {
+				got = append(got, tt.c.Encode(v))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Encode mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+
+	p := &Pipeline{}
+	if p.HasCategorical() {
+		t.Error("HasCategorical = true, want false")
+	}
+	WithCategorical([]*Categories{nil, ordinal})(p)
+	if !p.HasCategorical() {
+		t.Error("HasCategorical = false, want true")
+	}
+}